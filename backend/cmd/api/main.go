@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
 	"net/http"
 	"os"
@@ -11,11 +12,21 @@ import (
 	"syscall"
 	"time"
 
+	"book_manager/backend/internal/accounts"
 	"book_manager/backend/internal/admininvitations"
 	"book_manager/backend/internal/adminusers"
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/aiusage"
+	"book_manager/backend/internal/apitokens"
+	"book_manager/backend/internal/auditlogs"
+	"book_manager/backend/internal/auth"
+	"book_manager/backend/internal/bookreports"
 	"book_manager/backend/internal/books"
+	"book_manager/backend/internal/calendar"
+	"book_manager/backend/internal/clientip"
 	"book_manager/backend/internal/config"
 	"book_manager/backend/internal/db"
+	"book_manager/backend/internal/emailchange"
 	"book_manager/backend/internal/favorites"
 	"book_manager/backend/internal/firebaseauth"
 	"book_manager/backend/internal/follows"
@@ -24,12 +35,18 @@ import (
 	"book_manager/backend/internal/middleware"
 	"book_manager/backend/internal/nexttobuy"
 	"book_manager/backend/internal/openaikeys"
+	"book_manager/backend/internal/prompts"
+	"book_manager/backend/internal/readnext"
 	"book_manager/backend/internal/recommendations"
+	"book_manager/backend/internal/releases"
 	"book_manager/backend/internal/reports"
 	"book_manager/backend/internal/repository"
 	"book_manager/backend/internal/repository/gormrepo"
 	"book_manager/backend/internal/router"
+	"book_manager/backend/internal/secrets"
 	"book_manager/backend/internal/series"
+	"book_manager/backend/internal/sessions"
+	"book_manager/backend/internal/spending"
 	"book_manager/backend/internal/userbooks"
 	"book_manager/backend/internal/users"
 
@@ -51,14 +68,34 @@ func main() {
 		nextToBuyRepo       repository.NextToBuyRepository
 		recommendationRepo  repository.RecommendationRepository
 		isbnCacheRepo       repository.IsbnCacheRepository
+		seriesGuessRepo     repository.SeriesGuessCacheRepository
+		openAIUsageRepo     repository.OpenAIUsageRepository
 		auditLogRepo        repository.AuditLogRepository
 		seriesRepo          repository.SeriesRepository
 		openAIKeyRepo       repository.OpenAIKeyRepository
+		promptRepo          repository.PromptRepository
 		adminInvitationRepo repository.AdminInvitationRepository
 		adminUserRepo       repository.AdminUserRepository
+		releaseRepo         repository.ReleaseRepository
+		calendarTokenRepo   repository.CalendarTokenRepository
+		refreshTokenRepo    repository.RefreshTokenRepository
+		verificationRepo    repository.EmailVerificationRepository
+		sessionRepo         repository.SessionRepository
+		apiTokenRepo        repository.APITokenRepository
+		emailChangeRepo     repository.EmailChangeRepository
+		suspensionRepo      repository.AccountSuspensionRepository
+		accountDataRepo     repository.AccountDataRepository
+		bookReportRepo      repository.BookReportRepository
 	)
 
 	if cfg.DatabaseURL != "" {
+		cipher, err := secrets.ParseMasterKeys(cfg.SecretsMasterKeys)
+		if err != nil {
+			log.Fatalf("secrets master key error: %v", err)
+		}
+		if cipher == nil {
+			log.Println("WARNING: SECRETS_MASTER_KEYS is not set, API keys are stored in plaintext")
+		}
 		dbConn, err := db.Open(cfg.DatabaseURL)
 		if err != nil {
 			log.Fatalf("db connection error: %v", err)
@@ -73,28 +110,60 @@ func main() {
 				&gormrepo.NextToBuyManual{},
 				&gormrepo.Recommendation{},
 				&gormrepo.IsbnCache{},
+				&gormrepo.SeriesGuessCache{},
 				&gormrepo.AuditLog{},
 				&gormrepo.Series{},
 				&gormrepo.OpenAIKey{},
+				&gormrepo.OpenAIUsage{},
+				&gormrepo.Prompt{},
 				&gormrepo.AdminInvitation{},
 				&gormrepo.AdminUser{},
+				&gormrepo.Release{},
+				&gormrepo.CalendarToken{},
+				&gormrepo.RefreshToken{},
+				&gormrepo.EmailVerification{},
+				&gormrepo.Session{},
+				&gormrepo.APIToken{},
+				&gormrepo.EmailChange{},
+				&gormrepo.AccountSuspension{},
+				&gormrepo.BookReport{},
 			); err != nil {
 				log.Fatalf("db migrate error: %v", err)
 			}
+			if err := gormrepo.MigrateCalendarTokenHashes(dbConn, calendar.HashToken); err != nil {
+				log.Fatalf("calendar token migrate error: %v", err)
+			}
+		}
+		// マスターキーの設定漏れ・誤りのまま起動すると暗号化済みのキーが使えないため起動を止める
+		if err := gormrepo.CheckSecrets(dbConn, cipher); err != nil {
+			log.Fatalf("secrets check error: %v (check SECRETS_MASTER_KEYS)", err)
 		}
 		userRepo = gormrepo.NewUserRepository(dbConn)
 		bookRepo = gormrepo.NewBookRepository(dbConn)
 		userBookRepo = gormrepo.NewUserBookRepository(dbConn)
-		profileRepo = gormrepo.NewProfileSettingsRepository(dbConn)
+		profileRepo = gormrepo.NewProfileSettingsRepository(dbConn, cipher)
 		favoriteRepo = gormrepo.NewFavoriteRepository(dbConn)
 		nextToBuyRepo = gormrepo.NewNextToBuyRepository(dbConn)
 		recommendationRepo = gormrepo.NewRecommendationRepository(dbConn)
 		isbnCacheRepo = gormrepo.NewIsbnCacheRepository(dbConn)
+		seriesGuessRepo = gormrepo.NewSeriesGuessCacheRepository(dbConn)
+		openAIUsageRepo = gormrepo.NewOpenAIUsageRepository(dbConn)
 		auditLogRepo = gormrepo.NewAuditLogRepository(dbConn)
 		seriesRepo = gormrepo.NewSeriesRepository(dbConn)
-		openAIKeyRepo = gormrepo.NewOpenAIKeyRepository(dbConn)
+		openAIKeyRepo = gormrepo.NewOpenAIKeyRepository(dbConn, cipher)
+		promptRepo = gormrepo.NewPromptRepository(dbConn)
 		adminInvitationRepo = gormrepo.NewAdminInvitationRepository(dbConn)
 		adminUserRepo = gormrepo.NewAdminUserRepository(dbConn)
+		releaseRepo = gormrepo.NewReleaseRepository(dbConn)
+		calendarTokenRepo = gormrepo.NewCalendarTokenRepository(dbConn)
+		refreshTokenRepo = gormrepo.NewRefreshTokenRepository(dbConn)
+		verificationRepo = gormrepo.NewEmailVerificationRepository(dbConn)
+		sessionRepo = gormrepo.NewSessionRepository(dbConn)
+		apiTokenRepo = gormrepo.NewAPITokenRepository(dbConn)
+		emailChangeRepo = gormrepo.NewEmailChangeRepository(dbConn)
+		suspensionRepo = gormrepo.NewAccountSuspensionRepository(dbConn)
+		accountDataRepo = gormrepo.NewAccountDataRepository(dbConn)
+		bookReportRepo = gormrepo.NewBookReportRepository(dbConn)
 	} else {
 		userRepo = repository.NewMemoryUserRepository()
 		bookRepo = repository.NewMemoryBookRepository()
@@ -104,11 +173,45 @@ func main() {
 		nextToBuyRepo = repository.NewMemoryNextToBuyRepository()
 		recommendationRepo = repository.NewMemoryRecommendationRepository()
 		isbnCacheRepo = repository.NewMemoryIsbnCacheRepository()
-		auditLogRepo = repository.NewMemoryAuditLogRepository()
+		seriesGuessRepo = repository.NewMemorySeriesGuessCacheRepository()
+		openAIUsageRepo = repository.NewMemoryOpenAIUsageRepository()
+		memoryAuditLogRepo := repository.NewMemoryAuditLogRepository()
+		auditLogRepo = memoryAuditLogRepo
 		seriesRepo = repository.NewMemorySeriesRepository()
 		openAIKeyRepo = repository.NewMemoryOpenAIKeyRepository()
+		promptRepo = repository.NewMemoryPromptRepository()
 		adminInvitationRepo = repository.NewMemoryAdminInvitationRepository()
 		adminUserRepo = repository.NewMemoryAdminUserRepository()
+		releaseRepo = repository.NewMemoryReleaseRepository()
+		calendarTokenRepo = repository.NewMemoryCalendarTokenRepository()
+		memoryRefreshTokenRepo := repository.NewMemoryRefreshTokenRepository()
+		refreshTokenRepo = memoryRefreshTokenRepo
+		verificationRepo = repository.NewMemoryEmailVerificationRepository()
+		memorySessionRepo := repository.NewMemorySessionRepository()
+		sessionRepo = memorySessionRepo
+		memoryAPITokenRepo := repository.NewMemoryAPITokenRepository()
+		apiTokenRepo = memoryAPITokenRepo
+		emailChangeRepo = repository.NewMemoryEmailChangeRepository()
+		suspensionRepo = repository.NewMemoryAccountSuspensionRepository()
+		bookReportRepo = repository.NewMemoryBookReportRepository()
+		accountDataRepo = repository.NewMemoryAccountDataRepository(
+			userRepo,
+			profileRepo,
+			userBookRepo,
+			favoriteRepo,
+			nextToBuyRepo,
+			recommendationRepo,
+			calendarTokenRepo,
+			verificationRepo,
+			memoryAuditLogRepo,
+			memoryRefreshTokenRepo,
+			memorySessionRepo,
+			memoryAPITokenRepo,
+			emailChangeRepo,
+			suspensionRepo,
+			bookReportRepo,
+			adminUserRepo,
+		)
 	}
 	isbnCacheTTL := time.Duration(cfg.IsbnCacheTTLMinutes) * time.Minute
 	isbnService := isbn.NewService(cfg.GoogleBooksBaseURL, cfg.GoogleBooksAPIKey, isbnCacheTTL, isbnCacheRepo)
@@ -127,35 +230,57 @@ func main() {
 		From: cfg.SMTPFrom,
 	}, cfg.TemplatesDir, cfg.FrontendURL)
 	seriesService := series.NewService(seriesRepo)
-	openAIKeyService := openaikeys.NewService(openAIKeyRepo)
-	if cfg.FirebaseAPIKey == "" {
-		log.Println("WARNING: FIREBASE_API_KEY is not set, authentication features will not work")
-	}
-	if cfg.FirebaseProjectID == "" {
-		log.Println("WARNING: FIREBASE_PROJECT_ID is not set, token verification will not work")
-	}
-	firebaseClient := firebaseauth.NewClient(cfg.FirebaseAPIKey)
-	firebaseVerifier := firebaseauth.NewVerifier(cfg.FirebaseProjectID)
-	var firebaseAdmin *firebaseauth.AdminClient
-	if cfg.FirebaseClientEmail != "" && cfg.FirebasePrivateKey != "" {
-		var err error
-		firebaseAdmin, err = firebaseauth.NewAdminClient(context.Background(), firebaseauth.AdminCredentials{
-			ProjectID:   cfg.FirebaseProjectID,
-			ClientEmail: cfg.FirebaseClientEmail,
-			PrivateKey:  cfg.FirebasePrivateKey,
-		})
-		if err != nil {
-			log.Printf("firebase admin client init error: %v", err)
-		} else {
-			log.Println("firebase admin client initialized")
-		}
-	} else {
-		log.Println("WARNING: Firebase Admin SDK credentials not configured, admin features will be limited")
+	openAIKeyService := openaikeys.NewService(openAIKeyRepo, cfg.OpenAIKeyStrategy, time.Duration(cfg.OpenAIKeyCooldownSec)*time.Second)
+	releaseService := releases.NewService(releaseRepo, isbnService, favoriteRepo, seriesRepo, userBookRepo, bookRepo)
+	calendarService := calendar.NewService(calendarTokenRepo)
+	spendingService := spending.NewService(userBookRepo, bookRepo, seriesRepo)
+	seriesGuessCache := ai.NewGuessCache(seriesGuessRepo)
+	aiUsageService := aiusage.NewService(openAIUsageRepo, cfg.OpenAIUserDailyTokens)
+	promptsService := prompts.NewService(promptRepo, aiPrompt)
+	aiBreaker := ai.NewCircuitBreaker(cfg.OpenAIBreakerFailures, time.Duration(cfg.OpenAIBreakerOpenSec)*time.Second)
+	aiMetrics := ai.NewMetrics()
+	sessionsService := sessions.NewService(sessionRepo)
+	apiTokensService := apitokens.NewService(apiTokenRepo)
+	emailChangeService := emailchange.NewService(emailChangeRepo, userRepo)
+	auditLogsService := auditlogs.NewService(auditLogRepo)
+	bookReportsService := bookreports.NewService(bookReportRepo)
+	readNextService := readnext.NewService(userBookRepo, bookRepo, seriesRepo, favoriteRepo, recommendationRepo, isbnService)
+	var authBackend auth.Backend
+	switch cfg.AuthMode {
+	case auth.ModeLocal:
+		localAuth := newLocalAuth(cfg, userRepo, refreshTokenRepo, verificationRepo, reportsService)
+		go startRefreshTokenCleanup(localAuth)
+		authBackend = localAuth
+		log.Println("auth backend: local")
+	case auth.ModeFirebase:
+		authBackend = newFirebaseAuth(cfg)
+		log.Println("auth backend: firebase")
+	default:
+		log.Fatalf("unknown AUTH_MODE: %q (expected %q or %q)", cfg.AuthMode, auth.ModeFirebase, auth.ModeLocal)
 	}
-	firebaseMiddleware := middleware.NewFirebaseAuthMiddleware(firebaseVerifier, usersService)
-	if firebaseVerifier == nil {
-		log.Println("WARNING: Firebase auth middleware initialized without verifier")
+	// ローカル認証のアカウントは同じ DB にあり、削除のトランザクションで一緒に削除される
+	var deleteAuthUser accounts.DeleteAuthUserFunc
+	if authBackend.Mode() == auth.ModeFirebase {
+		deleteAuthUser = authBackend.DeleteUser
 	}
+	accountsService := accounts.NewService(
+		accountDataRepo,
+		userRepo,
+		profileRepo,
+		userBookRepo,
+		bookRepo,
+		favoriteRepo,
+		nextToBuyRepo,
+		recommendationRepo,
+		calendarTokenRepo,
+		auditLogRepo,
+		sessionRepo,
+		apiTokenRepo,
+		suspensionRepo,
+		followsService,
+		deleteAuthUser,
+	)
+	authMiddleware := middleware.NewAuthMiddleware(authBackend, usersService, apiTokensService, accountsService)
 	if count := normalizeBooks(bookService); count > 0 {
 		log.Printf("normalized %d book titles", count)
 	}
@@ -166,13 +291,10 @@ func main() {
 	adminUsersService := adminusers.NewService(adminUserRepo, adminUserIDs)
 	adminInvitationsService := admininvitations.NewService(
 		adminInvitationRepo,
-		adminUsersService.IsAdmin,
 		usersService.IsUserIDTaken,
 	)
 	h := handler.New(
-		firebaseClient,
-		firebaseVerifier,
-		firebaseAdmin,
+		authBackend,
 		isbnService,
 		bookService,
 		userBookService,
@@ -186,14 +308,33 @@ func main() {
 		openAIKeyService,
 		adminInvitationsService,
 		adminUsersService,
+		releaseService,
+		calendarService,
+		spendingService,
+		seriesGuessCache,
+		aiUsageService,
+		readNextService,
+		aiBreaker,
+		aiMetrics,
+		cfg.OpenAIBaseURL,
 		cfg.OpenAIAPIKey,
 		cfg.OpenAIDefaultModel,
-		aiPrompt,
+		promptsService,
+		sessionsService,
+		apiTokensService,
+		accountsService,
+		emailChangeService,
+		auditLogsService,
+		bookReportsService,
 	)
 	if !cfg.AuditLogEnabled {
 		auditLogRepo = nil
 	}
-	r := router.New(h, auditLogRepo, cfg.CORSAllowedOrigins, firebaseMiddleware.Wrap)
+	ipResolver, err := clientip.NewResolver(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("TRUSTED_PROXIES error: %v", err)
+	}
+	r := router.New(h, auditLogRepo, cfg.CORSAllowedOrigins, authMiddleware.Wrap, ipResolver)
 
 	server := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -209,6 +350,8 @@ func main() {
 	}()
 
 	go startAuditCleanup(auditLogRepo)
+	go startReleaseRefresh(releaseService, time.Duration(cfg.ReleaseRefreshHours)*time.Hour)
+	go startInvitationSweep(adminInvitationsService)
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -264,6 +407,111 @@ func normalizeBooks(bookService *books.Service) int {
 	return updated
 }
 
+func newFirebaseAuth(cfg config.Config) *auth.FirebaseBackend {
+	emulator := strings.TrimSpace(cfg.FirebaseEmulatorHost) != ""
+	// エミュレーターでは署名なしの ID トークンを受け付けるため、明示的に許可され、APP_ENV も明示的に local / test のときだけ有効にする
+	if emulator && (!cfg.FirebaseEmulatorOptIn || !cfg.IsDevEnv()) {
+		log.Fatal("FIREBASE_AUTH_EMULATOR_HOST is set; the emulator requires FIREBASE_AUTH_EMULATOR_ALLOWED=true and APP_ENV explicitly set to local or test")
+	}
+	if cfg.FirebaseAPIKey == "" && !emulator {
+		log.Println("WARNING: FIREBASE_API_KEY is not set, authentication features will not work")
+	}
+	if cfg.FirebaseProjectID == "" {
+		log.Println("WARNING: FIREBASE_PROJECT_ID is not set, token verification will not work")
+	}
+	firebaseClient := firebaseauth.NewClient(cfg.FirebaseAPIKey)
+	firebaseVerifier := firebaseauth.NewVerifier(cfg.FirebaseProjectID)
+	if emulator {
+		// エミュレーターの ID トークンは署名されていないため、検証を緩めることを明示的に警告する
+		log.Printf("WARNING: ==== FIREBASE AUTH EMULATOR MODE (%s): unsigned ID tokens are accepted. Never use this in production ====", cfg.FirebaseEmulatorHost)
+		firebaseClient.UseEmulator(cfg.FirebaseEmulatorHost)
+		firebaseVerifier.UseEmulator()
+	}
+	if cfg.FirebaseKeysFile != "" {
+		data, err := os.ReadFile(filepath.Clean(cfg.FirebaseKeysFile))
+		if err != nil {
+			log.Fatalf("firebase key set read error: %v", err)
+		}
+		keys, err := firebaseauth.LoadKeySet(data)
+		if err != nil {
+			log.Fatalf("firebase key set error: %v", err)
+		}
+		firebaseVerifier.SetStaticKeys(keys)
+		log.Printf("firebase token verification uses %d static keys from %s", len(keys), cfg.FirebaseKeysFile)
+	}
+	var firebaseAdmin *firebaseauth.AdminClient
+	if emulator && (cfg.FirebaseClientEmail == "" || cfg.FirebasePrivateKey == "") {
+		var err error
+		firebaseAdmin, err = firebaseauth.NewEmulatorAdminClient(context.Background(), cfg.FirebaseProjectID)
+		if err != nil {
+			log.Printf("firebase admin client init error: %v", err)
+		} else {
+			log.Println("firebase admin client initialized for emulator")
+		}
+	} else if cfg.FirebaseClientEmail != "" && cfg.FirebasePrivateKey != "" {
+		var err error
+		firebaseAdmin, err = firebaseauth.NewAdminClient(context.Background(), firebaseauth.AdminCredentials{
+			ProjectID:   cfg.FirebaseProjectID,
+			ClientEmail: cfg.FirebaseClientEmail,
+			PrivateKey:  cfg.FirebasePrivateKey,
+		})
+		if err != nil {
+			log.Printf("firebase admin client init error: %v", err)
+		} else {
+			log.Println("firebase admin client initialized")
+		}
+	} else {
+		log.Println("WARNING: Firebase Admin SDK credentials not configured, admin features will be limited")
+	}
+	return auth.NewFirebaseBackend(firebaseClient, firebaseVerifier, firebaseAdmin)
+}
+
+func newLocalAuth(
+	cfg config.Config,
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	verificationRepo repository.EmailVerificationRepository,
+	reportsService *reports.Service,
+) *auth.Service {
+	secret := []byte(cfg.AuthJWTSecret)
+	if len(secret) < 32 {
+		if !cfg.IsDevEnv() {
+			log.Fatal("AUTH_JWT_SECRET must be at least 32 bytes when AUTH_MODE=local unless APP_ENV is explicitly set to local or test")
+		}
+		// APP_ENV を明示した開発・テスト環境では起動ごとにランダムな鍵を使う（再起動でアクセストークンは無効になる）
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("jwt secret generation error: %v", err)
+		}
+		log.Println("WARNING: AUTH_JWT_SECRET is not set, using a random secret for this process")
+	}
+	sendVerification := func(to, userID, token string, expiresAt time.Time) {
+		reportsService.SendEmailVerification(reports.EmailVerificationEmail{
+			To:        to,
+			UserID:    userID,
+			Token:     token,
+			ExpiresAt: expiresAt,
+		})
+	}
+	return auth.NewService(userRepo, refreshTokenRepo, verificationRepo, sendVerification, auth.Options{
+		Secret:     secret,
+		AccessTTL:  time.Duration(cfg.AuthAccessTTLMinutes) * time.Minute,
+		RefreshTTL: time.Duration(cfg.AuthRefreshTTLDays) * 24 * time.Hour,
+	})
+}
+
+func startRefreshTokenCleanup(service *auth.Service) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if count := service.PurgeExpired(); count > 0 {
+			log.Printf("purged %d expired refresh tokens", count)
+		}
+		<-ticker.C
+	}
+}
+
 func startAuditCleanup(repo repository.AuditLogRepository) {
 	if repo == nil {
 		return
@@ -277,3 +525,30 @@ func startAuditCleanup(repo repository.AuditLogRepository) {
 		<-ticker.C
 	}
 }
+
+func startInvitationSweep(service *admininvitations.Service) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if count := service.SweepExpired(); count > 0 {
+			log.Printf("purged %d expired admin invitations", count)
+		}
+		<-ticker.C
+	}
+}
+
+func startReleaseRefresh(service *releases.Service, interval time.Duration) {
+	if service == nil || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count := service.Refresh(); count > 0 {
+			log.Printf("refreshed %d upcoming releases", count)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/db"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/prompts"
+	"book_manager/backend/internal/repository/gormrepo"
+
+	"github.com/joho/godotenv"
+)
+
+type candidate struct {
+	label   string
+	content string
+}
+
+// eval-prompts はラベル付きのフィクスチャをプロンプトとモデルの組み合わせごとに推定し、
+// シリーズ名・巻数の正解率を表示します。
+func main() {
+	_ = godotenv.Load()
+	_ = godotenv.Load("../.env")
+
+	fixturesPath := flag.String("fixtures", "eval/series_fixtures.json", "labelled fixture file (JSON array)")
+	modelsFlag := flag.String("models", "", "comma-separated models (default: OPENAI_DEFAULT_MODEL)")
+	promptsFlag := flag.String("prompts", "file,active", "comma-separated prompts: file, active, all or prompt IDs (DB prompts need DATABASE_URL)")
+	promptFile := flag.String("prompt-file", "prompt.md", "prompt file used for \"file\"")
+	includeLocal := flag.Bool("local", false, "also evaluate the rule-based local classifier")
+	verbose := flag.Bool("v", false, "print mismatches")
+	flag.Parse()
+
+	fixtures, err := prompts.LoadFixtures(*fixturesPath)
+	if err != nil {
+		log.Fatalf("fixtures load error: %v", err)
+	}
+	if len(fixtures) == 0 {
+		log.Fatal("no fixtures")
+	}
+	candidates, err := loadCandidates(*promptsFlag, *promptFile)
+	if err != nil {
+		log.Fatalf("prompt load error: %v", err)
+	}
+	models := splitList(*modelsFlag)
+	if len(models) == 0 {
+		models = []string{envOr("OPENAI_DEFAULT_MODEL", "gpt-4o-mini")}
+	}
+
+	ctx := context.Background()
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "PROMPT\tMODEL\tN\tSERIES\tVOLUME\tBOTH\tERRORS")
+	mismatches := make([]string, 0)
+	report := func(label, model string, score prompts.Score) {
+		fmt.Fprintf(out, "%s\t%s\t%d\t%.1f%%\t%.1f%%\t%.1f%%\t%d\n",
+			label, model, score.Total, score.SeriesAccuracy()*100, score.VolumeAccuracy()*100, score.Accuracy()*100, score.Errors)
+		for _, mismatch := range score.Mismatches {
+			mismatches = append(mismatches, fmt.Sprintf("[%s %s] %s", label, model, mismatch))
+		}
+	}
+	if *includeLocal {
+		report("local", "-", prompts.Evaluate(ctx, ai.NewLocalClassifier(nil), fixtures))
+	}
+	baseURL := envOr("OPENAI_BASE_URL", ai.DefaultBaseURL)
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	for _, prompt := range candidates {
+		for _, model := range models {
+			client := ai.NewOpenAIClient(baseURL, apiKey, model, prompt.content)
+			if !client.Available() {
+				log.Fatal("OPENAI_API_KEY is required unless OPENAI_BASE_URL points to a compatible server")
+			}
+			report(prompt.label, model, prompts.Evaluate(ctx, client, fixtures))
+		}
+	}
+	_ = out.Flush()
+	if *verbose && len(mismatches) > 0 {
+		fmt.Println()
+		for _, mismatch := range mismatches {
+			fmt.Println(mismatch)
+		}
+	}
+}
+
+func loadCandidates(spec, promptFile string) ([]candidate, error) {
+	var stored []domain.Prompt
+	loadedStored := false
+	storedPrompts := func() ([]domain.Prompt, error) {
+		if loadedStored {
+			return stored, nil
+		}
+		databaseURL := os.Getenv("DATABASE_URL")
+		if databaseURL == "" {
+			return nil, fmt.Errorf("DATABASE_URL is required for stored prompts")
+		}
+		dbConn, err := db.Open(databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		stored = gormrepo.NewPromptRepository(dbConn).List()
+		loadedStored = true
+		return stored, nil
+	}
+
+	candidates := make([]candidate, 0)
+	for _, name := range splitList(spec) {
+		switch name {
+		case "file":
+			data, err := os.ReadFile(filepath.Clean(promptFile))
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, candidate{label: "file:" + filepath.Base(promptFile), content: string(data)})
+		case "active", "all":
+			items, err := storedPrompts()
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				if name == "all" || item.Active {
+					candidates = append(candidates, promptCandidate(item))
+				}
+			}
+		default:
+			items, err := storedPrompts()
+			if err != nil {
+				return nil, err
+			}
+			found := false
+			for _, item := range items {
+				if item.ID == name {
+					candidates = append(candidates, promptCandidate(item))
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("prompt %q not found", name)
+			}
+		}
+	}
+	return candidates, nil
+}
+
+func promptCandidate(item domain.Prompt) candidate {
+	return candidate{label: fmt.Sprintf("v%d", item.Version), content: item.Content}
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+func envOr(key, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
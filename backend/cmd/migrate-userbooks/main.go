@@ -124,7 +124,19 @@ func cleanupAllTables(dbConn *gorm.DB) {
 		"admin_users",
 		"profile_settings",
 		"open_ai_keys",
+		"open_ai_usages",
+		"prompts",
 		"isbn_caches",
+		"series_guess_caches",
+		"releases",
+		"calendar_tokens",
+		"refresh_tokens",
+		"email_verifications",
+		"sessions",
+		"api_tokens",
+		"email_changes",
+		"account_suspensions",
+		"book_reports",
 		"users",
 	}
 	deleteFromTables(dbConn, tables)
@@ -141,6 +153,9 @@ func cleanupDataTables(dbConn *gorm.DB) {
 		"series",
 		"audit_logs",
 		"isbn_caches",
+		"series_guess_caches",
+		"open_ai_usages",
+		"releases",
 	}
 	deleteFromTables(dbConn, tables)
 }
@@ -161,7 +176,19 @@ func deleteFromTables(dbConn *gorm.DB, tables []string) {
 		"admin_users":       {},
 		"profile_settings":  {},
 		"open_ai_keys":      {},
+		"open_ai_usages":    {},
+		"prompts":           {},
 		"isbn_caches":       {},
+		"series_guess_caches": {},
+		"releases":          {},
+		"calendar_tokens":   {},
+		"refresh_tokens":    {},
+		"email_verifications": {},
+		"sessions":          {},
+		"api_tokens":        {},
+		"email_changes":     {},
+		"account_suspensions": {},
+		"book_reports":      {},
 		"users":             {},
 	}
 	for _, table := range tables {
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"book_manager/backend/internal/db"
+	"book_manager/backend/internal/repository/gormrepo"
+	"book_manager/backend/internal/secrets"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+// reencrypt-secrets は保存済みの API キーを SECRETS_MASTER_KEYS の先頭のマスターキーで暗号化し直します。
+// マスターキーを入れ替える場合は、新しいキーを先頭に、古いキーを後ろに並べて実行します。
+// 平文で保存されている値も暗号化されます。
+func main() {
+	_ = godotenv.Load()
+	_ = godotenv.Load("../.env")
+
+	dryRun := flag.Bool("dry-run", false, "report the number of values to re-encrypt without writing")
+	generateKey := flag.Bool("generate-key", false, "print a new random master key and exit")
+	flag.Parse()
+
+	if *generateKey {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			log.Fatalf("generate key error: %v", err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+	cipher, err := secrets.ParseMasterKeys(os.Getenv("SECRETS_MASTER_KEYS"))
+	if err != nil {
+		log.Fatalf("secrets master key error: %v", err)
+	}
+	if cipher == nil {
+		log.Fatal("SECRETS_MASTER_KEYS environment variable is required")
+	}
+
+	dbConn, err := db.Open(databaseURL)
+	if err != nil {
+		log.Fatalf("db connection error: %v", err)
+	}
+
+	var keys, settings int
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if keys, err = reencryptOpenAIKeys(tx, cipher, *dryRun); err != nil {
+			return err
+		}
+		settings, err = reencryptProfileSettings(tx, cipher, *dryRun)
+		return err
+	})
+	if err != nil {
+		log.Fatalf("re-encrypt error: %v", err)
+	}
+	if *dryRun {
+		fmt.Printf("dry run: %d openai keys and %d profile settings would be re-encrypted with key %q\n", keys, settings, cipher.CurrentKeyID())
+		return
+	}
+	fmt.Printf("re-encrypted %d openai keys and %d profile settings with key %q\n", keys, settings, cipher.CurrentKeyID())
+}
+
+func reencryptOpenAIKeys(tx *gorm.DB, cipher *secrets.Cipher, dryRun bool) (int, error) {
+	var models []gormrepo.OpenAIKey
+	if err := tx.Find(&models).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, model := range models {
+		if !cipher.NeedsRotation(model.APIKey) {
+			continue
+		}
+		value, err := reencrypt(cipher, model.APIKey)
+		if err != nil {
+			return 0, fmt.Errorf("openai key %s: %w", model.ID, err)
+		}
+		count++
+		if dryRun {
+			continue
+		}
+		if err := tx.Model(&model).Update("APIKey", value).Error; err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+func reencryptProfileSettings(tx *gorm.DB, cipher *secrets.Cipher, dryRun bool) (int, error) {
+	var models []gormrepo.ProfileSettings
+	if err := tx.Find(&models).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, model := range models {
+		if !cipher.NeedsRotation(model.OpenAIAPIKey) {
+			continue
+		}
+		value, err := reencrypt(cipher, model.OpenAIAPIKey)
+		if err != nil {
+			return 0, fmt.Errorf("profile settings %s: %w", model.UserID, err)
+		}
+		count++
+		if dryRun {
+			continue
+		}
+		if err := tx.Model(&model).Update("OpenAIAPIKey", value).Error; err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+func reencrypt(cipher *secrets.Cipher, value string) (string, error) {
+	plain, err := cipher.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return cipher.Encrypt(plain)
+}
@@ -0,0 +1,190 @@
+package accounts
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidStatus = errors.New("invalid status")
+	ErrNotSuspended  = errors.New("account is not suspended")
+	ErrReasonTooLong = errors.New("suspension reason too long")
+)
+
+// 管理画面のアカウント一覧の並び順です。
+const (
+	SortUserID        = "userId"
+	SortLibraryCount  = "libraryCount"
+	SortLastActiveAt  = "lastActiveAt"
+	SortEmailVerified = "emailVerified"
+)
+
+// 管理画面のアカウント一覧の状態による絞り込みです。
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+)
+
+const ReasonMaxLength = 500
+
+// Query は管理画面のアカウント一覧の検索条件です。
+type Query struct {
+	Search string // UserID・メールアドレス・表示名の部分一致
+	Status string // active / suspended（空はすべて）
+	Sort   string
+	Desc   bool
+}
+
+// Summary は管理画面に表示するアカウントの概要です。
+type Summary struct {
+	User         domain.User
+	LibraryCount int
+	LastActiveAt *time.Time // 監査ログの最新の日時（記録がなければ nil）
+	Suspension   *domain.AccountSuspension
+}
+
+// Search は条件に一致するアカウントの概要を並び替えて返します。
+// 所蔵数と最終利用日時は一覧全体で一度だけ集計します。
+func (s *Service) Search(query Query) ([]Summary, error) {
+	switch query.Sort {
+	case "":
+		query.Sort = SortUserID
+	case SortUserID, SortLibraryCount, SortLastActiveAt, SortEmailVerified:
+	default:
+		return nil, ErrInvalidSort
+	}
+	if query.Status != "" && query.Status != StatusActive && query.Status != StatusSuspended {
+		return nil, ErrInvalidStatus
+	}
+	counts := s.userBooks.CountByUser()
+	lastActive := s.auditLogs.LastActivityByUser()
+	suspensions := make(map[string]domain.AccountSuspension)
+	for _, item := range s.suspensions.List() {
+		suspensions[item.UserID] = item
+	}
+
+	search := strings.ToLower(strings.TrimSpace(query.Search))
+	items := make([]Summary, 0)
+	for _, user := range s.users.List() {
+		if search != "" &&
+			!strings.Contains(strings.ToLower(user.UserID), search) &&
+			!strings.Contains(strings.ToLower(user.Email), search) &&
+			!strings.Contains(strings.ToLower(user.DisplayName), search) {
+			continue
+		}
+		summary := Summary{User: user, LibraryCount: counts[user.ID]}
+		if at, ok := lastActive[user.ID]; ok {
+			summary.LastActiveAt = &at
+		}
+		if suspension, ok := suspensions[user.ID]; ok {
+			summary.Suspension = &suspension
+		}
+		if query.Status == StatusActive && summary.Suspension != nil ||
+			query.Status == StatusSuspended && summary.Suspension == nil {
+			continue
+		}
+		items = append(items, summary)
+	}
+	sortSummaries(items, query.Sort, query.Desc)
+	return items, nil
+}
+
+// Summary はユーザー 1 件の概要を返します。
+func (s *Service) Summary(userID string) (Summary, error) {
+	user, ok := s.users.FindByID(userID)
+	if !ok {
+		return Summary{}, ErrUserNotFound
+	}
+	summary := Summary{
+		User:         user,
+		LibraryCount: len(s.userBooks.ListByUser(user.ID)),
+	}
+	for _, item := range s.auditLogs.ListByUser(user.ID) {
+		if summary.LastActiveAt == nil || item.CreatedAt.After(*summary.LastActiveAt) {
+			at := item.CreatedAt
+			summary.LastActiveAt = &at
+		}
+	}
+	if suspension, ok := s.suspensions.Get(user.ID); ok {
+		summary.Suspension = &suspension
+	}
+	return summary, nil
+}
+
+// Suspend はアカウントを利用停止にします。停止中に再度呼んだ場合は理由を更新します。
+func (s *Service) Suspend(userID, suspendedBy, reason string) (domain.AccountSuspension, error) {
+	if _, ok := s.users.FindByID(userID); !ok {
+		return domain.AccountSuspension{}, ErrUserNotFound
+	}
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > ReasonMaxLength {
+		return domain.AccountSuspension{}, ErrReasonTooLong
+	}
+	suspension := domain.AccountSuspension{
+		UserID:      userID,
+		Reason:      reason,
+		SuspendedBy: suspendedBy,
+		SuspendedAt: s.now(),
+	}
+	if err := s.suspensions.Save(suspension); err != nil {
+		return domain.AccountSuspension{}, err
+	}
+	return suspension, nil
+}
+
+// Unsuspend はアカウントの利用停止を解除します。
+func (s *Service) Unsuspend(userID string) error {
+	if _, ok := s.users.FindByID(userID); !ok {
+		return ErrUserNotFound
+	}
+	if !s.suspensions.Delete(userID) {
+		return ErrNotSuspended
+	}
+	return nil
+}
+
+// IsSuspended はアカウントが利用停止中かを返します。認証ミドルウェアがリクエストごとに呼び出します。
+func (s *Service) IsSuspended(userID string) bool {
+	_, ok := s.suspensions.Get(userID)
+	return ok
+}
+
+func sortSummaries(items []Summary, key string, desc bool) {
+	less := func(a, b Summary) bool {
+		switch key {
+		case SortLibraryCount:
+			if a.LibraryCount != b.LibraryCount {
+				return a.LibraryCount < b.LibraryCount
+			}
+		case SortLastActiveAt:
+			at, bt := lastActiveUnix(a), lastActiveUnix(b)
+			if at != bt {
+				return at < bt
+			}
+		case SortEmailVerified:
+			if a.User.EmailVerified != b.User.EmailVerified {
+				return !a.User.EmailVerified
+			}
+		}
+		return a.User.UserID < b.User.UserID
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+}
+
+// lastActiveUnix は並び替え用に最終利用日時を返します。記録のないユーザーは最も古い扱いにします。
+func lastActiveUnix(summary Summary) int64 {
+	if summary.LastActiveAt == nil {
+		return 0
+	}
+	return summary.LastActiveAt.Unix()
+}
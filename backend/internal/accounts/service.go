@@ -0,0 +1,252 @@
+package accounts
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"book_manager/backend/internal/auditlogs"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/follows"
+	"book_manager/backend/internal/repository"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// DeleteAuthUserFunc は認証バックエンド側のアカウントを削除します。
+type DeleteAuthUserFunc func(ctx context.Context, uid string) error
+
+// Service はアカウントの削除と個人データのエクスポート（テイクアウト）、管理者によるアカウントの検索と利用停止を扱います。
+type Service struct {
+	data            repository.AccountDataRepository
+	users           repository.UserRepository
+	profiles        repository.ProfileSettingsRepository
+	userBooks       repository.UserBookRepository
+	books           repository.BookRepository
+	favorites       repository.FavoriteRepository
+	nextToBuy       repository.NextToBuyRepository
+	recommendations repository.RecommendationRepository
+	calendarTokens  repository.CalendarTokenRepository
+	auditLogs       repository.AuditLogRepository
+	sessions        repository.SessionRepository
+	apiTokens       repository.APITokenRepository
+	suspensions     repository.AccountSuspensionRepository
+	follows         *follows.Service
+	deleteAuthUser  DeleteAuthUserFunc
+	now             func() time.Time
+}
+
+// NewService は Service を作成します。
+// deleteAuthUser が nil の場合（ローカル認証でアカウントが同じ DB にある場合）は DB の削除のみ行います。
+func NewService(
+	data repository.AccountDataRepository,
+	users repository.UserRepository,
+	profiles repository.ProfileSettingsRepository,
+	userBooks repository.UserBookRepository,
+	books repository.BookRepository,
+	favorites repository.FavoriteRepository,
+	nextToBuy repository.NextToBuyRepository,
+	recommendations repository.RecommendationRepository,
+	calendarTokens repository.CalendarTokenRepository,
+	auditLogs repository.AuditLogRepository,
+	sessions repository.SessionRepository,
+	apiTokens repository.APITokenRepository,
+	suspensions repository.AccountSuspensionRepository,
+	followsService *follows.Service,
+	deleteAuthUser DeleteAuthUserFunc,
+) *Service {
+	return &Service{
+		data:            data,
+		users:           users,
+		profiles:        profiles,
+		userBooks:       userBooks,
+		books:           books,
+		favorites:       favorites,
+		nextToBuy:       nextToBuy,
+		recommendations: recommendations,
+		calendarTokens:  calendarTokens,
+		auditLogs:       auditLogs,
+		sessions:        sessions,
+		apiTokens:       apiTokens,
+		suspensions:     suspensions,
+		follows:         followsService,
+		deleteAuthUser:  deleteAuthUser,
+		now:             time.Now,
+	}
+}
+
+// Delete はユーザーのデータをすべて削除し、テーブルごとの削除件数を返します。
+// 認証バックエンドのアカウント削除は DB のトランザクション内で行い、失敗した場合は DB の削除も取り消します。
+func (s *Service) Delete(ctx context.Context, userID string) (map[string]int, error) {
+	if _, ok := s.users.FindByID(userID); !ok {
+		return nil, ErrUserNotFound
+	}
+	var beforeCommit func() error
+	if s.deleteAuthUser != nil {
+		beforeCommit = func() error {
+			return s.deleteAuthUser(ctx, userID)
+		}
+	}
+	counts, err := s.data.DeleteAllForUser(userID, beforeCommit)
+	if err != nil {
+		return nil, err
+	}
+	if s.follows != nil {
+		counts["follows"] = s.follows.RemoveUser(userID)
+	}
+	return counts, nil
+}
+
+// WriteTakeout はユーザーの個人データを JSON ファイルにまとめた ZIP を書き込みます。
+// API キーやトークンのハッシュなどの秘密情報は含めません。
+func (s *Service) WriteTakeout(w io.Writer, userID string) error {
+	user, ok := s.users.FindByID(userID)
+	if !ok {
+		return ErrUserNotFound
+	}
+	files := s.takeoutFiles(user)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	modified := s.now()
+	manifest := map[string]any{
+		"userId":     user.UserID,
+		"exportedAt": modified.Format(time.RFC3339),
+		"files":      names,
+	}
+	if err := writeJSONFile(archive, "manifest.json", manifest, modified); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := writeJSONFile(archive, name, files[name], modified); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (s *Service) takeoutFiles(user domain.User) map[string]any {
+	settings, ok := s.profiles.Get(user.ID)
+	if !ok {
+		settings.Visibility = "public"
+	}
+	userBooks := s.userBooks.ListByUser(user.ID)
+	books := make([]domain.Book, 0, len(userBooks))
+	for _, item := range userBooks {
+		if book, ok := s.books.FindByID(item.BookID); ok {
+			books = append(books, book)
+		}
+	}
+
+	files := map[string]any{
+		"profile.json": map[string]any{
+			"id":            user.ID,
+			"userId":        user.UserID,
+			"email":         user.Email,
+			"displayName":   user.DisplayName,
+			"emailVerified": user.EmailVerified,
+			"settings": map[string]any{
+				"visibility":     settings.Visibility,
+				"openaiEnabled":  settings.OpenAIEnabled,
+				"openaiModel":    settings.OpenAIModel,
+				"openaiHasKey":   settings.OpenAIAPIKey != "",
+				"monthlyBudget":  settings.MonthlyBudget,
+				"budgetCurrency": settings.BudgetCurrency,
+			},
+		},
+		"user_books.json":      userBooks,
+		"books.json":           books,
+		"favorites.json":       s.favorites.ListByUser(user.ID),
+		"next_to_buy.json":     s.nextToBuy.ListByUser(user.ID),
+		"recommendations.json": s.recommendations.ListByUser(user.ID),
+		"sessions.json":        sessionsExport(s.sessions.ListByUser(user.ID)),
+		"api_tokens.json":      apiTokensExport(s.apiTokens.ListByUser(user.ID)),
+		"audit_logs.json":      auditLogsExport(s.auditLogs.ListByUser(user.ID)),
+	}
+	if s.follows != nil {
+		files["follows.json"] = map[string]any{
+			"following": s.follows.Following(user.ID),
+			"followers": s.follows.Followers(user.ID),
+		}
+	}
+	if token, ok := s.calendarTokens.FindByUserID(user.ID); ok {
+		files["calendar.json"] = map[string]any{
+			"enabled":   true,
+			"createdAt": token.CreatedAt,
+		}
+	}
+	return files
+}
+
+func writeJSONFile(archive *zip.Writer, name string, payload any, modified time.Time) error {
+	file, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+func sessionsExport(items []domain.Session) []map[string]any {
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		result = append(result, map[string]any{
+			"id":         item.ID,
+			"deviceName": item.DeviceName,
+			"userAgent":  item.UserAgent,
+			"ip":         item.IP,
+			"createdAt":  item.CreatedAt,
+			"lastUsedAt": item.LastUsedAt,
+			"revokedAt":  item.RevokedAt,
+		})
+	}
+	return result
+}
+
+func apiTokensExport(items []domain.APIToken) []map[string]any {
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		result = append(result, map[string]any{
+			"id":         item.ID,
+			"name":       item.Name,
+			"scope":      item.Scope,
+			"prefix":     item.Prefix,
+			"createdAt":  item.CreatedAt,
+			"expiresAt":  item.ExpiresAt,
+			"lastUsedAt": item.LastUsedAt,
+			"revokedAt":  item.RevokedAt,
+		})
+	}
+	return result
+}
+
+// auditLogsExport はマスク導入前に記録された行も含め、パスワードやトークンをマスクした payload を書き出します。
+func auditLogsExport(items []domain.AuditLog) []map[string]any {
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		result = append(result, map[string]any{
+			"id":        item.ID,
+			"action":    item.Action,
+			"entity":    item.Entity,
+			"entityId":  item.EntityID,
+			"payload":   auditlogs.RedactPayload(item.Payload),
+			"ip":        item.IP,
+			"userAgent": item.UserAgent,
+			"createdAt": item.CreatedAt,
+		})
+	}
+	return result
+}
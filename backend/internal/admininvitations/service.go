@@ -16,50 +16,63 @@ var (
 	ErrInvitationNotFound   = errors.New("invitation not found")
 	ErrInvitationExpired    = errors.New("invitation expired")
 	ErrInvitationUsed       = errors.New("invitation already used")
-	ErrUserIDNotAdmin       = errors.New("user id is not an admin user id")
 	ErrUserIDAlreadyTaken   = errors.New("user id already taken")
 	ErrUserIDAlreadyInvited = errors.New("user id already has pending invitation")
 	ErrEmailMismatch        = errors.New("email does not match invitation")
+	ErrInvalidStatus        = errors.New("invalid invitation status")
+	ErrInvalidExtension     = errors.New("invalid invitation extension")
 )
 
 const (
 	TokenBytes      = 32 // 256 bits
 	ExpirationHours = 72
+	// MaxExtendHours は 1 回の延長で追加できる最大の時間です。
+	MaxExtendHours = 7 * 24
+	// ExpiredRetention は期限切れの招待を一覧に残す期間です。過ぎたものはスイーパーが削除します。
+	ExpiredRetention = 30 * 24 * time.Hour
+)
+
+// 招待の状態です。DB には保存せず、使用日時と有効期限から求めます。
+const (
+	StatusPending = "pending"
+	StatusUsed    = "used"
+	StatusExpired = "expired"
 )
 
 type Service struct {
 	repo          repository.AdminInvitationRepository
-	isAdminUserID func(userID string) bool
 	isUserIDTaken func(userID string) bool
+	now           func() time.Time
 }
 
 func NewService(
 	repo repository.AdminInvitationRepository,
-	isAdminUserID func(userID string) bool,
 	isUserIDTaken func(userID string) bool,
 ) *Service {
 	return &Service{
 		repo:          repo,
-		isAdminUserID: isAdminUserID,
 		isUserIDTaken: isUserIDTaken,
+		now:           time.Now,
 	}
 }
 
+// Create は招待を作成します。管理者ロールは招待の受諾（管理者登録）時に付与します。
+// 同じ UserID の期限切れの招待は新しい招待で置き換えます。
 func (s *Service) Create(createdBy, userID, email string) (domain.AdminInvitation, error) {
-	if s.isAdminUserID == nil || !s.isAdminUserID(userID) {
-		return domain.AdminInvitation{}, ErrUserIDNotAdmin
-	}
 	if s.isUserIDTaken(userID) {
 		return domain.AdminInvitation{}, ErrUserIDAlreadyTaken
 	}
-	if existing, ok := s.repo.FindByUserID(userID); ok && existing.UsedAt == nil {
-		return domain.AdminInvitation{}, ErrUserIDAlreadyInvited
+	now := s.now()
+	if existing, ok := s.repo.FindByUserID(userID); ok {
+		if Status(existing, now) != StatusExpired {
+			return domain.AdminInvitation{}, ErrUserIDAlreadyInvited
+		}
+		s.repo.Delete(existing.ID)
 	}
 	token, err := generateToken()
 	if err != nil {
 		return domain.AdminInvitation{}, err
 	}
-	now := time.Now()
 	invitation := domain.AdminInvitation{
 		ID:        uuid.New().String(),
 		Token:     token,
@@ -79,6 +92,94 @@ func (s *Service) List() []domain.AdminInvitation {
 	return s.repo.List()
 }
 
+// ListByStatus は指定した状態の招待を返します。status が空の場合はすべて返します。
+func (s *Service) ListByStatus(status string) ([]domain.AdminInvitation, error) {
+	items := s.repo.List()
+	if status == "" {
+		return items, nil
+	}
+	if status != StatusPending && status != StatusUsed && status != StatusExpired {
+		return nil, ErrInvalidStatus
+	}
+	now := s.now()
+	filtered := make([]domain.AdminInvitation, 0, len(items))
+	for _, item := range items {
+		if Status(item, now) == status {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// Status は招待の状態（pending / used / expired）を返します。
+func Status(invitation domain.AdminInvitation, now time.Time) string {
+	if invitation.UsedAt != nil {
+		return StatusUsed
+	}
+	if now.After(invitation.ExpiresAt) {
+		return StatusExpired
+	}
+	return StatusPending
+}
+
+// Resend は未使用の招待のトークンを作り直し、有効期限を作成時と同じ長さで設定し直します。
+// 以前のトークン（送信済みのリンク）は使えなくなります。
+func (s *Service) Resend(id string) (domain.AdminInvitation, error) {
+	invitation, ok := s.repo.FindByID(id)
+	if !ok {
+		return domain.AdminInvitation{}, ErrInvitationNotFound
+	}
+	if invitation.UsedAt != nil {
+		return domain.AdminInvitation{}, ErrInvitationUsed
+	}
+	token, err := generateToken()
+	if err != nil {
+		return domain.AdminInvitation{}, err
+	}
+	invitation.Token = token
+	invitation.ExpiresAt = s.now().Add(ExpirationHours * time.Hour)
+	if !s.repo.Update(invitation) {
+		return domain.AdminInvitation{}, ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+// Extend は未使用の招待の有効期限を延長します。期限切れの招待は現在時刻から延長します。
+func (s *Service) Extend(id string, hours int) (domain.AdminInvitation, error) {
+	if hours < 1 || hours > MaxExtendHours {
+		return domain.AdminInvitation{}, ErrInvalidExtension
+	}
+	invitation, ok := s.repo.FindByID(id)
+	if !ok {
+		return domain.AdminInvitation{}, ErrInvitationNotFound
+	}
+	if invitation.UsedAt != nil {
+		return domain.AdminInvitation{}, ErrInvitationUsed
+	}
+	base := invitation.ExpiresAt
+	if now := s.now(); now.After(base) {
+		base = now
+	}
+	invitation.ExpiresAt = base.Add(time.Duration(hours) * time.Hour)
+	if !s.repo.Update(invitation) {
+		return domain.AdminInvitation{}, ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+// SweepExpired は期限切れから ExpiredRetention を過ぎた未使用の招待を削除し、件数を返します。
+// それより新しい期限切れの招待は、再送・延長できるよう expired として一覧に残します。
+func (s *Service) SweepExpired() int {
+	return s.repo.DeleteExpiredBefore(s.now().Add(-ExpiredRetention))
+}
+
+// IsPending は UserID に未使用で期限内の招待があるかを返します。
+// 招待された UserID は受諾されるまで一般のサインアップで使えないよう予約します。
+func (s *Service) IsPending(userID string) bool {
+	invitation, ok := s.repo.FindByUserID(userID)
+	return ok && Status(invitation, s.now()) == StatusPending
+}
+
 func (s *Service) Get(id string) (domain.AdminInvitation, bool) {
 	return s.repo.FindByID(id)
 }
@@ -95,7 +196,7 @@ func (s *Service) ValidateToken(token string) (domain.AdminInvitation, error) {
 	if invitation.UsedAt != nil {
 		return domain.AdminInvitation{}, ErrInvitationUsed
 	}
-	if time.Now().After(invitation.ExpiresAt) {
+	if s.now().After(invitation.ExpiresAt) {
 		return domain.AdminInvitation{}, ErrInvitationExpired
 	}
 	return invitation, nil
@@ -117,7 +218,7 @@ func (s *Service) MarkUsed(id, usedBy string) error {
 	if !ok {
 		return ErrInvitationNotFound
 	}
-	now := time.Now()
+	now := s.now()
 	invitation.UsedAt = &now
 	invitation.UsedBy = usedBy
 	if !s.repo.Update(invitation) {
@@ -140,13 +241,6 @@ func (s *Service) UnmarkUsed(id string) error {
 	return nil
 }
 
-func (s *Service) IsAdminUserID(userID string) bool {
-	if s.isAdminUserID == nil {
-		return false
-	}
-	return s.isAdminUserID(userID)
-}
-
 func generateToken() (string, error) {
 	bytes := make([]byte, TokenBytes)
 	if _, err := rand.Read(bytes); err != nil {
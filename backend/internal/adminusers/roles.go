@@ -0,0 +1,81 @@
+package adminusers
+
+import "sort"
+
+// Role は管理機能の権限の組み合わせです。
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleSupport   = "support"
+)
+
+// Permission は管理 API ごとに要求する権限です。
+type Permission string
+
+const (
+	PermManageRoles       Permission = "roles:manage"
+	PermManageInvitations Permission = "invitations:manage"
+	PermManageOpenAI      Permission = "openai:manage"
+	PermManagePrompts     Permission = "prompts:manage"
+	PermViewAIUsage       Permission = "ai-usage:view"
+	PermManageSeries      Permission = "series:manage"
+	PermHandleBookReports Permission = "book-reports:handle"
+	PermViewAuditLogs     Permission = "audit-logs:view"
+	PermManageAccounts    Permission = "accounts:manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermManageRoles,
+		PermManageInvitations,
+		PermManageOpenAI,
+		PermManagePrompts,
+		PermViewAIUsage,
+		PermManageSeries,
+		PermHandleBookReports,
+		PermViewAuditLogs,
+		PermManageAccounts,
+	},
+	RoleModerator: {
+		PermManageSeries,
+		PermHandleBookReports,
+	},
+	RoleSupport: {
+		PermViewAuditLogs,
+		PermViewAIUsage,
+	},
+}
+
+// IsValidRole はロール名が定義済みかを返します。
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Roles は定義済みのロールと権限の一覧を返します。
+func Roles() map[string][]Permission {
+	result := make(map[string][]Permission, len(rolePermissions))
+	for role, perms := range rolePermissions {
+		result[role] = append([]Permission(nil), perms...)
+	}
+	return result
+}
+
+// RoleNames は定義済みのロール名を名前順で返します。
+func RoleNames() []string {
+	names := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		names = append(names, role)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func roleHas(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
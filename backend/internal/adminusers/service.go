@@ -16,6 +16,8 @@ var (
 	ErrAlreadyAdmin = errors.New("user is already an admin")
 	ErrNotDBAdmin   = errors.New("user is not a DB admin")
 	ErrEnvAdmin     = errors.New("cannot remove env admin from DB")
+	ErrInvalidRole  = errors.New("invalid role")
+	ErrLastAdmin    = errors.New("cannot remove the last admin")
 )
 
 type Service struct {
@@ -37,20 +39,39 @@ func NewService(repo repository.AdminUserRepository, envAdminIDs []string) *Serv
 	}
 }
 
+// IsAdmin はユーザーが admin ロールを持つかを返します。
 func (s *Service) IsAdmin(userID string) bool {
+	role, ok := s.RoleOf(userID)
+	return ok && role == RoleAdmin
+}
+
+// RoleOf はユーザーのロールを返します。環境変数で指定したユーザーは常に admin です。
+func (s *Service) RoleOf(userID string) (string, bool) {
 	if userID == "" {
-		return false
+		return "", false
 	}
-	s.mu.RLock()
-	_, ok := s.envAdminIDs[userID]
-	s.mu.RUnlock()
-	if ok {
-		return true
+	if s.IsEnvAdmin(userID) {
+		return RoleAdmin, true
+	}
+	adminUser, ok := s.repo.FindByUserID(userID)
+	if !ok {
+		return "", false
 	}
-	_, ok = s.repo.FindByUserID(userID)
+	return normalizeRole(adminUser.Role), true
+}
+
+// HasRole はユーザーに何らかのロールが割り当てられているかを返します。
+func (s *Service) HasRole(userID string) bool {
+	_, ok := s.RoleOf(userID)
 	return ok
 }
 
+// HasPermission はユーザーのロールが権限を含むかを返します。
+func (s *Service) HasPermission(userID string, perm Permission) bool {
+	role, ok := s.RoleOf(userID)
+	return ok && roleHas(role, perm)
+}
+
 func (s *Service) List() []AdminUserInfo {
 	dbAdmins := s.repo.List()
 
@@ -66,6 +87,7 @@ func (s *Service) List() []AdminUserInfo {
 		_, inDB := dbUserIDs[userID]
 		result = append(result, AdminUserInfo{
 			UserID:    userID,
+			Role:      RoleAdmin,
 			Source:    "env",
 			CreatedAt: time.Time{},
 			InDB:      inDB,
@@ -78,6 +100,7 @@ func (s *Service) List() []AdminUserInfo {
 		}
 		result = append(result, AdminUserInfo{
 			UserID:    admin.UserID,
+			Role:      normalizeRole(admin.Role),
 			Source:    "db",
 			CreatedBy: admin.CreatedBy,
 			CreatedAt: admin.CreatedAt,
@@ -116,7 +139,11 @@ func (s *Service) ListAll() []string {
 	return result
 }
 
-func (s *Service) Add(userID, createdBy string) error {
+// Add はユーザーにロールを割り当てます。
+func (s *Service) Add(userID, role, createdBy string) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
 	if _, ok := s.repo.FindByUserID(userID); ok {
 		return ErrAlreadyAdmin
 	}
@@ -124,12 +151,34 @@ func (s *Service) Add(userID, createdBy string) error {
 	adminUser := domain.AdminUser{
 		ID:        uuid.New().String(),
 		UserID:    userID,
+		Role:      role,
 		CreatedBy: createdBy,
 		CreatedAt: time.Now(),
 	}
 	return s.repo.Create(adminUser)
 }
 
+// SetRole は DB で割り当てたユーザーのロールを変更します。
+func (s *Service) SetRole(userID, role string) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+	if s.IsEnvAdmin(userID) {
+		return ErrEnvAdmin
+	}
+	current, ok := s.repo.FindByUserID(userID)
+	if !ok {
+		return ErrNotDBAdmin
+	}
+	if normalizeRole(current.Role) == RoleAdmin && role != RoleAdmin && s.isLastAdmin(userID) {
+		return ErrLastAdmin
+	}
+	if !s.repo.UpdateRole(userID, role) {
+		return ErrNotDBAdmin
+	}
+	return nil
+}
+
 func (s *Service) Remove(userID string) error {
 	s.mu.RLock()
 	_, isEnv := s.envAdminIDs[userID]
@@ -139,6 +188,8 @@ func (s *Service) Remove(userID string) error {
 		if _, inDB := s.repo.FindByUserID(userID); !inDB {
 			return ErrEnvAdmin
 		}
+	} else if current, ok := s.repo.FindByUserID(userID); ok && normalizeRole(current.Role) == RoleAdmin && s.isLastAdmin(userID) {
+		return ErrLastAdmin
 	}
 
 	if !s.repo.Delete(userID) {
@@ -147,6 +198,19 @@ func (s *Service) Remove(userID string) error {
 	return nil
 }
 
+// CheckAccountDeletion はロールを持つユーザーがアカウントを削除できるかを返します。
+// ADMIN_USER_IDS のユーザーは同じ ID で再登録すると admin に戻るため拒否し、
+// 最後の admin も管理者がいなくなるため拒否します。
+func (s *Service) CheckAccountDeletion(userID string) error {
+	if s.IsEnvAdmin(userID) {
+		return ErrEnvAdmin
+	}
+	if current, ok := s.repo.FindByUserID(userID); ok && normalizeRole(current.Role) == RoleAdmin && s.isLastAdmin(userID) {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
 func (s *Service) IsEnvAdmin(userID string) bool {
 	s.mu.RLock()
 	_, ok := s.envAdminIDs[userID]
@@ -154,8 +218,33 @@ func (s *Service) IsEnvAdmin(userID string) bool {
 	return ok
 }
 
+// isLastAdmin は userID 以外に admin ロールのユーザーがいないかを返します。
+func (s *Service) isLastAdmin(userID string) bool {
+	s.mu.RLock()
+	envCount := len(s.envAdminIDs)
+	s.mu.RUnlock()
+	if envCount > 0 {
+		return false
+	}
+	for _, admin := range s.repo.List() {
+		if admin.UserID != userID && normalizeRole(admin.Role) == RoleAdmin {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeRole はロール導入前に登録された（ロールが空の）管理者を admin として扱います。
+func normalizeRole(role string) string {
+	if role == "" {
+		return RoleAdmin
+	}
+	return role
+}
+
 type AdminUserInfo struct {
 	UserID    string    `json:"userId"`
+	Role      string    `json:"role"`
 	Source    string    `json:"source"`
 	CreatedBy string    `json:"createdBy,omitempty"`
 	CreatedAt time.Time `json:"createdAt,omitempty"`
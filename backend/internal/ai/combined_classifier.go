@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"context"
+	"log"
+)
+
+const (
+	// LocalConfidenceThreshold 以上の信頼度をローカル推定が返した場合は LLM に問い合わせません。
+	LocalConfidenceThreshold = 85
+	// defaultRemoteConfidence は LLM が信頼度を返さなかった場合に仮定する値です。
+	defaultRemoteConfidence = 70
+)
+
+// CombinedClassifier はローカル推定と LLM 推定のうち信頼度の高い方を採用します。
+type CombinedClassifier struct {
+	local  SeriesClassifier
+	remote SeriesClassifier
+}
+
+var _ SeriesClassifier = (*CombinedClassifier)(nil)
+
+// NewCombinedClassifier は remote が nil の場合、ローカル推定のみを使う分類器を返します。
+func NewCombinedClassifier(local, remote SeriesClassifier) *CombinedClassifier {
+	return &CombinedClassifier{
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (c *CombinedClassifier) GuessSeries(ctx context.Context, input SeriesInput) (SeriesGuess, error) {
+	local, err := c.local.GuessSeries(ctx, input)
+	if err != nil {
+		if c.remote == nil {
+			return SeriesGuess{}, err
+		}
+		return c.remote.GuessSeries(ctx, input)
+	}
+	if c.remote == nil || local.Confidence >= LocalConfidenceThreshold {
+		return local, nil
+	}
+	remote, err := c.remote.GuessSeries(ctx, input)
+	if err != nil {
+		log.Printf("series classifier: remote error, using local guess: %v", err)
+		return local, nil
+	}
+	if remote.Confidence <= 0 {
+		remote.Confidence = defaultRemoteConfidence
+	}
+	if local.Confidence > remote.Confidence {
+		return local, nil
+	}
+	return remote, nil
+}
@@ -0,0 +1,43 @@
+package ai
+
+import "context"
+
+// KeyedClassifier は API キーごとに作成した分類器です。
+type KeyedClassifier struct {
+	KeyID      string
+	Classifier SeriesClassifier
+}
+
+// FailoverClassifier は候補のキーを順に試し、キーの失効やレート制限で失敗した場合は次のキーに切り替えます。
+// 各呼び出しの結果（成功時は nil）を report に渡します。
+type FailoverClassifier struct {
+	candidates []KeyedClassifier
+	report     func(keyID string, err error)
+}
+
+var _ SeriesClassifier = (*FailoverClassifier)(nil)
+
+func NewFailoverClassifier(candidates []KeyedClassifier, report func(keyID string, err error)) *FailoverClassifier {
+	return &FailoverClassifier{
+		candidates: candidates,
+		report:     report,
+	}
+}
+
+func (c *FailoverClassifier) GuessSeries(ctx context.Context, input SeriesInput) (SeriesGuess, error) {
+	lastErr := ErrOpenAIUnavailable
+	for _, candidate := range c.candidates {
+		guess, err := candidate.Classifier.GuessSeries(ctx, input)
+		if c.report != nil {
+			c.report(candidate.KeyID, err)
+		}
+		if err == nil {
+			return guess, nil
+		}
+		if !IsKeyError(err) {
+			return SeriesGuess{}, err
+		}
+		lastErr = err
+	}
+	return SeriesGuess{}, lastErr
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+)
+
+// GuessCache は ISBN ごとの LLM 推定結果を保存し、ユーザー間で再利用します。
+// モデルまたはプロンプトが変わった場合、保存済みの結果は使わずに問い合わせ直します。
+type GuessCache struct {
+	repo repository.SeriesGuessCacheRepository
+}
+
+func NewGuessCache(repo repository.SeriesGuessCacheRepository) *GuessCache {
+	return &GuessCache{repo: repo}
+}
+
+// Wrap は inner の推定結果をキャッシュする SeriesClassifier を返します。
+func (c *GuessCache) Wrap(inner SeriesClassifier, model, promptHash string) SeriesClassifier {
+	if c == nil || c.repo == nil {
+		return inner
+	}
+	return &cachedClassifier{
+		inner:      inner,
+		repo:       c.repo,
+		model:      model,
+		promptHash: promptHash,
+	}
+}
+
+// PromptHash はプロンプトの内容から短いハッシュを求めます。空の場合は既定のプロンプトを使います。
+func PromptHash(prompt string) string {
+	if strings.TrimSpace(prompt) == "" {
+		prompt = defaultPrompt
+	}
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+type cachedClassifier struct {
+	inner      SeriesClassifier
+	repo       repository.SeriesGuessCacheRepository
+	model      string
+	promptHash string
+}
+
+func (c *cachedClassifier) GuessSeries(ctx context.Context, input SeriesInput) (SeriesGuess, error) {
+	if input.ISBN13 == "" {
+		return c.inner.GuessSeries(ctx, input)
+	}
+	if entry, ok := c.repo.Get(input.ISBN13); ok && entry.Model == c.model && entry.PromptHash == c.promptHash {
+		return SeriesGuess{
+			IsSeries:     entry.IsSeries,
+			Name:         entry.SeriesName,
+			VolumeNumber: entry.VolumeNumber,
+			Confidence:   entry.Confidence,
+			Source:       SourceOpenAI,
+		}, nil
+	}
+	guess, err := c.inner.GuessSeries(ctx, input)
+	if err != nil {
+		return SeriesGuess{}, err
+	}
+	if err := c.repo.Upsert(domain.SeriesGuessCache{
+		ISBN13:       input.ISBN13,
+		IsSeries:     guess.IsSeries,
+		SeriesName:   guess.Name,
+		VolumeNumber: guess.VolumeNumber,
+		Confidence:   guess.Confidence,
+		Model:        c.model,
+		PromptHash:   c.promptHash,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		log.Printf("series guess cache store error: isbn=%s err=%v", input.ISBN13, err)
+	}
+	return guess, nil
+}
@@ -0,0 +1,198 @@
+package ai
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"book_manager/backend/internal/isbn"
+)
+
+const (
+	SourceLocal  = "local"
+	SourceOpenAI = "openai"
+)
+
+// KnownSeries はユーザーが既に所蔵しているシリーズと、その巻の著者・タイトルです。
+type KnownSeries struct {
+	Name    string
+	Authors []string
+	Titles  []string
+}
+
+// publisherLabels はシリーズ物として刊行されることが多いレーベル名です。
+// タイトルに含まれる場合はシリーズ名から取り除き、判定の信頼度を上げます。
+// 他のレーベル名を含む長い名前を先に置きます。
+var publisherLabels = []string{
+	"ジャンプコミックスDIGITAL", "ヤングジャンプコミックス", "ジャンプ・コミックス", "ジャンプコミックス",
+	"少年サンデーコミックス", "ビッグコミックス", "講談社コミックス", "マガジンKC", "アフタヌーンKC",
+	"モーニングKC", "ヤンマガKC", "花とゆめコミックス", "りぼんマスコットコミックス", "マーガレットコミックス",
+	"ガンガンコミックス", "MFコミックス", "ドラゴンコミックスエイジ", "角川コミックス・エース",
+	"電撃文庫", "角川スニーカー文庫", "富士見ファンタジア文庫", "ガガガ文庫", "MF文庫J", "GA文庫",
+	"ファミ通文庫", "講談社ラノベ文庫", "オーバーラップ文庫", "HJ文庫", "集英社オレンジ文庫",
+	"ハヤカワ文庫", "創元推理文庫", "新潮文庫", "角川文庫", "講談社文庫", "文春文庫",
+}
+
+// emptyBracketPattern はレーベル名を取り除いた後に残る空の括弧です。
+var emptyBracketPattern = regexp.MustCompile(`[(（【\[]\s*[)）】\]]`)
+
+// LocalClassifier は API を使わずにシリーズを推定するルールベースの分類器です。
+// レーベル表記の除去、巻数表記の検出、ユーザーの既存シリーズとの著者・タイトル前方一致で判定します。
+type LocalClassifier struct {
+	known []KnownSeries
+}
+
+var _ SeriesClassifier = (*LocalClassifier)(nil)
+
+func NewLocalClassifier(known []KnownSeries) *LocalClassifier {
+	return &LocalClassifier{known: known}
+}
+
+func (c *LocalClassifier) GuessSeries(_ context.Context, input SeriesInput) (SeriesGuess, error) {
+	raw := strings.TrimSpace(input.RawTitle)
+	if raw == "" {
+		raw = strings.TrimSpace(input.Title)
+	}
+	stripped, hasLabel := stripLabels(raw)
+	if !hasLabel {
+		_, hasLabel = stripLabels(input.Publisher)
+	}
+	volume := isbn.ExtractVolume(stripped)
+	volumeNumber := isbn.VolumeNumberOf(volume)
+	cleaned := isbn.NormalizeSeriesName(stripped)
+
+	if name, confidence := c.matchKnown(cleaned, input.Authors); name != "" {
+		if volume.Label != "" {
+			confidence += 5
+		}
+		return localGuess(name, volumeNumber, confidence), nil
+	}
+	if name := isbn.NormalizeSeriesName(input.SeriesName); name != "" {
+		return localGuess(name, volumeNumber, 80), nil
+	}
+	if cleaned == "" {
+		return SeriesGuess{Confidence: 30, Source: SourceLocal}, nil
+	}
+	if volume.Label != "" {
+		confidence := 65
+		if hasLabel {
+			confidence += 10
+		}
+		return localGuess(cleaned, volumeNumber, confidence), nil
+	}
+	// 巻数表記がない場合は単巻として扱うが、判断材料が少ないため信頼度は低くする
+	confidence := 50
+	if hasLabel {
+		confidence = 35
+	}
+	return SeriesGuess{Confidence: confidence, Source: SourceLocal}, nil
+}
+
+// matchKnown は既存シリーズとの一致を探し、シリーズ名と信頼度を返します。
+// シリーズ名の完全一致・前方一致、または同じ著者の既刊タイトルとの共通接頭辞で判定します。
+func (c *LocalClassifier) matchKnown(cleaned string, authors []string) (string, int) {
+	if cleaned == "" {
+		return "", 0
+	}
+	bestName, bestScore := "", 0
+	for _, series := range c.known {
+		name := strings.TrimSpace(series.Name)
+		if name == "" {
+			continue
+		}
+		sameAuthor := sharesAuthor(authors, series.Authors)
+		score := 0
+		switch {
+		case strings.EqualFold(cleaned, name):
+			score = 80
+			if sameAuthor {
+				score = 90
+			}
+		case sameAuthor && utf8.RuneCountInString(name) >= 2 && strings.HasPrefix(cleaned, name):
+			score = 85
+		case sameAuthor && sharesTitlePrefix(cleaned, series.Titles):
+			score = 75
+		}
+		if score > bestScore {
+			bestName, bestScore = name, score
+		}
+	}
+	return bestName, bestScore
+}
+
+func localGuess(name string, volumeNumber, confidence int) SeriesGuess {
+	if confidence > 100 {
+		confidence = 100
+	}
+	return SeriesGuess{
+		IsSeries:     true,
+		Name:         name,
+		VolumeNumber: volumeNumber,
+		Confidence:   confidence,
+		Source:       SourceLocal,
+	}
+}
+
+// stripLabels はタイトルからレーベル名を取り除きます。レーベルが含まれていたかも返します。
+func stripLabels(title string) (string, bool) {
+	found := false
+	for _, label := range publisherLabels {
+		if strings.Contains(title, label) {
+			title = strings.ReplaceAll(title, label, "")
+			found = true
+		}
+	}
+	if found {
+		title = emptyBracketPattern.ReplaceAllString(title, "")
+	}
+	return strings.TrimSpace(title), found
+}
+
+func sharesAuthor(a, b []string) bool {
+	for _, left := range a {
+		left = normalizeAuthor(left)
+		if left == "" {
+			continue
+		}
+		for _, right := range b {
+			if left == normalizeAuthor(right) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeAuthor(author string) string {
+	return strings.Join(strings.Fields(author), "")
+}
+
+// sharesTitlePrefix は既刊タイトルのいずれかと十分な長さの共通接頭辞を持つかを判定します。
+// 接頭辞は 4 文字以上かつ短い方のタイトルの 6 割以上を要求します。
+func sharesTitlePrefix(cleaned string, titles []string) bool {
+	for _, title := range titles {
+		other := isbn.NormalizeSeriesName(title)
+		if other == "" {
+			continue
+		}
+		prefix := commonPrefixLength(cleaned, other)
+		shorter := utf8.RuneCountInString(cleaned)
+		if n := utf8.RuneCountInString(other); n < shorter {
+			shorter = n
+		}
+		if prefix >= 4 && prefix*10 >= shorter*6 {
+			return true
+		}
+	}
+	return false
+}
+
+func commonPrefixLength(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	n := 0
+	for n < len(ar) && n < len(br) && ar[n] == br[n] {
+		n++
+	}
+	return n
+}
@@ -8,20 +8,86 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
 
 var ErrOpenAIUnavailable = errors.New("openai unavailable")
 
+var errEmptyResponse = errors.New("openai empty response")
+
+// StatusError は API が 200 以外のステータスを返したことを表します。
+// RetryAfter は Retry-After ヘッダーが秒数で指定されていた場合の待ち時間です。
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("openai status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("openai status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsKeyError はキーの失効やレート制限など、別のキーで再試行すべきエラーかを判定します。
+func IsKeyError(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	return false
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	statusErr := &StatusError{
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(raw)),
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && seconds > 0 {
+		statusErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return statusErr
+}
+
+// DefaultBaseURL は OpenAI API のベース URL です。
+// Ollama や llama.cpp など OpenAI 互換のサーバーを使う場合は別の URL を指定します。
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClient は OpenAI 互換の Chat Completions API を使う SeriesClassifier です。
 type OpenAIClient struct {
-	apiKey string
-	model  string
-	client *http.Client
-	prompt string
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+	prompt  string
+	onUsage func(Usage)
+	breaker *CircuitBreaker
+	metrics *Metrics
 }
 
-func NewOpenAIClient(apiKey, model, prompt string) *OpenAIClient {
+// Usage は 1 回の Chat Completions 呼び出しで消費したトークン数です。
+type Usage struct {
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+var _ SeriesClassifier = (*OpenAIClient)(nil)
+
+func NewOpenAIClient(baseURL, apiKey, model, prompt string) *OpenAIClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 	if strings.TrimSpace(prompt) == "" {
 		prompt = defaultPrompt
 	}
@@ -30,8 +96,9 @@ func NewOpenAIClient(apiKey, model, prompt string) *OpenAIClient {
 		model = "gpt-4o-mini"
 	}
 	return &OpenAIClient{
-		apiKey: apiKey,
-		model:  model,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		model:   model,
 		client: &http.Client{
 			Timeout: 8 * time.Second,
 		},
@@ -39,87 +106,267 @@ func NewOpenAIClient(apiKey, model, prompt string) *OpenAIClient {
 	}
 }
 
+// OnUsage はレスポンスにトークン使用量が含まれていた場合に呼ばれる関数を設定します。
+func (c *OpenAIClient) OnUsage(fn func(Usage)) {
+	c.onUsage = fn
+}
+
+// SetCircuitBreaker は複数のクライアントで共有するサーキットブレーカーを設定します。
+func (c *OpenAIClient) SetCircuitBreaker(breaker *CircuitBreaker) {
+	c.breaker = breaker
+}
+
+// SetMetrics は失敗の分類を集計する Metrics を設定します。
+func (c *OpenAIClient) SetMetrics(metrics *Metrics) {
+	c.metrics = metrics
+}
+
+// Model は推定に使うモデル名を返します。
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// PromptHash は推定に使うシステムプロンプトのハッシュを返します。
+func (c *OpenAIClient) PromptHash() string {
+	return PromptHash(c.prompt)
+}
+
+// Available は API を呼び出せる設定かどうかを返します。
+// OpenAI 本体には API キーが必須ですが、互換サーバーはキーなしでも利用できます。
+func (c *OpenAIClient) Available() bool {
+	if c.model == "" {
+		return false
+	}
+	return c.apiKey != "" || c.baseURL != DefaultBaseURL
+}
+
 type openAIResponse struct {
 	Choices []struct {
 		Message struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
 }
 
 func (c *OpenAIClient) GuessSeries(ctx context.Context, input SeriesInput) (SeriesGuess, error) {
-	if c.apiKey == "" || c.model == "" {
+	var guess SeriesGuess
+	err := c.complete(ctx, chatRequest{
+		system: c.prompt,
+		user: fmt.Sprintf(
+			"Input:\n"+
+				"title=%q\nrawTitle=%q\nauthors=%q\npublisher=%q\npublishedDate=%q\nisbn13=%q\nseriesName=%q\n",
+			input.Title,
+			input.RawTitle,
+			strings.Join(input.Authors, " / "),
+			input.Publisher,
+			input.PublishedDate,
+			input.ISBN13,
+			input.SeriesName,
+		),
+		temperature: 0.1,
+		schemaName:  "series_guess",
+		schema:      seriesGuessSchema,
+	}, &guess)
+	if err != nil {
+		return SeriesGuess{}, err
+	}
+	if guess.Name != "" && !guess.IsSeries {
+		guess.IsSeries = true
+	}
+	if guess.IsSeries && strings.TrimSpace(guess.Name) == "" {
 		return SeriesGuess{}, ErrOpenAIUnavailable
 	}
+	if !guess.IsSeries {
+		guess.Name = ""
+		guess.VolumeNumber = 0
+	}
+	if guess.VolumeNumber < 0 {
+		guess.VolumeNumber = 0
+	}
+	guess.Confidence = min(max(guess.Confidence, 0), 100)
+	guess.Source = SourceOpenAI
+	return guess, nil
+}
+
+type chatRequest struct {
+	system      string
+	user        string
+	temperature float64
+	schemaName  string
+	schema      map[string]any
+}
+
+// complete は Chat Completions API を呼び出し、応答を schema で検証して dst に読み込みます。
+// 429・5xx・通信エラー・不正な応答は指数バックオフ（429 は Retry-After を優先）で最大 maxAttempts 回まで試し、
+// サーキットブレーカーが開いている間は呼び出さずに ErrCircuitOpen を返します。
+func (c *OpenAIClient) complete(ctx context.Context, request chatRequest, dst any) error {
+	if !c.Available() {
+		return ErrOpenAIUnavailable
+	}
+	structured := c.baseURL == DefaultBaseURL
+	downgraded := false
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay, retry := retryDelay(err, attempt-1)
+			if downgraded {
+				delay, retry, downgraded = 0, true, false
+			}
+			if !retry {
+				break
+			}
+			c.metrics.recordRetry()
+			if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+				return err
+			}
+		}
+		if err = c.breaker.allow(); err != nil {
+			c.metrics.recordFailure(FailureCircuitOpen)
+			return err
+		}
+		c.metrics.recordRequest()
+		err = c.completeOnce(ctx, request, structured, dst)
+		category := ""
+		if err != nil {
+			category = FailureCategory(err)
+		}
+		switch {
+		case err == nil:
+			c.breaker.success()
+			c.metrics.recordSuccess()
+			return nil
+		case tripsBreaker(category):
+			c.breaker.failure()
+		default:
+			c.breaker.done()
+		}
+		c.metrics.recordFailure(category)
+		// json_schema に対応していないモデルでは json_object で再試行する
+		var statusErr *StatusError
+		if structured && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest {
+			structured = false
+			downgraded = true
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *OpenAIClient) completeOnce(ctx context.Context, request chatRequest, structured bool, dst any) error {
+	responseFormat := map[string]any{
+		"type": "json_object",
+	}
+	if structured && request.schema != nil {
+		responseFormat = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   request.schemaName,
+				"strict": true,
+				"schema": request.schema,
+			},
+		}
+	}
 	payload := map[string]any{
 		"model": c.model,
 		"messages": []map[string]string{
 			{
 				"role":    "system",
-				"content": c.prompt,
+				"content": request.system,
 			},
 			{
-				"role": "user",
-				"content": fmt.Sprintf(
-					"Input:\n"+
-						"title=%q\nrawTitle=%q\nauthors=%q\npublisher=%q\npublishedDate=%q\nisbn13=%q\nseriesName=%q\n",
-					input.Title,
-					input.RawTitle,
-					strings.Join(input.Authors, " / "),
-					input.Publisher,
-					input.PublishedDate,
-					input.ISBN13,
-					input.SeriesName,
-				),
+				"role":    "user",
+				"content": request.user,
 			},
 		},
-		"response_format": map[string]string{
-			"type": "json_object",
-		},
-		"temperature": 0.1,
+		"response_format": responseFormat,
+		"temperature":     request.temperature,
 	}
 	body, _ := json.Marshal(payload)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
 	if err != nil {
-		return SeriesGuess{}, err
+		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.authorize(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return SeriesGuess{}, err
+		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
-		return SeriesGuess{}, fmt.Errorf("openai status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+		return newStatusError(resp)
 	}
 	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
 	if err != nil {
-		return SeriesGuess{}, err
+		return err
 	}
 	var data openAIResponse
 	if err := json.Unmarshal(raw, &data); err != nil {
-		return SeriesGuess{}, err
+		return &parseError{err: err, text: string(raw)}
 	}
-	if len(data.Choices) == 0 {
-		return SeriesGuess{}, ErrOpenAIUnavailable
+	c.reportUsage(data)
+	if len(data.Choices) == 0 || strings.TrimSpace(data.Choices[0].Message.Content) == "" {
+		return errEmptyResponse
 	}
-	text := data.Choices[0].Message.Content
-	var guess SeriesGuess
-	if err := json.Unmarshal([]byte(text), &guess); err != nil {
-		return SeriesGuess{}, fmt.Errorf("openai parse error: %w text=%q", err, text)
+	return decodeWithSchema(data.Choices[0].Message.Content, request.schema, dst)
+}
+
+type modelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// ListModels は /models で利用可能なモデル ID の一覧を取得します。
+func (c *OpenAIClient) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/models", nil)
+	if err != nil {
+		return nil, err
 	}
-	if guess.Name != "" && !guess.IsSeries {
-		guess.IsSeries = true
+	c.authorize(req)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
 	}
-	if guess.IsSeries && strings.TrimSpace(guess.Name) == "" {
-		return SeriesGuess{}, ErrOpenAIUnavailable
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
 	}
-	if !guess.IsSeries {
-		guess.Name = ""
-		guess.VolumeNumber = 0
+	var payload modelsResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&payload); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(payload.Data))
+	for _, model := range payload.Data {
+		if model.ID != "" {
+			models = append(models, model.ID)
+		}
+	}
+	return models, nil
+}
+
+func (c *OpenAIClient) reportUsage(data openAIResponse) {
+	if c.onUsage == nil || data.Usage == nil {
+		return
+	}
+	c.onUsage(Usage{
+		Model:            c.model,
+		PromptTokens:     data.Usage.PromptTokens,
+		CompletionTokens: data.Usage.CompletionTokens,
+		TotalTokens:      data.Usage.TotalTokens,
+	})
+}
+
+func (c *OpenAIClient) authorize(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
-	return guess, nil
 }
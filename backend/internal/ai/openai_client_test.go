@@ -0,0 +1,195 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// rewriteTransport は DefaultBaseURL 宛てのリクエストをテスト用サーバーに送り直します。
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func chatCompletionBody(t *testing.T, content string) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"choices": []map[string]any{
+			{"message": map[string]any{"content": content}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func responseFormatType(t *testing.T, r *http.Request) string {
+	t.Helper()
+	var payload struct {
+		ResponseFormat struct {
+			Type string `json:"type"`
+		} `json:"response_format"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		t.Errorf("decode request: %v", err)
+	}
+	return payload.ResponseFormat.Type
+}
+
+func TestOpenAIClientUsesBaseURL(t *testing.T) {
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want empty", got)
+		}
+		formats = append(formats, responseFormatType(t, r))
+		w.Write(chatCompletionBody(t, `{"isSeries":true,"seriesName":"ワンピース","volumeNumber":3,"confidence":120}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(server.URL+"/v1/", "", "local-model", "")
+	if !client.Available() {
+		t.Fatal("client with custom base URL should be available without api key")
+	}
+	guess, err := client.GuessSeries(context.Background(), SeriesInput{Title: "ワンピース 3"})
+	if err != nil {
+		t.Fatalf("GuessSeries: %v", err)
+	}
+	want := SeriesGuess{IsSeries: true, Name: "ワンピース", VolumeNumber: 3, Confidence: 100, Source: SourceOpenAI}
+	if guess != want {
+		t.Errorf("guess = %+v, want %+v", guess, want)
+	}
+	// 互換サーバーには json_schema を送らない
+	if !reflect.DeepEqual(formats, []string{"json_object"}) {
+		t.Errorf("response formats = %v, want [json_object]", formats)
+	}
+}
+
+func TestOpenAIClientDowngradesToJSONObject(t *testing.T) {
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		format := responseFormatType(t, r)
+		formats = append(formats, format)
+		if format == "json_schema" {
+			http.Error(w, `{"error":{"message":"response_format json_schema is not supported"}}`, http.StatusBadRequest)
+			return
+		}
+		w.Write(chatCompletionBody(t, `{"isSeries":false,"seriesName":"","volumeNumber":0,"confidence":80}`))
+	}))
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewOpenAIClient("", "test-key", "", "")
+	client.client.Transport = rewriteTransport{target: target}
+	metrics := NewMetrics()
+	client.SetMetrics(metrics)
+	guess, err := client.GuessSeries(context.Background(), SeriesInput{Title: "単行本"})
+	if err != nil {
+		t.Fatalf("GuessSeries: %v", err)
+	}
+	if guess.IsSeries || guess.Confidence != 80 {
+		t.Errorf("guess = %+v", guess)
+	}
+	if !reflect.DeepEqual(formats, []string{"json_schema", "json_object"}) {
+		t.Errorf("response formats = %v, want [json_schema json_object]", formats)
+	}
+	snapshot := metrics.Snapshot()
+	if snapshot.Requests != 2 || snapshot.Successes != 1 || snapshot.Failures[FailureClientError] != 1 {
+		t.Errorf("metrics = %+v", snapshot)
+	}
+}
+
+func TestOpenAIClientDoesNotRetryClientErrorAfterDowngrade(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewOpenAIClient("", "test-key", "", "")
+	client.client.Transport = rewriteTransport{target: target}
+	_, err = client.GuessSeries(context.Background(), SeriesInput{Title: "単行本"})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want status 400", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestOpenAIClientListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/models" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Write([]byte(`{"data":[{"id":"gpt-4o-mini"},{"id":""},{"id":"llama3"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(server.URL+"/v1", "test-key", "", "")
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if want := []string{"gpt-4o-mini", "llama3"}; !reflect.DeepEqual(models, want) {
+		t.Errorf("models = %v, want %v", models, want)
+	}
+}
+
+func TestOpenAIClientListModelsStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(server.URL, "revoked", "", "")
+	_, err := client.ListModels(context.Background())
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("err = %v, want *StatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusUnauthorized || statusErr.Body != "invalid api key" || statusErr.RetryAfter != 3*time.Second {
+		t.Errorf("status error = %+v", statusErr)
+	}
+	if !IsKeyError(err) {
+		t.Error("401 should be a key error")
+	}
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ReadingSuggester は蔵書の要約から次に読む本の候補を提案します。
+type ReadingSuggester interface {
+	SuggestBooks(ctx context.Context, library LibrarySummary, limit int) ([]BookSuggestion, error)
+}
+
+// LibrarySummary はプロンプトに渡す蔵書の要約です。
+type LibrarySummary struct {
+	BookCount int
+	Series    []SeriesSummary
+	Authors   []string
+	Favorites []string
+	Liked     []string
+}
+
+type SeriesSummary struct {
+	Name    string
+	Authors []string
+	Volumes int
+}
+
+// BookSuggestion は LLM が提案した本です。実在するとは限らないため、書誌検索で確認してから使います。
+type BookSuggestion struct {
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Reason string `json:"reason"`
+}
+
+var _ ReadingSuggester = (*OpenAIClient)(nil)
+
+const suggestionPrompt = "You are a librarian who recommends what to read next based on a reader's library. " +
+	"Recommend books that are not in the library, preferring works by the same authors, related series and similar genres. " +
+	"Use the original title as published in Japan when the reader's books are Japanese. " +
+	"Return JSON only with key items: an array of objects with keys title (string), author (string) and reason (short string in Japanese)."
+
+func (c *OpenAIClient) SuggestBooks(ctx context.Context, library LibrarySummary, limit int) ([]BookSuggestion, error) {
+	var payload struct {
+		Items []BookSuggestion `json:"items"`
+	}
+	err := c.complete(ctx, chatRequest{
+		system:      suggestionPrompt,
+		user:        formatLibrary(library, limit),
+		temperature: 0.7,
+		schemaName:  "book_suggestions",
+		schema:      bookSuggestionsSchema,
+	}, &payload)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]BookSuggestion, 0, len(payload.Items))
+	for _, item := range payload.Items {
+		item.Title = strings.TrimSpace(item.Title)
+		item.Author = strings.TrimSpace(item.Author)
+		item.Reason = strings.TrimSpace(item.Reason)
+		if item.Title != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func formatLibrary(library LibrarySummary, limit int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Recommend up to %d books.\n", limit)
+	fmt.Fprintf(&b, "The library has %d books.\n", library.BookCount)
+	if len(library.Series) > 0 {
+		b.WriteString("Series (name / authors / owned volumes):\n")
+		for _, series := range library.Series {
+			fmt.Fprintf(&b, "- %s / %s / %d\n", series.Name, strings.Join(series.Authors, ", "), series.Volumes)
+		}
+	}
+	if len(library.Authors) > 0 {
+		fmt.Fprintf(&b, "Frequent authors: %s\n", strings.Join(library.Authors, ", "))
+	}
+	if len(library.Favorites) > 0 {
+		fmt.Fprintf(&b, "Favorites: %s\n", strings.Join(library.Favorites, ", "))
+	}
+	if len(library.Liked) > 0 {
+		fmt.Fprintf(&b, "Books the reader recommended to others: %s\n", strings.Join(library.Liked, ", "))
+	}
+	return b.String()
+}
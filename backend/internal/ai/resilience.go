@@ -0,0 +1,282 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 失敗の分類です。Metrics の集計キーとして使います。
+const (
+	FailureRateLimited  = "rate_limited"
+	FailureUnauthorized = "unauthorized"
+	FailureClientError  = "client_error"
+	FailureServerError  = "server_error"
+	FailureTimeout      = "timeout"
+	FailureNetwork      = "network"
+	FailureInvalidJSON  = "invalid_json"
+	FailureSchema       = "schema_violation"
+	FailureEmpty        = "empty_response"
+	FailureCircuitOpen  = "circuit_open"
+	FailureOther        = "other"
+)
+
+const (
+	// maxAttempts は 1 回の推定で API を呼び出す最大回数です。
+	maxAttempts = 3
+	baseBackoff = 500 * time.Millisecond
+	// maxRetryAfter より長い Retry-After が指定された場合は待たずに失敗を返し、呼び出し側で別のキーに切り替えます。
+	maxRetryAfter = 10 * time.Second
+)
+
+var ErrCircuitOpen = errors.New("openai circuit open")
+
+// FailureCategory はエラーを失敗の分類に変換します。
+func FailureCategory(err error) string {
+	var statusErr *StatusError
+	var parseErr *parseError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return FailureCircuitOpen
+	case errors.As(err, &statusErr):
+		switch {
+		case statusErr.StatusCode == http.StatusTooManyRequests:
+			return FailureRateLimited
+		case statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden:
+			return FailureUnauthorized
+		case statusErr.StatusCode >= 500:
+			return FailureServerError
+		default:
+			return FailureClientError
+		}
+	case errors.Is(err, ErrSchemaViolation):
+		return FailureSchema
+	case errors.As(err, &parseErr):
+		return FailureInvalidJSON
+	case errors.Is(err, errEmptyResponse):
+		return FailureEmpty
+	case errors.Is(err, context.DeadlineExceeded):
+		return FailureTimeout
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return FailureTimeout
+		}
+		return FailureNetwork
+	}
+	return FailureOther
+}
+
+// retryDelay は再試行するまでの待ち時間を返します。再試行すべきでないエラーの場合は false を返します。
+// 429 は Retry-After を優先し、キーの失効やリクエストの誤りは再試行しません。
+func retryDelay(err error, attempt int) (time.Duration, bool) {
+	backoff := baseBackoff << attempt
+	backoff += time.Duration(rand.Int63n(int64(baseBackoff)))
+	switch FailureCategory(err) {
+	case FailureRateLimited:
+		var statusErr *StatusError
+		errors.As(err, &statusErr)
+		if statusErr.RetryAfter > maxRetryAfter {
+			return 0, false
+		}
+		if statusErr.RetryAfter > backoff {
+			return statusErr.RetryAfter, true
+		}
+		return backoff, true
+	case FailureServerError, FailureTimeout, FailureNetwork, FailureInvalidJSON, FailureSchema, FailureEmpty:
+		return backoff, true
+	}
+	return 0, false
+}
+
+// tripsBreaker はサーキットブレーカーの失敗として数えるかを返します。
+// キー単位の失敗（401/429）は別のキーで回復できるため数えません。
+func tripsBreaker(category string) bool {
+	switch category {
+	case FailureServerError, FailureTimeout, FailureNetwork:
+		return true
+	}
+	return false
+}
+
+// Metrics は AI 呼び出しの成功数・再試行数と失敗の分類ごとの件数をプロセス内で集計します。
+type Metrics struct {
+	mu        sync.Mutex
+	requests  int64
+	successes int64
+	retries   int64
+	failures  map[string]int64
+}
+
+type MetricsSnapshot struct {
+	Requests  int64            `json:"requests"`
+	Successes int64            `json:"successes"`
+	Retries   int64            `json:"retries"`
+	Failures  map[string]int64 `json:"failures"`
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{failures: make(map[string]int64)}
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	failures := make(map[string]int64, len(m.failures))
+	for category, count := range m.failures {
+		failures[category] = count
+	}
+	return MetricsSnapshot{
+		Requests:  m.requests,
+		Successes: m.successes,
+		Retries:   m.retries,
+		Failures:  failures,
+	}
+}
+
+func (m *Metrics) recordRequest() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.requests++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) recordSuccess() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.successes++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) recordRetry() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.retries++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) recordFailure(category string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.failures[category]++
+	m.mu.Unlock()
+}
+
+// CircuitBreaker は連続した障害（5xx・タイムアウト・通信エラー）が threshold 回に達すると、
+// openFor の間 API を呼び出さずに ErrCircuitOpen を返します。期間が明けると 1 件だけ試し、成功すれば元に戻します。
+type CircuitBreaker struct {
+	threshold int
+	openFor   time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+type BreakerState struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenUntil           *time.Time `json:"openUntil,omitempty"`
+}
+
+func NewCircuitBreaker(threshold int, openFor time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if openFor <= 0 {
+		openFor = 30 * time.Second
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		openFor:   openFor,
+	}
+}
+
+func (b *CircuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+func (b *CircuitBreaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.probing = false
+}
+
+// done は障害として数えない結果（キー単位の失敗など）の後に、試行中の状態だけを解除します。
+func (b *CircuitBreaker) done() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *CircuitBreaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold || !b.openUntil.IsZero() {
+		b.openUntil = time.Now().Add(b.openFor)
+	}
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := BreakerState{State: "closed", ConsecutiveFailures: b.failures}
+	if !b.openUntil.IsZero() {
+		openUntil := b.openUntil
+		state.OpenUntil = &openUntil
+		state.State = "open"
+		if !time.Now().Before(openUntil) {
+			state.State = "half-open"
+		}
+	}
+	return state
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string   { return "net error" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return false }
+
+func TestFailureCategoryAndRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		attempt  int
+		category string
+		retry    bool
+		// exact が 0 以外なら待ち時間がその値であること、0 ならバックオフの範囲内であることを確かめる
+		exact time.Duration
+	}{
+		{name: "rate limited", err: &StatusError{StatusCode: http.StatusTooManyRequests}, category: FailureRateLimited, retry: true},
+		{name: "rate limited with retry-after", err: &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second}, category: FailureRateLimited, retry: true, exact: 5 * time.Second},
+		{name: "retry-after shorter than backoff", err: &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Millisecond}, attempt: 1, category: FailureRateLimited, retry: true},
+		{name: "retry-after too long", err: &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: maxRetryAfter + time.Second}, category: FailureRateLimited},
+		{name: "unauthorized", err: &StatusError{StatusCode: http.StatusUnauthorized}, category: FailureUnauthorized},
+		{name: "forbidden", err: &StatusError{StatusCode: http.StatusForbidden}, category: FailureUnauthorized},
+		{name: "bad request", err: &StatusError{StatusCode: http.StatusBadRequest}, category: FailureClientError},
+		{name: "server error", err: &StatusError{StatusCode: http.StatusBadGateway}, attempt: 2, category: FailureServerError, retry: true},
+		{name: "wrapped server error", err: fmt.Errorf("guess: %w", &StatusError{StatusCode: http.StatusInternalServerError}), category: FailureServerError, retry: true},
+		{name: "deadline", err: context.DeadlineExceeded, category: FailureTimeout, retry: true},
+		{name: "net timeout", err: timeoutError{timeout: true}, category: FailureTimeout, retry: true},
+		{name: "network", err: timeoutError{}, category: FailureNetwork, retry: true},
+		{name: "invalid json", err: &parseError{err: errors.New("unexpected end"), text: "{"}, category: FailureInvalidJSON, retry: true},
+		{name: "schema violation", err: ErrSchemaViolation, category: FailureSchema, retry: true},
+		{name: "empty response", err: errEmptyResponse, category: FailureEmpty, retry: true},
+		{name: "circuit open", err: ErrCircuitOpen, category: FailureCircuitOpen},
+		{name: "canceled", err: context.Canceled, category: FailureOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FailureCategory(tt.err); got != tt.category {
+				t.Errorf("FailureCategory = %q, want %q", got, tt.category)
+			}
+			delay, retry := retryDelay(tt.err, tt.attempt)
+			if retry != tt.retry {
+				t.Fatalf("retry = %v, want %v", retry, tt.retry)
+			}
+			switch {
+			case !retry:
+				if delay != 0 {
+					t.Errorf("delay = %v, want 0", delay)
+				}
+			case tt.exact != 0:
+				if delay != tt.exact {
+					t.Errorf("delay = %v, want %v", delay, tt.exact)
+				}
+			default:
+				low := baseBackoff << tt.attempt
+				if delay < low || delay >= low+baseBackoff {
+					t.Errorf("delay = %v, want [%v, %v)", delay, low, low+baseBackoff)
+				}
+			}
+		})
+	}
+}
+
+func TestTripsBreaker(t *testing.T) {
+	for _, category := range []string{FailureServerError, FailureTimeout, FailureNetwork} {
+		if !tripsBreaker(category) {
+			t.Errorf("tripsBreaker(%q) = false", category)
+		}
+	}
+	for _, category := range []string{FailureRateLimited, FailureUnauthorized, FailureClientError, FailureInvalidJSON, FailureSchema} {
+		if tripsBreaker(category) {
+			t.Errorf("tripsBreaker(%q) = true", category)
+		}
+	}
+}
+
+func TestCircuitBreakerOpenProbeClose(t *testing.T) {
+	const openFor = 50 * time.Millisecond
+	breaker := NewCircuitBreaker(2, openFor)
+
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("closed breaker: allow = %v", err)
+	}
+	breaker.failure()
+	if state := breaker.State(); state.State != "closed" || state.ConsecutiveFailures != 1 {
+		t.Fatalf("after 1 failure: state = %+v", state)
+	}
+	// キー単位の失敗は数えない
+	breaker.done()
+	breaker.failure()
+	if state := breaker.State(); state.State != "open" || state.OpenUntil == nil {
+		t.Fatalf("after threshold: state = %+v", state)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("open breaker: allow = %v, want ErrCircuitOpen", err)
+	}
+
+	time.Sleep(openFor + 10*time.Millisecond)
+	if state := breaker.State(); state.State != "half-open" {
+		t.Fatalf("after openFor: state = %+v", state)
+	}
+	// 期間が明けたら 1 件だけ試す
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("probe: allow = %v", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("during probe: allow = %v, want ErrCircuitOpen", err)
+	}
+	// 試行が失敗すれば再び開く
+	breaker.failure()
+	if state := breaker.State(); state.State != "open" {
+		t.Fatalf("failed probe: state = %+v", state)
+	}
+
+	time.Sleep(openFor + 10*time.Millisecond)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("second probe: allow = %v", err)
+	}
+	breaker.success()
+	if state := breaker.State(); state.State != "closed" || state.ConsecutiveFailures != 0 || state.OpenUntil != nil {
+		t.Fatalf("after success: state = %+v", state)
+	}
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("closed again: allow = %v", err)
+	}
+}
+
+func TestCircuitBreakerProbeReleasedByDone(t *testing.T) {
+	const openFor = 20 * time.Millisecond
+	breaker := NewCircuitBreaker(1, openFor)
+	breaker.failure()
+	time.Sleep(openFor + 10*time.Millisecond)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("probe: allow = %v", err)
+	}
+	// 試行がキー単位の失敗で終わった場合は、開いたまま次の試行を許す
+	breaker.done()
+	if state := breaker.State(); state.State != "half-open" {
+		t.Fatalf("after done: state = %+v", state)
+	}
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("next probe: allow = %v", err)
+	}
+}
+
+func TestNilCircuitBreakerAndMetrics(t *testing.T) {
+	var breaker *CircuitBreaker
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("nil breaker: allow = %v", err)
+	}
+	breaker.failure()
+	breaker.success()
+	breaker.done()
+
+	var metrics *Metrics
+	metrics.recordRequest()
+	metrics.recordRetry()
+	metrics.recordSuccess()
+	metrics.recordFailure(FailureOther)
+}
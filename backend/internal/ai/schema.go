@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrSchemaViolation は応答が JSON スキーマを満たしていないことを表します。
+var ErrSchemaViolation = errors.New("openai response does not match schema")
+
+// seriesGuessSchema はシリーズ推定の応答の JSON スキーマです。
+// OpenAI 本体には response_format として渡し、互換サーバーを含むすべての応答をこのスキーマで検証します。
+// strict モードで使えないキーワードは含めず、値の範囲は読み込んだ後に確認します。
+var seriesGuessSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"isSeries":     map[string]any{"type": "boolean"},
+		"seriesName":   map[string]any{"type": "string"},
+		"volumeNumber": map[string]any{"type": "integer"},
+		"confidence":   map[string]any{"type": "integer"},
+	},
+	"required":             []string{"isSeries", "seriesName", "volumeNumber", "confidence"},
+	"additionalProperties": false,
+}
+
+// bookSuggestionsSchema は「次に読む本」の応答の JSON スキーマです。
+var bookSuggestionsSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"items": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"title":  map[string]any{"type": "string"},
+					"author": map[string]any{"type": "string"},
+					"reason": map[string]any{"type": "string"},
+				},
+				"required":             []string{"title", "author", "reason"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"items"},
+	"additionalProperties": false,
+}
+
+// decodeWithSchema はコードフェンスを取り除いた応答を schema で検証してから dst に読み込みます。
+// JSON として読めない場合は invalid_json、スキーマ違反の場合は ErrSchemaViolation を返します。
+func decodeWithSchema(text string, schema map[string]any, dst any) error {
+	text = stripCodeFence(text)
+	var value any
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return &parseError{err: err, text: text}
+	}
+	if err := validateSchema(value, schema, "$"); err != nil {
+		return fmt.Errorf("%w: %v", ErrSchemaViolation, err)
+	}
+	if err := json.Unmarshal([]byte(text), dst); err != nil {
+		return &parseError{err: err, text: text}
+	}
+	return nil
+}
+
+type parseError struct {
+	err  error
+	text string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("openai parse error: %v text=%q", e.err, e.text)
+}
+
+func (e *parseError) Unwrap() error {
+	return e.err
+}
+
+// validateSchema は type / properties / required / items のみを扱う簡易的な検証です。
+// additionalProperties は OpenAI の strict モード用の指定で、余分なキーは無視して読み込むため検証しません。
+func validateSchema(value any, schema map[string]any, path string) error {
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		if required, ok := schema["required"].([]string); ok {
+			for _, key := range required {
+				if _, exists := object[key]; !exists {
+					return fmt.Errorf("%s.%s: required", path, key)
+				}
+			}
+		}
+		for key, field := range object {
+			propertySchema, ok := properties[key].(map[string]any)
+			if !ok {
+				continue
+			}
+			if err := validateSchema(field, propertySchema, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		for i, item := range items {
+			if itemSchema == nil {
+				break
+			}
+			if err := validateSchema(item, itemSchema, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: expected %s", path, schema["type"])
+		}
+		if schema["type"] == "integer" && number != math.Trunc(number) {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+	}
+	return nil
+}
+
+// stripCodeFence は ```json ... ``` で囲まれた応答から本文を取り出します。
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	if newline := strings.Index(text, "\n"); newline >= 0 {
+		text = text[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
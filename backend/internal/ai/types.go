@@ -1,10 +1,19 @@
 package ai
 
+import "context"
+
+// SeriesClassifier は書誌情報からシリーズ名と巻数を推定します。
+type SeriesClassifier interface {
+	GuessSeries(ctx context.Context, input SeriesInput) (SeriesGuess, error)
+}
+
 type SeriesGuess struct {
 	IsSeries     bool   `json:"isSeries"`
 	Name         string `json:"seriesName"`
 	VolumeNumber int    `json:"volumeNumber"`
 	Confidence   int    `json:"confidence"`
+	// Source は推定元（"local" / "openai"）です。レスポンスの JSON には含まれません。
+	Source string `json:"-"`
 }
 
 type SeriesInput struct {
@@ -0,0 +1,191 @@
+package aiusage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/repository"
+)
+
+// EnvKeyID は環境変数 OPENAI_API_KEY のキーを使った記録のキー ID です。
+const EnvKeyID = "env"
+
+// price は 100 万トークンあたりの料金（100 万分の 1 ドル単位）です。
+type price struct {
+	input  int64
+	output int64
+}
+
+// modelPrices は推定コストの算出に使う料金表です。日付付きのモデル名は前方一致で引きます。
+var modelPrices = map[string]price{
+	"gpt-4o-mini":  {input: 150_000, output: 600_000},
+	"gpt-4o":       {input: 2_500_000, output: 10_000_000},
+	"gpt-4.1-nano": {input: 100_000, output: 400_000},
+	"gpt-4.1-mini": {input: 400_000, output: 1_600_000},
+	"gpt-4.1":      {input: 2_000_000, output: 8_000_000},
+	"o3-mini":      {input: 1_100_000, output: 4_400_000},
+	"o4-mini":      {input: 1_100_000, output: 4_400_000},
+}
+
+// Bucket は集計キーごとの使用量と推定コストです。
+type Bucket struct {
+	Key              string  `json:"key"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalTokens      int     `json:"totalTokens"`
+	EstimatedCostUSD float64 `json:"estimatedCostUsd"`
+}
+
+type Summary struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Total   Bucket    `json:"total"`
+	ByKey   []Bucket  `json:"byKey"`
+	ByUser  []Bucket  `json:"byUser"`
+	ByModel []Bucket  `json:"byModel"`
+}
+
+type Service struct {
+	repo            repository.OpenAIUsageRepository
+	userDailyTokens int
+}
+
+// NewService は userDailyTokens が 0 以下の場合、ユーザーごとの上限なしで動作します。
+func NewService(repo repository.OpenAIUsageRepository, userDailyTokens int) *Service {
+	return &Service{
+		repo:            repo,
+		userDailyTokens: userDailyTokens,
+	}
+}
+
+// Record は 1 回の呼び出しの使用量を推定コストとともに保存します。
+func (s *Service) Record(keyID, userID string, usage ai.Usage) error {
+	return s.repo.Create(domain.OpenAIUsage{
+		ID:               idgen.NewOpenAIUsage(),
+		KeyID:            keyID,
+		UserID:           userID,
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostMicroUSD:     EstimateCostMicroUSD(usage.Model, usage.PromptTokens, usage.CompletionTokens),
+		CreatedAt:        time.Now(),
+	})
+}
+
+// AllowUser はユーザーが当日のトークン上限に達していないかを返します。
+func (s *Service) AllowUser(userID string) bool {
+	if s.userDailyTokens <= 0 {
+		return true
+	}
+	return s.UserTokensToday(userID) < s.userDailyTokens
+}
+
+func (s *Service) UserTokensToday(userID string) int {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return s.repo.TotalsByUserSince(userID, start).TotalTokens
+}
+
+// KeySpentThisMonthMicroUSD はキーの当月の推定コストを返します。
+func (s *Service) KeySpentThisMonthMicroUSD(keyID string) int64 {
+	return s.repo.TotalsByKeySince(keyID, monthStart(time.Now())).CostMicroUSD
+}
+
+// KeyTokensThisMonth はキーの当月のトークン使用量を返します。
+func (s *Service) KeyTokensThisMonth(keyID string) int {
+	return s.repo.TotalsByKeySince(keyID, monthStart(time.Now())).TotalTokens
+}
+
+// KeyWithinBudget はキーが当月の予算内かを返します。予算が未設定のキーは常に true です。
+func (s *Service) KeyWithinBudget(key domain.OpenAIKey) bool {
+	if key.MonthlyBudgetMicroUSD <= 0 {
+		return true
+	}
+	return s.KeySpentThisMonthMicroUSD(key.ID) < key.MonthlyBudgetMicroUSD
+}
+
+// Summary は from 以上 to 未満の使用量をキー・ユーザー・モデルごとに集計します。
+// 集計はリポジトリ（DB）で行い、全体の合計はキーごとの合計から求めます。
+func (s *Service) Summary(from, to time.Time) Summary {
+	summary := Summary{From: from, To: to}
+	byKey := s.repo.TotalsBetween(from, to, repository.OpenAIUsageByKey)
+	var total repository.OpenAIUsageTotals
+	for _, totals := range byKey {
+		total.Requests += totals.Requests
+		total.PromptTokens += totals.PromptTokens
+		total.CompletionTokens += totals.CompletionTokens
+		total.TotalTokens += totals.TotalTokens
+		total.CostMicroUSD += totals.CostMicroUSD
+	}
+	summary.Total = newBucket("total", total)
+	summary.ByKey = sortedBuckets(byKey)
+	summary.ByUser = sortedBuckets(s.repo.TotalsBetween(from, to, repository.OpenAIUsageByUser))
+	summary.ByModel = sortedBuckets(s.repo.TotalsBetween(from, to, repository.OpenAIUsageByModel))
+	return summary
+}
+
+// EstimateCostMicroUSD は料金表からコストを推定します。料金表にないモデルは 0 になります。
+func EstimateCostMicroUSD(model string, promptTokens, completionTokens int) int64 {
+	p, ok := priceFor(model)
+	if !ok {
+		return 0
+	}
+	return (int64(promptTokens)*p.input + int64(completionTokens)*p.output) / 1_000_000
+}
+
+func MicroUSDToUSD(value int64) float64 {
+	return float64(value) / 1_000_000
+}
+
+func USDToMicroUSD(value float64) int64 {
+	return int64(value * 1_000_000)
+}
+
+func priceFor(model string) (price, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if p, ok := modelPrices[model]; ok {
+		return p, true
+	}
+	best, bestLen := price{}, 0
+	for name, p := range modelPrices {
+		if strings.HasPrefix(model, name+"-") && len(name) > bestLen {
+			best, bestLen = p, len(name)
+		}
+	}
+	return best, bestLen > 0
+}
+
+func monthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+func newBucket(key string, totals repository.OpenAIUsageTotals) Bucket {
+	return Bucket{
+		Key:              key,
+		Requests:         totals.Requests,
+		PromptTokens:     totals.PromptTokens,
+		CompletionTokens: totals.CompletionTokens,
+		TotalTokens:      totals.TotalTokens,
+		EstimatedCostUSD: MicroUSDToUSD(totals.CostMicroUSD),
+	}
+}
+
+func sortedBuckets(totals map[string]repository.OpenAIUsageTotals) []Bucket {
+	items := make([]Bucket, 0, len(totals))
+	for key, item := range totals {
+		items = append(items, newBucket(key, item))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TotalTokens != items[j].TotalTokens {
+			return items[i].TotalTokens > items[j].TotalTokens
+		}
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
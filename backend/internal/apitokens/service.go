@@ -0,0 +1,173 @@
+package apitokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/repository"
+)
+
+var (
+	ErrTokenNotFound = errors.New("api token not found")
+	ErrInvalidToken  = errors.New("invalid api token")
+	ErrInvalidName   = errors.New("invalid api token name")
+	ErrInvalidScope  = errors.New("invalid api token scope")
+	ErrInvalidExpiry = errors.New("invalid api token expiry")
+	ErrTooManyTokens = errors.New("too many api tokens")
+)
+
+const (
+	// TokenPrefix は個人用アクセストークンの接頭辞です。Bearer トークンの種類の判別に使います。
+	TokenPrefix         = "bmpat_"
+	tokenBytes          = 32
+	displayPrefixLength = len(TokenPrefix) + 6
+
+	NameMaxLength        = 100
+	DefaultExpiresInDays = 90
+	MaxExpiresInDays     = 365
+	MaxActiveTokens      = 20
+
+	// lastUsedInterval より短い間隔の利用では最終利用日時を更新しません（リクエストごとの書き込みを避けるため）。
+	lastUsedInterval = time.Minute
+)
+
+// Service は個人用アクセストークンの発行・検証・失効を扱います。
+type Service struct {
+	repo repository.APITokenRepository
+	now  func() time.Time
+}
+
+func NewService(repo repository.APITokenRepository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// IsAPIToken は Bearer トークンが個人用アクセストークンの形式かを判定します。
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
+
+// Create はトークンを発行し、記録と平文のトークンを返します。平文はこの時だけ返します。
+func (s *Service) Create(userID, name, scope string, expiresInDays int) (domain.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > NameMaxLength {
+		return domain.APIToken{}, "", ErrInvalidName
+	}
+	if scope == "" {
+		scope = domain.APITokenScopeRead
+	}
+	if scope != domain.APITokenScopeRead && scope != domain.APITokenScopeReadWrite {
+		return domain.APIToken{}, "", ErrInvalidScope
+	}
+	if expiresInDays == 0 {
+		expiresInDays = DefaultExpiresInDays
+	}
+	if expiresInDays < 1 || expiresInDays > MaxExpiresInDays {
+		return domain.APIToken{}, "", ErrInvalidExpiry
+	}
+	if len(s.List(userID)) >= MaxActiveTokens {
+		return domain.APIToken{}, "", ErrTooManyTokens
+	}
+	raw, err := generateToken()
+	if err != nil {
+		return domain.APIToken{}, "", err
+	}
+	now := s.now()
+	token := domain.APIToken{
+		ID:        idgen.NewAPIToken(),
+		UserID:    userID,
+		Name:      name,
+		Scope:     scope,
+		TokenHash: hashToken(raw),
+		Prefix:    raw[:displayPrefixLength],
+		CreatedAt: now,
+		ExpiresAt: now.AddDate(0, 0, expiresInDays),
+	}
+	if err := s.repo.Create(token); err != nil {
+		return domain.APIToken{}, "", err
+	}
+	return token, raw, nil
+}
+
+// List はユーザーの有効な（失効・期限切れでない）トークンを作成日時の新しい順に返します。
+func (s *Service) List(userID string) []domain.APIToken {
+	now := s.now()
+	items := s.repo.ListByUser(userID)
+	active := make([]domain.APIToken, 0, len(items))
+	for _, token := range items {
+		if token.RevokedAt == nil && now.Before(token.ExpiresAt) {
+			active = append(active, token)
+		}
+	}
+	return active
+}
+
+// Revoke はユーザー自身のトークンを失効させます。
+func (s *Service) Revoke(userID, id string) error {
+	token, ok := s.repo.FindByID(id)
+	if !ok || token.UserID != userID {
+		return ErrTokenNotFound
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+	now := s.now()
+	token.RevokedAt = &now
+	if !s.repo.Update(token) {
+		return errors.New("failed to revoke api token")
+	}
+	return nil
+}
+
+// Authenticate は Bearer トークンを検証し、有効なトークンの記録を返します。
+// 最終利用日時は lastUsedInterval ごとに更新します。
+func (s *Service) Authenticate(raw string) (domain.APIToken, error) {
+	if !IsAPIToken(raw) {
+		return domain.APIToken{}, ErrInvalidToken
+	}
+	token, ok := s.repo.FindByHash(hashToken(raw))
+	if !ok || token.RevokedAt != nil {
+		return domain.APIToken{}, ErrInvalidToken
+	}
+	now := s.now()
+	if !now.Before(token.ExpiresAt) {
+		return domain.APIToken{}, ErrInvalidToken
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedInterval {
+		token.LastUsedAt = &now
+		s.repo.Update(token)
+	}
+	return token, nil
+}
+
+// Allows はトークンのスコープでリクエストのメソッドを実行できるかを返します。
+// 読み取り専用のトークンは GET / HEAD のみ許可します。
+func Allows(scope, method string) bool {
+	if scope == domain.APITokenScopeReadWrite {
+		return true
+	}
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func generateToken() (string, error) {
+	bytes := make([]byte, tokenBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
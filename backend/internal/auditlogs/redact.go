@@ -0,0 +1,108 @@
+package auditlogs
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+const (
+	// Redacted は監査ログに残さない値の置き換え文字列です。
+	Redacted = "[REDACTED]"
+	// omittedBody は /auth/* など、ボディ全体を残さないリクエストの置き換え文字列です。
+	omittedBody = "[omitted]"
+)
+
+// sensitiveKeyParts はキー名（小文字）に含まれていれば値をマスクする語です。
+var sensitiveKeyParts = []string{"password", "token", "secret", "apikey", "api_key"}
+
+// RedactBody は監査ログに残すリクエストボディから認証情報を取り除きます。
+// /auth/* はパスワード・トークンの受け渡しが中心のためボディを残さず、
+// それ以外は JSON のキー名でマスクします。JSON として読めないボディは残しません。
+func RedactBody(path, body string) string {
+	if body == "" {
+		return ""
+	}
+	if strings.HasPrefix(path, "/auth/") {
+		return omittedBody
+	}
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return omittedBody
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return omittedBody
+	}
+	return string(redacted)
+}
+
+// RedactQuery はクエリ文字列のうち認証情報にあたるパラメーターをマスクします。
+func RedactQuery(query url.Values) url.Values {
+	redacted := make(url.Values, len(query))
+	for key, values := range query {
+		if isSensitiveKey(key) {
+			redacted[key] = []string{Redacted}
+			continue
+		}
+		redacted[key] = values
+	}
+	return redacted
+}
+
+// RedactPayload は保存済みの payload に同じマスクを適用します。
+// マスクを導入する前に記録された行を管理画面やエクスポートで返さないために、読み出し時にも使います。
+func RedactPayload(payload map[string]any) map[string]any {
+	if payload == nil {
+		return nil
+	}
+	path, _ := payload["path"].(string)
+	redacted := make(map[string]any, len(payload))
+	for key, value := range payload {
+		switch key {
+		case "body":
+			body, _ := value.(string)
+			redacted[key] = RedactBody(path, body)
+		case "query":
+			redacted[key] = redactValue(value)
+		default:
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(typed))
+		for key, item := range typed {
+			if isSensitiveKey(key) {
+				redacted[key] = Redacted
+				continue
+			}
+			redacted[key] = redactValue(item)
+		}
+		return redacted
+	case url.Values:
+		return RedactQuery(typed)
+	case []any:
+		redacted := make([]any, 0, len(typed))
+		for _, item := range typed {
+			redacted = append(redacted, redactValue(item))
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package auditlogs
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidAction = errors.New("invalid action")
+	ErrInvalidRange  = errors.New("from must be before to")
+	ErrTooManyRows   = errors.New("too many rows to export")
+)
+
+const (
+	// ExportMaxRows はエクスポート 1 回あたりの上限です。超える場合は期間を絞ってもらいます。
+	ExportMaxRows   = 100000
+	exportBatchSize = 500
+)
+
+// actions は監査ミドルウェアが HTTP メソッドから記録する操作です。
+var actions = map[string]bool{
+	"create": true,
+	"read":   true,
+	"update": true,
+	"delete": true,
+}
+
+// Filter は監査ログの絞り込み条件です。空の項目・ゼロ値の日時では絞り込みません。
+type Filter struct {
+	UserID   string
+	Entity   string
+	EntityID string
+	Action   string
+	From     time.Time // 以上
+	To       time.Time // 未満
+}
+
+// Page は新しい順の 1 ページ分の記録です。NextCursor が空の場合は最後のページです。
+type Page struct {
+	Items      []domain.AuditLog
+	NextCursor string
+}
+
+type Service struct {
+	repo repository.AuditLogRepository
+}
+
+func NewService(repo repository.AuditLogRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Search は cursor（前のページの NextCursor、先頭ページは空）の続きから最大 limit 件を返します。
+func (s *Service) Search(filter Filter, cursor string, limit int) (Page, error) {
+	query, err := buildQuery(filter)
+	if err != nil {
+		return Page{}, err
+	}
+	if cursor != "" {
+		at, id, err := decodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		query.BeforeAt, query.BeforeID = at, id
+	}
+	// 1 件多く取得して次のページがあるかを判定する
+	query.Limit = limit + 1
+	items := redactItems(s.repo.Query(query))
+	page := Page{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// Export は条件に一致する記録を新しい順に write へ渡します。
+// 件数が ExportMaxRows を超える場合は何も書き出さずに ErrTooManyRows を返します。
+func (s *Service) Export(filter Filter, write func(domain.AuditLog) error) error {
+	query, err := buildQuery(filter)
+	if err != nil {
+		return err
+	}
+	// 書き出しを始めた後ではエラーを返せないため、先に件数を確認する
+	if s.repo.Count(query) > ExportMaxRows {
+		return ErrTooManyRows
+	}
+	query.Limit = exportBatchSize
+	for {
+		items := redactItems(s.repo.Query(query))
+		for _, item := range items {
+			if err := write(item); err != nil {
+				return err
+			}
+		}
+		if len(items) < exportBatchSize {
+			return nil
+		}
+		last := items[len(items)-1]
+		query.BeforeAt, query.BeforeID = last.CreatedAt, last.ID
+	}
+}
+
+// redactItems はマスク導入前に記録された行の payload もマスクしてから返します。
+func redactItems(items []domain.AuditLog) []domain.AuditLog {
+	for i := range items {
+		items[i].Payload = RedactPayload(items[i].Payload)
+	}
+	return items
+}
+
+func buildQuery(filter Filter) (repository.AuditLogQuery, error) {
+	action := strings.ToLower(strings.TrimSpace(filter.Action))
+	if action != "" && !actions[action] {
+		return repository.AuditLogQuery{}, ErrInvalidAction
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return repository.AuditLogQuery{}, ErrInvalidRange
+	}
+	return repository.AuditLogQuery{
+		UserID:   strings.TrimSpace(filter.UserID),
+		Entity:   strings.ToLower(strings.TrimSpace(filter.Entity)),
+		EntityID: strings.TrimSpace(filter.EntityID),
+		Action:   action,
+		From:     filter.From,
+		To:       filter.To,
+	}, nil
+}
+
+// encodeCursor は最後の記録の作成日時（UnixNano）と ID を不透明な文字列にします。
+func encodeCursor(at time.Time, id string) string {
+	raw := strconv.FormatInt(at.UnixNano(), 10) + ":" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	value, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return time.Unix(0, value), id, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"book_manager/backend/internal/authctx"
+)
+
+const (
+	ModeFirebase = "firebase"
+	ModeLocal    = "local"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrEmailNotFound      = errors.New("email not found")
+	ErrEmailExists        = errors.New("email already exists")
+	ErrUserIDExists       = errors.New("user id already exists")
+	ErrWeakPassword       = errors.New("weak password")
+	ErrInvalidEmail       = errors.New("invalid email")
+	ErrTooManyAttempts    = errors.New("too many attempts")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrUnsupported        = errors.New("not supported by auth backend")
+)
+
+// Session はサインアップ・ログイン・リフレッシュの結果としてクライアントへ返すトークンとユーザー情報です。
+type Session struct {
+	AccessToken   string
+	RefreshToken  string
+	UID           string
+	Email         string
+	DisplayName   string
+	EmailVerified bool
+}
+
+// Backend は /auth/* ハンドラーと認証ミドルウェアが利用する認証バックエンドです。
+// Firebase Authentication（FirebaseBackend）と自前のパスワード認証（Service）を AUTH_MODE で切り替えます。
+type Backend interface {
+	Mode() string
+	SignUp(ctx context.Context, email, password, userID, displayName string) (Session, error)
+	Login(ctx context.Context, email, password string) (Session, error)
+	Refresh(ctx context.Context, refreshToken string) (Session, error)
+	Logout(ctx context.Context, refreshToken string) error
+	ResendVerification(ctx context.Context, refreshToken string) error
+	VerifyEmail(ctx context.Context, token string) error
+	UpdateEmail(ctx context.Context, refreshToken, email string) (Session, error)
+	// SetVerifiedEmail は新しいアドレスでの確認が済んだメールアドレス変更を反映します。
+	SetVerifiedEmail(ctx context.Context, uid, email string) error
+	// ChangePassword は現在のパスワードを確認してから変更し、新しいセッションを返します。
+	// ほかのリフレッシュトークンはすべて失効します。
+	ChangePassword(ctx context.Context, refreshToken, currentPassword, newPassword string) (Session, error)
+	VerifyAccessToken(ctx context.Context, token string) (authctx.AuthInfo, error)
+	DeleteUser(ctx context.Context, uid string) error
+	RevokeRefreshTokens(ctx context.Context, uid string) error
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"book_manager/backend/internal/authctx"
+	"book_manager/backend/internal/firebaseauth"
+)
+
+// FirebaseBackend は Firebase Authentication の REST API と Admin SDK を Backend として扱うアダプターです。
+type FirebaseBackend struct {
+	client   *firebaseauth.Client
+	verifier *firebaseauth.Verifier
+	admin    *firebaseauth.AdminClient
+}
+
+func NewFirebaseBackend(client *firebaseauth.Client, verifier *firebaseauth.Verifier, admin *firebaseauth.AdminClient) *FirebaseBackend {
+	return &FirebaseBackend{
+		client:   client,
+		verifier: verifier,
+		admin:    admin,
+	}
+}
+
+func (b *FirebaseBackend) Mode() string {
+	return ModeFirebase
+}
+
+func (b *FirebaseBackend) SignUp(ctx context.Context, email, password, userID, displayName string) (Session, error) {
+	result, err := b.client.SignUp(email, password, userID)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	if err := b.client.SendEmailVerification(result.IDToken); err != nil {
+		// メール送信に失敗してもサインアップ処理は続行する（ユーザーは後で再送信できる）
+		log.Printf("WARNING: failed to send verification email to %s: %v", email, err)
+	}
+	return Session{
+		AccessToken:  result.IDToken,
+		RefreshToken: result.RefreshToken,
+		UID:          result.LocalID,
+		Email:        email,
+		DisplayName:  result.DisplayName,
+	}, nil
+}
+
+func (b *FirebaseBackend) Login(ctx context.Context, email, password string) (Session, error) {
+	result, err := b.client.Login(email, password)
+	if err != nil {
+		// 登録済みのメールアドレスかを推測されないよう、未登録もパスワード誤りと同じエラーにする
+		if errors.Is(err, firebaseauth.ErrEmailNotFound) {
+			return Session{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+		}
+		return Session{}, mapFirebaseError(err)
+	}
+	info, err := b.verifier.VerifyIDToken(ctx, result.IDToken)
+	if err != nil {
+		log.Printf("WARNING: ID token verification failed after successful login: %v", err)
+		return Session{}, ErrInvalidToken
+	}
+	return Session{
+		AccessToken:   result.IDToken,
+		RefreshToken:  result.RefreshToken,
+		UID:           result.LocalID,
+		Email:         result.Email,
+		DisplayName:   result.DisplayName,
+		EmailVerified: info.EmailVerified,
+	}, nil
+}
+
+func (b *FirebaseBackend) Refresh(ctx context.Context, refreshToken string) (Session, error) {
+	result, err := b.client.Refresh(refreshToken)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	return Session{
+		AccessToken:  result.IDToken,
+		RefreshToken: result.RefreshToken,
+		UID:          result.LocalID,
+		Email:        result.Email,
+	}, nil
+}
+
+func (b *FirebaseBackend) Logout(ctx context.Context, refreshToken string) error {
+	// リフレッシュトークンからユーザーIDを取得
+	result, err := b.client.Refresh(refreshToken)
+	if err != nil {
+		if errors.Is(err, firebaseauth.ErrInvalidCredentials) {
+			// トークンが既に無効な場合は成功として扱う
+			return nil
+		}
+		return err
+	}
+	// Firebase Admin SDKでリフレッシュトークンを失効
+	// 注: 失効に失敗してもログアウト自体は成功として扱う
+	// 理由: クライアント側でトークンを削除すれば実質的にログアウトとなり、
+	// ユーザー体験を優先する（Admin SDKが利用不可でも動作させる）
+	if b.admin != nil {
+		if err := b.admin.RevokeRefreshTokens(ctx, result.LocalID); err != nil {
+			log.Printf("WARNING: failed to revoke refresh tokens for user %s: %v", result.LocalID, err)
+		}
+	}
+	return nil
+}
+
+func (b *FirebaseBackend) ResendVerification(ctx context.Context, refreshToken string) error {
+	result, err := b.client.Refresh(refreshToken)
+	if err != nil {
+		return mapFirebaseError(err)
+	}
+	return b.client.SendEmailVerification(result.IDToken)
+}
+
+// VerifyEmail は Firebase では確認リンクを Firebase 側が処理するため対応しません。
+func (b *FirebaseBackend) VerifyEmail(ctx context.Context, token string) error {
+	return ErrUnsupported
+}
+
+func (b *FirebaseBackend) UpdateEmail(ctx context.Context, refreshToken, email string) (Session, error) {
+	refreshed, err := b.client.Refresh(refreshToken)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	updated, err := b.client.UpdateEmail(refreshed.IDToken, email)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	if err := b.client.SendEmailVerification(updated.IDToken); err != nil {
+		return Session{}, err
+	}
+	return Session{
+		AccessToken:  updated.IDToken,
+		RefreshToken: updated.RefreshToken,
+		UID:          updated.LocalID,
+		Email:        email,
+	}, nil
+}
+
+func (b *FirebaseBackend) SetVerifiedEmail(ctx context.Context, uid, email string) error {
+	if b.admin == nil {
+		return errors.New("firebase admin client is not configured")
+	}
+	if err := b.admin.SetVerifiedEmail(ctx, uid, email); err != nil {
+		return mapFirebaseError(err)
+	}
+	return nil
+}
+
+// ChangePassword は現在のパスワードで再ログインして確認してから変更します。
+// Firebase はパスワード変更時に既存のリフレッシュトークンを失効させます。
+func (b *FirebaseBackend) ChangePassword(ctx context.Context, refreshToken, currentPassword, newPassword string) (Session, error) {
+	refreshed, err := b.client.Refresh(refreshToken)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	info, err := b.verifier.VerifyIDToken(ctx, refreshed.IDToken)
+	if err != nil {
+		return Session{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	current, err := b.client.Login(info.Email, currentPassword)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	if current.LocalID != refreshed.LocalID {
+		return Session{}, ErrInvalidCredentials
+	}
+	updated, err := b.client.UpdatePassword(current.IDToken, newPassword)
+	if err != nil {
+		return Session{}, mapFirebaseError(err)
+	}
+	return Session{
+		AccessToken:   updated.IDToken,
+		RefreshToken:  updated.RefreshToken,
+		UID:           updated.LocalID,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+	}, nil
+}
+
+func (b *FirebaseBackend) VerifyAccessToken(ctx context.Context, token string) (authctx.AuthInfo, error) {
+	info, err := b.verifier.VerifyIDToken(ctx, token)
+	if err != nil {
+		return authctx.AuthInfo{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return authctx.AuthInfo{
+		UserID:        info.UserID,
+		Email:         info.Email,
+		Name:          info.Name,
+		EmailVerified: info.EmailVerified,
+	}, nil
+}
+
+func (b *FirebaseBackend) DeleteUser(ctx context.Context, uid string) error {
+	if b.admin == nil {
+		return errors.New("firebase admin client is not configured")
+	}
+	return b.admin.DeleteUser(ctx, uid)
+}
+
+// RevokeRefreshTokens はユーザーのすべてのリフレッシュトークンを失効させます。
+// Firebase はトークン単位の失効に対応していないため、常にユーザー単位で失効します。
+func (b *FirebaseBackend) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	if b.admin == nil {
+		return errors.New("firebase admin client is not configured")
+	}
+	return b.admin.RevokeRefreshTokens(ctx, uid)
+}
+
+func mapFirebaseError(err error) error {
+	var mapped error
+	switch {
+	case errors.Is(err, firebaseauth.ErrEmailExists):
+		mapped = ErrEmailExists
+	case errors.Is(err, firebaseauth.ErrEmailNotFound):
+		mapped = ErrEmailNotFound
+	case errors.Is(err, firebaseauth.ErrInvalidCredentials):
+		mapped = ErrInvalidCredentials
+	case errors.Is(err, firebaseauth.ErrWeakPassword):
+		mapped = ErrWeakPassword
+	case errors.Is(err, firebaseauth.ErrInvalidEmail):
+		mapped = ErrInvalidEmail
+	case errors.Is(err, firebaseauth.ErrTooManyAttempts):
+		mapped = ErrTooManyAttempts
+	default:
+		return err
+	}
+	return fmt.Errorf("%w: %v", mapped, err)
+}
+
+var _ Backend = (*FirebaseBackend)(nil)
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type accessClaims struct {
+	Iss string `json:"iss"`
+	Sub string `json:"sub"`
+	Sid string `json:"sid"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// signAccessToken は HS256 で署名したアクセストークン（JWT）を発行します。
+func signAccessToken(secret []byte, claims accessClaims) (string, error) {
+	header, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(hmacSHA256(secret, signingInput)), nil
+}
+
+// parseAccessToken は署名・発行者・有効期限を検証してクレームを返します。
+func parseAccessToken(secret []byte, issuer, token string, now time.Time) (accessClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return accessClaims{}, ErrInvalidToken
+	}
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return accessClaims{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return accessClaims{}, ErrInvalidToken
+	}
+	if !hmac.Equal(signature, hmacSHA256(secret, parts[0]+"."+parts[1])) {
+		return accessClaims{}, ErrInvalidToken
+	}
+	var claims accessClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return accessClaims{}, ErrInvalidToken
+	}
+	if claims.Sub == "" || claims.Iss != issuer {
+		return accessClaims{}, ErrInvalidToken
+	}
+	if claims.Exp == 0 || now.Unix() > claims.Exp {
+		return accessClaims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func decodeJWTSegment(segment string, out any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func hmacSHA256(secret []byte, input string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id のパラメータ（OWASP 推奨値: メモリ 64MiB、反復 3 回）
+const (
+	argonTime    uint32 = 3
+	argonMemory  uint32 = 64 * 1024
+	argonThreads uint8  = 2
+	argonKeyLen  uint32 = 32
+	argonSaltLen        = 16
+)
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// hashPassword は PHC 形式（$argon2id$v=19$m=...,t=...,p=...$salt$hash）のハッシュを返します。
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argonMemory,
+		argonTime,
+		argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// verifyDummyPassword は存在しないユーザーのログインでも、実際の検証と同じ時間をかけるために使います。
+func verifyDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = hashPassword("dummy-password-for-timing")
+	})
+	_, _ = verifyPassword(dummyHash, password)
+}
+
+// verifyPassword はパスワードを検証し、現在のパラメータで再ハッシュすべきかを併せて返します。
+// 旧形式（salt:sha256）のハッシュも検証でき、その場合は常に再ハッシュを要求します。
+func verifyPassword(stored, password string) (ok bool, needsRehash bool) {
+	if !strings.HasPrefix(stored, "$argon2id$") {
+		return verifyLegacyPassword(stored, password), true
+	}
+	params, salt, key, err := decodeArgonHash(stored)
+	if err != nil {
+		return false, false
+	}
+	computed := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(computed, key) != 1 {
+		return false, false
+	}
+	outdated := params.time != argonTime || params.memory != argonMemory || params.threads != argonThreads || uint32(len(key)) != argonKeyLen
+	return true, outdated
+}
+
+type argonParams struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func decodeArgonHash(stored string) (argonParams, []byte, []byte, error) {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return argonParams{}, nil, nil, errMalformedHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argonParams{}, nil, nil, errMalformedHash
+	}
+	var params argonParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return argonParams{}, nil, nil, errMalformedHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argonParams{}, nil, nil, errMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return argonParams{}, nil, nil, errMalformedHash
+	}
+	return params, salt, key, nil
+}
+
+func verifyLegacyPassword(stored, password string) bool {
+	parts := strings.SplitN(stored, ":", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	salt := parts[0]
+	sum := sha256.Sum256([]byte(salt + ":" + password))
+	expected := fmt.Sprintf("%s:%x", salt, sum[:])
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(expected)) == 1
+}
@@ -1,42 +1,86 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"fmt"
+	"log"
 	"strings"
-	"sync"
+	"time"
 
+	"book_manager/backend/internal/authctx"
 	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
 	"book_manager/backend/internal/repository"
 )
 
-var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserExists         = errors.New("user already exists")
-	ErrUserIDExists       = errors.New("user id already exists")
+const (
+	DefaultIssuer          = "book_manager"
+	DefaultAccessTTL       = 15 * time.Minute
+	DefaultRefreshTTL      = 30 * 24 * time.Hour
+	DefaultVerificationTTL = 24 * time.Hour
 )
 
-type Service struct {
-	users  repository.UserRepository
-	tokens *TokenStore
+// VerificationSender は確認用トークンを含むメールを送信します。
+type VerificationSender func(to, userID, token string, expiresAt time.Time)
+
+type Options struct {
+	Secret          []byte
+	Issuer          string
+	AccessTTL       time.Duration
+	RefreshTTL      time.Duration
+	VerificationTTL time.Duration
 }
 
-type AuthResult struct {
-	AccessToken  string
-	RefreshToken string
-	User         domain.User
+// Service は Firebase を使わないローカル認証バックエンドです。
+// パスワードは argon2id でハッシュ化し、アクセストークンは HS256 の JWT、
+// リフレッシュトークンは使い捨て（ローテーション）でハッシュのみを永続化します。
+type Service struct {
+	users            repository.UserRepository
+	refreshTokens    repository.RefreshTokenRepository
+	verifications    repository.EmailVerificationRepository
+	sendVerification VerificationSender
+	options          Options
+	now              func() time.Time
 }
 
-func NewService(users repository.UserRepository) *Service {
+func NewService(
+	users repository.UserRepository,
+	refreshTokens repository.RefreshTokenRepository,
+	verifications repository.EmailVerificationRepository,
+	sendVerification VerificationSender,
+	options Options,
+) *Service {
+	if options.Issuer == "" {
+		options.Issuer = DefaultIssuer
+	}
+	if options.AccessTTL <= 0 {
+		options.AccessTTL = DefaultAccessTTL
+	}
+	if options.RefreshTTL <= 0 {
+		options.RefreshTTL = DefaultRefreshTTL
+	}
+	if options.VerificationTTL <= 0 {
+		options.VerificationTTL = DefaultVerificationTTL
+	}
 	return &Service{
-		users:  users,
-		tokens: NewTokenStore(),
+		users:            users,
+		refreshTokens:    refreshTokens,
+		verifications:    verifications,
+		sendVerification: sendVerification,
+		options:          options,
+		now:              time.Now,
 	}
 }
 
+func (s *Service) Mode() string {
+	return ModeLocal
+}
+
+// SeedUser は開発用に確認済みのユーザーを作成します（既に存在する場合は何もしません）。
 func (s *Service) SeedUser(id, email, userID, displayName, password string) error {
 	if _, ok := s.users.FindByID(id); ok {
 		return nil
@@ -48,29 +92,29 @@ func (s *Service) SeedUser(id, email, userID, displayName, password string) erro
 	if err != nil {
 		return err
 	}
-	user := domain.User{
-		ID:           id,
-		Email:        email,
-		UserID:       userID,
-		DisplayName:  displayName,
-		PasswordHash: hashed,
-	}
-	if err := s.users.Create(user); err != nil {
-		return err
-	}
-	return nil
+	return s.users.Create(domain.User{
+		ID:            id,
+		Email:         email,
+		UserID:        userID,
+		DisplayName:   displayName,
+		PasswordHash:  hashed,
+		EmailVerified: true,
+	})
 }
 
-func (s *Service) Signup(email, password, userID, displayName string) (AuthResult, error) {
+func (s *Service) SignUp(ctx context.Context, email, password, userID, displayName string) (Session, error) {
 	if _, ok := s.users.FindByUserID(userID); ok {
-		return AuthResult{}, ErrUserIDExists
+		return Session{}, ErrUserIDExists
+	}
+	if _, ok := s.users.FindByEmail(email); ok {
+		return Session{}, ErrEmailExists
 	}
 	hashed, err := hashPassword(password)
 	if err != nil {
-		return AuthResult{}, err
+		return Session{}, err
 	}
 	user := domain.User{
-		ID:           newID(),
+		ID:           idgen.NewUser(),
 		Email:        email,
 		UserID:       userID,
 		DisplayName:  displayName,
@@ -78,121 +122,309 @@ func (s *Service) Signup(email, password, userID, displayName string) (AuthResul
 	}
 	if err := s.users.Create(user); err != nil {
 		if errors.Is(err, repository.ErrUserExists) {
-			return AuthResult{}, ErrUserExists
+			return Session{}, ErrEmailExists
 		}
-		return AuthResult{}, err
+		return Session{}, err
 	}
-
-	accessToken, refreshToken := s.tokens.Issue(user.ID)
-	return AuthResult{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         user,
-	}, nil
+	if err := s.startVerification(user); err != nil {
+		// 確認メールの送信に失敗してもサインアップは続行する（後で再送信できる）
+		log.Printf("WARNING: failed to start email verification for %s: %v", user.ID, err)
+	}
+	return s.issueSession(user, idgen.NewTokenFamily())
 }
 
-func (s *Service) Login(email, password string) (AuthResult, error) {
+func (s *Service) Login(ctx context.Context, email, password string) (Session, error) {
 	user, ok := s.users.FindByEmail(email)
-	if !ok {
-		return AuthResult{}, ErrInvalidCredentials
+	if !ok || user.PasswordHash == "" {
+		// 登録済みのメールアドレスかを応答内容や応答時間から推測されないよう、ダミーのハッシュも検証してから同じエラーを返す
+		verifyDummyPassword(password)
+		return Session{}, ErrInvalidCredentials
 	}
-	if !verifyPassword(user.PasswordHash, password) {
-		return AuthResult{}, ErrInvalidCredentials
+	valid, needsRehash := verifyPassword(user.PasswordHash, password)
+	if !valid {
+		return Session{}, ErrInvalidCredentials
 	}
-	accessToken, refreshToken := s.tokens.Issue(user.ID)
-	return AuthResult{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         user,
-	}, nil
+	if needsRehash {
+		// 旧形式や古いパラメータのハッシュはログイン成功時に置き換える
+		if hashed, err := hashPassword(password); err == nil {
+			user.PasswordHash = hashed
+			if !s.users.Update(user) {
+				log.Printf("WARNING: failed to upgrade password hash for %s", user.ID)
+			}
+		}
+	}
+	return s.issueSession(user, idgen.NewTokenFamily())
 }
 
-func (s *Service) Refresh(refreshToken string) (string, string, error) {
-	accessToken, nextRefreshToken, ok := s.tokens.Rotate(refreshToken)
-	if !ok {
-		return "", "", ErrInvalidCredentials
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (Session, error) {
+	current, user, err := s.activeRefreshToken(refreshToken)
+	if err != nil {
+		return Session{}, err
 	}
-	return accessToken, nextRefreshToken, nil
+	return s.rotate(current, user)
 }
 
-func (s *Service) Logout(refreshToken string) {
-	s.tokens.Revoke(refreshToken)
+// Logout はリフレッシュトークンが属する系列（=ログインセッション）をまとめて失効させます。
+// 既に無効なトークンの場合も成功として扱います。
+func (s *Service) Logout(ctx context.Context, refreshToken string) error {
+	token, ok := s.refreshTokens.FindByHash(hashToken(refreshToken))
+	if !ok {
+		return nil
+	}
+	s.refreshTokens.RevokeFamily(token.FamilyID, s.now())
+	return nil
 }
 
-type TokenStore struct {
-	mu      sync.Mutex
-	refresh map[string]string
+func (s *Service) ResendVerification(ctx context.Context, refreshToken string) error {
+	_, user, err := s.activeRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	return s.startVerification(user)
 }
 
-func NewTokenStore() *TokenStore {
-	return &TokenStore{
-		refresh: make(map[string]string),
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	verification, ok := s.verifications.FindByHash(hashToken(token))
+	if !ok || verification.UsedAt != nil {
+		return ErrInvalidToken
+	}
+	now := s.now()
+	if now.After(verification.ExpiresAt) {
+		return ErrInvalidToken
 	}
+	user, ok := s.users.FindByID(verification.UserID)
+	if !ok || !strings.EqualFold(user.Email, verification.Email) {
+		// 確認メール送信後にメールアドレスが変更された場合は古いリンクを無効とする
+		return ErrInvalidToken
+	}
+	if !s.verifications.MarkUsed(verification.ID, now) {
+		return ErrInvalidToken
+	}
+	user.EmailVerified = true
+	if !s.users.Update(user) {
+		return errors.New("failed to update user")
+	}
+	return nil
 }
 
-func (s *TokenStore) Issue(userID string) (string, string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Service) UpdateEmail(ctx context.Context, refreshToken, email string) (Session, error) {
+	current, user, err := s.activeRefreshToken(refreshToken)
+	if err != nil {
+		return Session{}, err
+	}
+	if existing, ok := s.users.FindByEmail(email); ok && existing.ID != user.ID {
+		return Session{}, ErrEmailExists
+	}
+	if user.Email != email {
+		user.Email = email
+		user.EmailVerified = false
+		if !s.users.Update(user) {
+			return Session{}, errors.New("failed to update user")
+		}
+	}
+	if !user.EmailVerified {
+		if err := s.startVerification(user); err != nil {
+			return Session{}, err
+		}
+	}
+	return s.rotate(current, user)
+}
 
-	accessToken := newToken()
-	refreshToken := newToken()
-	s.refresh[refreshToken] = userID
-	return accessToken, refreshToken
+// SetVerifiedEmail は確認済みの新しいメールアドレスに変更します。
+func (s *Service) SetVerifiedEmail(ctx context.Context, uid, email string) error {
+	user, ok := s.users.FindByID(uid)
+	if !ok {
+		return ErrInvalidToken
+	}
+	if existing, ok := s.users.FindByEmail(email); ok && existing.ID != user.ID {
+		return ErrEmailExists
+	}
+	user.Email = email
+	user.EmailVerified = true
+	if !s.users.Update(user) {
+		return errors.New("failed to update user")
+	}
+	// 旧アドレス宛ての未使用の確認リンクは無効にする
+	s.verifications.DeleteForUser(user.ID)
+	return nil
 }
 
-func (s *TokenStore) Rotate(refreshToken string) (string, string, bool) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// ChangePassword は現在のパスワードを確認してから変更し、ほかのログインをすべて失効させます。
+func (s *Service) ChangePassword(ctx context.Context, refreshToken, currentPassword, newPassword string) (Session, error) {
+	_, user, err := s.activeRefreshToken(refreshToken)
+	if err != nil {
+		return Session{}, err
+	}
+	if user.PasswordHash == "" {
+		return Session{}, ErrInvalidCredentials
+	}
+	if valid, _ := verifyPassword(user.PasswordHash, currentPassword); !valid {
+		return Session{}, ErrInvalidCredentials
+	}
+	hashed, err := hashPassword(newPassword)
+	if err != nil {
+		return Session{}, err
+	}
+	user.PasswordHash = hashed
+	if !s.users.Update(user) {
+		return Session{}, errors.New("failed to update user")
+	}
+	s.refreshTokens.RevokeAllForUser(user.ID, s.now())
+	return s.issueSession(user, idgen.NewTokenFamily())
+}
 
-	userID, ok := s.refresh[refreshToken]
+// VerifyAccessToken は JWT を検証し、現在のユーザー情報（確認状態を含む）を返します。
+func (s *Service) VerifyAccessToken(ctx context.Context, token string) (authctx.AuthInfo, error) {
+	claims, err := parseAccessToken(s.options.Secret, s.options.Issuer, token, s.now())
+	if err != nil {
+		return authctx.AuthInfo{}, err
+	}
+	user, ok := s.users.FindByID(claims.Sub)
 	if !ok {
-		return "", "", false
+		return authctx.AuthInfo{}, ErrInvalidToken
 	}
-	delete(s.refresh, refreshToken)
-	newAccess := newToken()
-	newRefresh := newToken()
-	s.refresh[newRefresh] = userID
-	return newAccess, newRefresh, true
+	return authctx.AuthInfo{
+		UserID:        user.ID,
+		Email:         user.Email,
+		Name:          user.UserID,
+		EmailVerified: user.EmailVerified,
+	}, nil
 }
 
-func (s *TokenStore) Revoke(refreshToken string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	delete(s.refresh, refreshToken)
+func (s *Service) DeleteUser(ctx context.Context, uid string) error {
+	now := s.now()
+	s.refreshTokens.RevokeAllForUser(uid, now)
+	s.verifications.DeleteForUser(uid)
+	if _, ok := s.users.FindByID(uid); ok && !s.users.Delete(uid) {
+		return errors.New("failed to delete user")
+	}
+	return nil
 }
 
-func hashPassword(password string) (string, error) {
-	salt := make([]byte, 16)
-	if _, err := rand.Read(salt); err != nil {
-		return "", err
+// RevokeRefreshTokens はユーザーのすべてのリフレッシュトークンを失効させます。
+func (s *Service) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	s.refreshTokens.RevokeAllForUser(uid, s.now())
+	return nil
+}
+
+// PurgeExpired は有効期限切れのリフレッシュトークンを削除します。
+func (s *Service) PurgeExpired() int {
+	return s.refreshTokens.DeleteExpired(s.now())
+}
+
+func (s *Service) activeRefreshToken(refreshToken string) (domain.RefreshToken, domain.User, error) {
+	if strings.TrimSpace(refreshToken) == "" {
+		return domain.RefreshToken{}, domain.User{}, ErrInvalidCredentials
+	}
+	token, ok := s.refreshTokens.FindByHash(hashToken(refreshToken))
+	if !ok {
+		return domain.RefreshToken{}, domain.User{}, ErrInvalidCredentials
+	}
+	now := s.now()
+	if token.RevokedAt != nil {
+		if token.ReplacedBy != "" {
+			// ローテーション済みトークンの再利用は漏洩の兆候なので系列ごと失効させる
+			revoked := s.refreshTokens.RevokeFamily(token.FamilyID, now)
+			log.Printf("WARNING: refresh token reuse detected for user %s (family %s, revoked %d)", token.UserID, token.FamilyID, revoked)
+		}
+		return domain.RefreshToken{}, domain.User{}, ErrInvalidCredentials
 	}
-	encodedSalt := base64.RawURLEncoding.EncodeToString(salt)
-	sum := sha256.Sum256([]byte(encodedSalt + ":" + password))
-	return fmt.Sprintf("%s:%x", encodedSalt, sum[:]), nil
+	if now.After(token.ExpiresAt) {
+		return domain.RefreshToken{}, domain.User{}, ErrInvalidCredentials
+	}
+	user, ok := s.users.FindByID(token.UserID)
+	if !ok {
+		return domain.RefreshToken{}, domain.User{}, ErrInvalidCredentials
+	}
+	return token, user, nil
 }
 
-func verifyPassword(stored, password string) bool {
-	parts := strings.SplitN(stored, ":", 2)
-	if len(parts) != 2 {
-		return false
+func (s *Service) rotate(current domain.RefreshToken, user domain.User) (Session, error) {
+	nextID := idgen.NewRefreshToken()
+	if !s.refreshTokens.Rotate(current.ID, nextID, s.now()) {
+		// 同じトークンで同時にリフレッシュされた場合は後続を拒否する
+		return Session{}, ErrInvalidCredentials
 	}
-	salt := parts[0]
-	sum := sha256.Sum256([]byte(salt + ":" + password))
-	return stored == fmt.Sprintf("%s:%x", salt, sum[:])
+	return s.issueSessionWithID(user, current.FamilyID, nextID)
 }
 
-func newToken() string {
-	seed := make([]byte, 32)
-	if _, err := rand.Read(seed); err != nil {
-		return "token"
+func (s *Service) issueSession(user domain.User, familyID string) (Session, error) {
+	return s.issueSessionWithID(user, familyID, idgen.NewRefreshToken())
+}
+
+func (s *Service) issueSessionWithID(user domain.User, familyID, tokenID string) (Session, error) {
+	now := s.now()
+	refreshToken, err := newToken()
+	if err != nil {
+		return Session{}, err
+	}
+	if err := s.refreshTokens.Create(domain.RefreshToken{
+		ID:        tokenID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.options.RefreshTTL),
+		CreatedAt: now,
+	}); err != nil {
+		return Session{}, err
+	}
+	accessToken, err := signAccessToken(s.options.Secret, accessClaims{
+		Iss: s.options.Issuer,
+		Sub: user.ID,
+		Sid: familyID,
+		Iat: now.Unix(),
+		Exp: now.Add(s.options.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		UID:           user.ID,
+		Email:         user.Email,
+		DisplayName:   user.DisplayName,
+		EmailVerified: user.EmailVerified,
+	}, nil
+}
+
+func (s *Service) startVerification(user domain.User) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	expiresAt := now.Add(s.options.VerificationTTL)
+	if err := s.verifications.Create(domain.EmailVerification{
+		ID:        idgen.NewEmailVerification(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+	if s.sendVerification != nil {
+		s.sendVerification(user.Email, user.UserID, token, expiresAt)
 	}
-	return base64.RawURLEncoding.EncodeToString(seed)
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func newID() string {
-	seed := make([]byte, 16)
+func newToken() (string, error) {
+	seed := make([]byte, 32)
 	if _, err := rand.Read(seed); err != nil {
-		return "user_demo"
+		return "", err
 	}
-	return base64.RawURLEncoding.EncodeToString(seed)
+	return base64.RawURLEncoding.EncodeToString(seed), nil
 }
+
+var _ Backend = (*Service)(nil)
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LoginEmailMaxFailures は同じメールアドレスで連続して失敗できる回数です。
+	LoginEmailMaxFailures = 5
+	// LoginIPMaxFailures は同じ IP から連続して失敗できる回数です（複数のアカウントへの総当たり対策）。
+	LoginIPMaxFailures = 20
+	// LoginFailureWindow は失敗回数を数える期間で、上限に達した場合はこの期間ログインを拒否します。
+	LoginFailureWindow = 15 * time.Minute
+	throttlePruneSize  = 10000
+)
+
+type failureWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// LoginThrottle はメールアドレスと IP ごとのログイン失敗回数を数え、上限に達したキーのログインを一定時間拒否します。
+// 認証バックエンド（Firebase / ローカル）に関係なくハンドラーで使います。状態はプロセス内のみです。
+type LoginThrottle struct {
+	mu       sync.Mutex
+	failures map[string]*failureWindow
+	now      func() time.Time
+}
+
+func NewLoginThrottle() *LoginThrottle {
+	return &LoginThrottle{
+		failures: make(map[string]*failureWindow),
+		now:      time.Now,
+	}
+}
+
+// Check はメールアドレスか IP の失敗回数が上限に達している場合に ErrTooManyAttempts を返します。
+func (t *LoginThrottle) Check(email, ip string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.exceeded(emailKey(email), LoginEmailMaxFailures, now) || t.exceeded(ipKey(ip), LoginIPMaxFailures, now) {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// Fail はログインの失敗を記録します。
+func (t *LoginThrottle) Fail(email, ip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if len(t.failures) >= throttlePruneSize {
+		for key, window := range t.failures {
+			if !now.Before(window.resetAt) {
+				delete(t.failures, key)
+			}
+		}
+	}
+	for _, key := range []string{emailKey(email), ipKey(ip)} {
+		if key == "" {
+			continue
+		}
+		window, ok := t.failures[key]
+		if !ok || !now.Before(window.resetAt) {
+			window = &failureWindow{resetAt: now.Add(LoginFailureWindow)}
+			t.failures[key] = window
+		}
+		window.count++
+	}
+}
+
+// Succeed はログインに成功したメールアドレスの失敗回数を消します。IP の回数は残します。
+func (t *LoginThrottle) Succeed(email string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, emailKey(email))
+}
+
+func (t *LoginThrottle) exceeded(key string, limit int, now time.Time) bool {
+	window, ok := t.failures[key]
+	return ok && now.Before(window.resetAt) && window.count >= limit
+}
+
+func emailKey(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return ""
+	}
+	return "email:" + email
+}
+
+func ipKey(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	return "ip:" + ip
+}
@@ -7,6 +7,8 @@ type AuthInfo struct {
 	Email  string
 	Name   string
 	EmailVerified bool
+	// APITokenScope は個人用アクセストークンで認証した場合のスコープです（ログインセッションの場合は空）。
+	APITokenScope string
 }
 
 type contextKey string
@@ -0,0 +1,91 @@
+package bookreports
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/repository"
+)
+
+var (
+	ErrReportNotFound = errors.New("book report not found")
+	ErrInvalidStatus  = errors.New("invalid book report status")
+	ErrAlreadyHandled = errors.New("book report already handled")
+)
+
+// 報告の状態です。届いた報告は open で、モデレーターが resolved（修正済み）か rejected（却下）にします。
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+	StatusRejected = "rejected"
+)
+
+type Service struct {
+	repo repository.BookReportRepository
+	now  func() time.Time
+}
+
+func NewService(repo repository.BookReportRepository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Submit はユーザーの修正提案を未対応の報告として保存します。
+func (s *Service) Submit(userID, bookID, suggestion, note string) (domain.BookReport, error) {
+	report := domain.BookReport{
+		ID:         idgen.NewBookReport(),
+		UserID:     userID,
+		BookID:     bookID,
+		Suggestion: strings.TrimSpace(suggestion),
+		Note:       strings.TrimSpace(note),
+		Status:     StatusOpen,
+		CreatedAt:  s.now(),
+	}
+	if err := s.repo.Create(report); err != nil {
+		return domain.BookReport{}, err
+	}
+	return report, nil
+}
+
+// List は報告を新しい順に返します。status が空の場合はすべての状態を返します。
+func (s *Service) List(status string) ([]domain.BookReport, error) {
+	if status != "" && !isValidStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+	return s.repo.List(status), nil
+}
+
+// Handle は未対応の報告を resolved か rejected にし、対応した管理者を記録します。
+func (s *Service) Handle(id, status, handledBy string) (domain.BookReport, error) {
+	if status != StatusResolved && status != StatusRejected {
+		return domain.BookReport{}, ErrInvalidStatus
+	}
+	report, ok := s.repo.FindByID(id)
+	if !ok {
+		return domain.BookReport{}, ErrReportNotFound
+	}
+	if report.Status != StatusOpen {
+		return domain.BookReport{}, ErrAlreadyHandled
+	}
+	now := s.now()
+	report.Status = status
+	report.HandledBy = handledBy
+	report.HandledAt = &now
+	if !s.repo.Update(report) {
+		return domain.BookReport{}, ErrReportNotFound
+	}
+	return report, nil
+}
+
+func isValidStatus(status string) bool {
+	switch status {
+	case StatusOpen, StatusResolved, StatusRejected:
+		return true
+	}
+	return false
+}
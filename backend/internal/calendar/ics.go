@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event はカレンダーに出力する終日の予定です。
+type Event struct {
+	UID         string
+	Date        string // YYYY-MM-DD
+	Summary     string
+	Description string
+}
+
+const (
+	productID     = "-//book_manager//releases//JA"
+	maxLineOctets = 75
+)
+
+// Render は RFC 5545 形式の VCALENDAR を生成します。日付が不正な予定は出力しません。
+func Render(name string, events []Event, now time.Time) string {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+productID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(name))
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, event := range events {
+		start, err := time.Parse("2006-01-02", event.Date)
+		if err != nil {
+			continue
+		}
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.UID))
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, "DTSTART;VALUE=DATE:"+start.Format("20060102"))
+		writeLine(&b, "DTEND;VALUE=DATE:"+start.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// writeLine は 75 オクテットを超える行を折り返し、CRLF で終端します。
+// マルチバイト文字の途中では折り返しません。
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// 継続行は先頭の空白を含めて 75 オクテットに収める
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+)
+
+var ErrTokenNotFound = errors.New("calendar token not found")
+
+const TokenBytes = 24
+
+type Service struct {
+	repo repository.CalendarTokenRepository
+}
+
+func NewService(repo repository.CalendarTokenRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Issue はユーザーのフィード用トークンを発行します。既存のトークンは無効になります。
+// 平文のトークンは保存しないため、呼び出し元に返すこのときだけ取得できます。
+func (s *Service) Issue(userID string) (domain.CalendarToken, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return domain.CalendarToken{}, "", err
+	}
+	item := domain.CalendarToken{
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Upsert(item); err != nil {
+		return domain.CalendarToken{}, "", err
+	}
+	return item, token, nil
+}
+
+func (s *Service) Get(userID string) (domain.CalendarToken, bool) {
+	return s.repo.FindByUserID(userID)
+}
+
+func (s *Service) Revoke(userID string) bool {
+	return s.repo.Delete(userID)
+}
+
+// Resolve はトークンに対応するユーザーIDを返します。
+func (s *Service) Resolve(token string) (string, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrTokenNotFound
+	}
+	item, ok := s.repo.FindByHash(HashToken(token))
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return item.UserID, nil
+}
+
+func generateToken() (string, error) {
+	bytes := make([]byte, TokenBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// HashToken は保存・照合に使うトークンのハッシュを返します。
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
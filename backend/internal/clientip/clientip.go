@@ -0,0 +1,106 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type contextKey string
+
+const ipKey contextKey = "clientIP"
+
+// Resolver はリクエストの送信元 IP を求めます。
+// X-Forwarded-For はクライアントが自由に書けるため、直前の接続元が信頼するプロキシの場合だけ使います。
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// NewResolver は信頼するプロキシの一覧（カンマ区切りの IP か CIDR）から Resolver を作ります。
+// 空の場合は X-Forwarded-For を使わず、接続元のアドレスだけを使います。
+func NewResolver(trustedProxies string) (*Resolver, error) {
+	resolver := &Resolver{}
+	for _, item := range strings.Split(trustedProxies, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %q", item)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			item = fmt.Sprintf("%s/%d", ip.String(), bits)
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %q", item)
+		}
+		resolver.trusted = append(resolver.trusted, network)
+	}
+	return resolver, nil
+}
+
+// Resolve は送信元 IP を返します。接続元が信頼するプロキシの場合は X-Forwarded-For を右から読み、
+// 信頼するプロキシ以外で最初に現れたアドレスを送信元とします。
+func (r *Resolver) Resolve(req *http.Request) string {
+	ip := remoteIP(req)
+	if r == nil || !r.isTrusted(ip) {
+		return ip
+	}
+	hops := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			// 読めない値はクライアントが書いたものなので、それより左は使わない
+			return ip
+		}
+		ip = hop
+		if !r.isTrusted(hop) {
+			return hop
+		}
+	}
+	return ip
+}
+
+// Middleware は送信元 IP を求めてリクエストのコンテキストに保存します。
+func (r *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), ipKey, r.Resolve(req))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// FromRequest は Middleware が保存した送信元 IP を返します。Middleware を通っていない場合は接続元のアドレスを返します。
+func FromRequest(req *http.Request) string {
+	if ip, ok := req.Context().Value(ipKey).(string); ok {
+		return ip
+	}
+	return remoteIP(req)
+}
+
+func (r *Resolver) isTrusted(value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
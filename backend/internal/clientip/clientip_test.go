@@ -0,0 +1,67 @@
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	resolver, err := NewResolver("10.0.0.0/8, 192.168.1.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		resolver   *Resolver
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{name: "no proxies configured ignores header", resolver: &Resolver{}, remoteAddr: "203.0.113.5:4000", forwarded: "198.51.100.1", want: "203.0.113.5"},
+		{name: "untrusted peer ignores header", resolver: resolver, remoteAddr: "203.0.113.5:4000", forwarded: "198.51.100.1", want: "203.0.113.5"},
+		{name: "trusted peer uses header", resolver: resolver, remoteAddr: "10.1.2.3:4000", forwarded: "198.51.100.1", want: "198.51.100.1"},
+		{name: "spoofed left entries are skipped", resolver: resolver, remoteAddr: "10.1.2.3:4000", forwarded: "1.1.1.1, 198.51.100.1, 192.168.1.10", want: "198.51.100.1"},
+		{name: "garbage stops the walk", resolver: resolver, remoteAddr: "10.1.2.3:4000", forwarded: "198.51.100.1, not-an-ip", want: "10.1.2.3"},
+		{name: "trusted peer without header", resolver: resolver, remoteAddr: "10.1.2.3:4000", want: "10.1.2.3"},
+		{name: "all hops trusted", resolver: resolver, remoteAddr: "10.1.2.3:4000", forwarded: "10.9.9.9", want: "10.9.9.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := tt.resolver.Resolve(req); got != tt.want {
+				t.Errorf("Resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResolverRejectsInvalidEntries(t *testing.T) {
+	for _, value := range []string{"not-an-ip", "10.0.0.0/99"} {
+		if _, err := NewResolver(value); err == nil {
+			t.Errorf("NewResolver(%q) succeeded", value)
+		}
+	}
+}
+
+func TestMiddlewareStoresResolvedIP(t *testing.T) {
+	resolver, err := NewResolver("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	handler := resolver.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromRequest(r)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.5:4000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "203.0.113.5" {
+		t.Errorf("FromRequest = %q, want 203.0.113.5", got)
+	}
+}
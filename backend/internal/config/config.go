@@ -6,61 +6,106 @@ import (
 )
 
 type Config struct {
-	Port                string
-	Env                 string
-	GoogleBooksAPIKey   string
-	GoogleBooksBaseURL  string
-	BookReportTo        string
-	IsbnCacheTTLMinutes int
-	DatabaseURL         string
-	AutoMigrate         bool
-	AuditLogEnabled     bool
-	SMTPHost            string
-	SMTPPort            string
-	SMTPUser            string
-	SMTPPass            string
-	SMTPFrom            string
-	CORSAllowedOrigins  string
-	OpenAIAPIKey        string
-	OpenAIDefaultModel  string
-	AdminUserIDs        string
-	FirebaseProjectID   string
-	FirebaseAPIKey      string
-	FirebaseClientEmail string
-	FirebasePrivateKey  string
-	FrontendURL         string
-	TemplatesDir        string
+	Port                  string
+	Env                   string
+	EnvExplicit           bool
+	GoogleBooksAPIKey     string
+	GoogleBooksBaseURL    string
+	BookReportTo          string
+	IsbnCacheTTLMinutes   int
+	DatabaseURL           string
+	SecretsMasterKeys     string
+	AutoMigrate           bool
+	AuditLogEnabled       bool
+	SMTPHost              string
+	SMTPPort              string
+	SMTPUser              string
+	SMTPPass              string
+	SMTPFrom              string
+	CORSAllowedOrigins    string
+	TrustedProxies        string
+	OpenAIBaseURL         string
+	OpenAIAPIKey          string
+	OpenAIDefaultModel    string
+	OpenAIUserDailyTokens int
+	OpenAIKeyStrategy     string
+	OpenAIKeyCooldownSec  int
+	OpenAIBreakerFailures int
+	OpenAIBreakerOpenSec  int
+	AdminUserIDs          string
+	AuthMode              string
+	AuthJWTSecret         string
+	AuthAccessTTLMinutes  int
+	AuthRefreshTTLDays    int
+	FirebaseProjectID     string
+	FirebaseAPIKey        string
+	FirebaseClientEmail   string
+	FirebasePrivateKey    string
+	FirebaseEmulatorHost  string
+	FirebaseEmulatorOptIn bool
+	FirebaseKeysFile      string
+	FrontendURL           string
+	TemplatesDir          string
+	ReleaseRefreshHours   int
 }
 
 func Load() Config {
 	return Config{
-		Port:                getEnv("PORT", "8080"),
-		Env:                 getEnv("APP_ENV", "local"),
-		GoogleBooksAPIKey:   getEnv("GOOGLE_BOOKS_API_KEY", ""),
-		GoogleBooksBaseURL:  getEnv("GOOGLE_BOOKS_BASE_URL", "https://www.googleapis.com/books/v1/volumes"),
-		BookReportTo:        getEnv("BOOK_REPORT_TO", "product@rikut0904.site"),
-		IsbnCacheTTLMinutes: getEnvInt("ISBN_CACHE_TTL_MINUTES", 1440),
-		DatabaseURL:         getEnv("DATABASE_URL", ""),
-		AutoMigrate:         getEnvBool("AUTO_MIGRATE", true),
-		AuditLogEnabled:     getEnvBool("AUDIT_LOG_ENABLED", true),
-		SMTPHost:            getEnv("SMTP_HOST", ""),
-		SMTPPort:            getEnv("SMTP_PORT", "587"),
-		SMTPUser:            getEnv("SMTP_USER", ""),
-		SMTPPass:            getEnv("SMTP_PASS", ""),
-		SMTPFrom:            getEnv("SMTP_FROM", ""),
-		CORSAllowedOrigins:  getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
-		OpenAIAPIKey:        getEnv("OPENAI_API_KEY", ""),
-		OpenAIDefaultModel:  getEnv("OPENAI_DEFAULT_MODEL", "gpt-4o-mini"),
-		AdminUserIDs:        getEnv("ADMIN_USER_IDS", ""),
-		FirebaseProjectID:   getEnv("FIREBASE_PROJECT_ID", ""),
-		FirebaseAPIKey:      getEnv("FIREBASE_API_KEY", ""),
-		FirebaseClientEmail: getEnv("FIREBASE_CLIENT_EMAIL", ""),
-		FirebasePrivateKey:  getEnv("FIREBASE_PRIVATE_KEY", ""),
-		FrontendURL:         getEnv("FRONTEND_URL", "http://localhost:3000"),
-		TemplatesDir:        getEnv("TEMPLATES_DIR", "templates"),
+		Port:                  getEnv("PORT", "8080"),
+		Env:                   getEnv("APP_ENV", "local"),
+		EnvExplicit:           isEnvSet("APP_ENV"),
+		GoogleBooksAPIKey:     getEnv("GOOGLE_BOOKS_API_KEY", ""),
+		GoogleBooksBaseURL:    getEnv("GOOGLE_BOOKS_BASE_URL", "https://www.googleapis.com/books/v1/volumes"),
+		BookReportTo:          getEnv("BOOK_REPORT_TO", "product@rikut0904.site"),
+		IsbnCacheTTLMinutes:   getEnvInt("ISBN_CACHE_TTL_MINUTES", 1440),
+		DatabaseURL:           getEnv("DATABASE_URL", ""),
+		SecretsMasterKeys:     getEnv("SECRETS_MASTER_KEYS", ""),
+		AutoMigrate:           getEnvBool("AUTO_MIGRATE", true),
+		AuditLogEnabled:       getEnvBool("AUDIT_LOG_ENABLED", true),
+		SMTPHost:              getEnv("SMTP_HOST", ""),
+		SMTPPort:              getEnv("SMTP_PORT", "587"),
+		SMTPUser:              getEnv("SMTP_USER", ""),
+		SMTPPass:              getEnv("SMTP_PASS", ""),
+		SMTPFrom:              getEnv("SMTP_FROM", ""),
+		CORSAllowedOrigins:    getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
+		TrustedProxies:        getEnv("TRUSTED_PROXIES", ""),
+		OpenAIBaseURL:         getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		OpenAIAPIKey:          getEnv("OPENAI_API_KEY", ""),
+		OpenAIDefaultModel:    getEnv("OPENAI_DEFAULT_MODEL", "gpt-4o-mini"),
+		OpenAIUserDailyTokens: getEnvInt("OPENAI_USER_DAILY_TOKENS", 0),
+		OpenAIKeyStrategy:     getEnv("OPENAI_KEY_STRATEGY", "round-robin"),
+		OpenAIKeyCooldownSec:  getEnvInt("OPENAI_KEY_COOLDOWN_SECONDS", 300),
+		OpenAIBreakerFailures: getEnvInt("OPENAI_CIRCUIT_THRESHOLD", 5),
+		OpenAIBreakerOpenSec:  getEnvInt("OPENAI_CIRCUIT_OPEN_SECONDS", 30),
+		AdminUserIDs:          getEnv("ADMIN_USER_IDS", ""),
+		AuthMode:              getEnv("AUTH_MODE", "firebase"),
+		AuthJWTSecret:         getEnv("AUTH_JWT_SECRET", ""),
+		AuthAccessTTLMinutes:  getEnvInt("AUTH_ACCESS_TTL_MINUTES", 15),
+		AuthRefreshTTLDays:    getEnvInt("AUTH_REFRESH_TTL_DAYS", 30),
+		FirebaseProjectID:     getEnv("FIREBASE_PROJECT_ID", ""),
+		FirebaseAPIKey:        getEnv("FIREBASE_API_KEY", ""),
+		FirebaseClientEmail:   getEnv("FIREBASE_CLIENT_EMAIL", ""),
+		FirebasePrivateKey:    getEnv("FIREBASE_PRIVATE_KEY", ""),
+		FirebaseEmulatorHost:  getEnv("FIREBASE_AUTH_EMULATOR_HOST", ""),
+		FirebaseEmulatorOptIn: getEnvBool("FIREBASE_AUTH_EMULATOR_ALLOWED", false),
+		FirebaseKeysFile:      getEnv("FIREBASE_AUTH_KEYS_FILE", ""),
+		FrontendURL:           getEnv("FRONTEND_URL", "http://localhost:3000"),
+		TemplatesDir:          getEnv("TEMPLATES_DIR", "templates"),
+		ReleaseRefreshHours:   getEnvInt("RELEASE_REFRESH_HOURS", 24),
 	}
 }
 
+// IsDevEnv は APP_ENV が local / test に明示的に設定されているかを返します。
+// 既定値の local では true にならないため、危険な開発用の挙動はこれで判定します。
+func (c Config) IsDevEnv() bool {
+	return c.EnvExplicit && (c.Env == "local" || c.Env == "test")
+}
+
+func isEnvSet(key string) bool {
+	_, ok := os.LookupEnv(key)
+	return ok
+}
+
 func getEnvInt(key string, fallback int) int {
 	if value, ok := os.LookupEnv(key); ok {
 		if parsed, err := strconv.Atoi(value); err == nil {
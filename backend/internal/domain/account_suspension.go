@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// AccountSuspension は管理者によるアカウントの利用停止です。停止中のユーザーの API リクエストは認証ミドルウェアで拒否します。
+type AccountSuspension struct {
+	UserID      string
+	Reason      string
+	SuspendedBy string
+	SuspendedAt time.Time
+}
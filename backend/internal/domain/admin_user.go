@@ -5,6 +5,7 @@ import "time"
 type AdminUser struct {
 	ID        string
 	UserID    string
+	Role      string // admin / moderator / support
 	CreatedBy string
 	CreatedAt time.Time
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	APITokenScopeRead      = "read"
+	APITokenScopeReadWrite = "read-write"
+)
+
+// APIToken はスクリプトや外部連携向けにユーザーが発行する個人用アクセストークンです。
+// トークン本体は保存せず、SHA-256 ハッシュと表示用の先頭数文字のみを保持します。
+type APIToken struct {
+	ID         string
+	UserID     string
+	Name       string
+	Scope      string
+	TokenHash  string
+	Prefix     string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// BookReport はユーザーから届いた書誌情報の修正提案です。モデレーターが確認して対応済み・却下にします。
+type BookReport struct {
+	ID         string
+	UserID     string
+	BookID     string
+	Suggestion string
+	Note       string
+	Status     string
+	HandledBy  string
+	HandledAt  *time.Time
+	CreatedAt  time.Time
+}
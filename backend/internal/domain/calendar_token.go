@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// CalendarToken は iCalendar フィードを認証なしで購読するためのユーザーごとのトークンです。
+// トークンの平文は保存せず、SHA-256 のハッシュだけを持ちます。
+type CalendarToken struct {
+	UserID    string
+	TokenHash string
+	CreatedAt time.Time
+}
@@ -0,0 +1,4 @@
+package domain
+
+// DefaultCurrency は金額に通貨が指定されていない場合に用いる通貨コードです。
+const DefaultCurrency = "JPY"
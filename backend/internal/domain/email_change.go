@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// EmailChange は確認待ちのメールアドレス変更です。
+// 新しいアドレスに送った確認リンクが開かれるまで、ユーザーのメールアドレスは変更しません。
+type EmailChange struct {
+	ID          string
+	UserID      string
+	OldEmail    string
+	NewEmail    string
+	TokenHash   string
+	ExpiresAt   time.Time
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+}
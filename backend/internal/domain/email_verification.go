@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// EmailVerification はローカル認証モードのメールアドレス確認トークンです。
+type EmailVerification struct {
+	ID        string
+	UserID    string
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -1,10 +1,16 @@
 package domain
 
 type NextToBuyManual struct {
-	ID           string `json:"id"`
-	UserID       string `json:"userId"`
-	Title        string `json:"title"`
-	SeriesName   string `json:"seriesName"`
-	VolumeNumber int    `json:"volumeNumber"`
-	Note         string `json:"note"`
+	ID            string `json:"id"`
+	UserID        string `json:"userId"`
+	Title         string `json:"title"`
+	SeriesName    string `json:"seriesName"`
+	VolumeNumber  int    `json:"volumeNumber"`
+	Note          string `json:"note"`
+	TargetDate    string `json:"targetDate"`
+	Priority      int    `json:"priority"`
+	ExpectedPrice int    `json:"expectedPrice"`
+	Currency      string `json:"currency"`
+	Store         string `json:"store"`
+	StoreURL      string `json:"storeUrl"`
 }
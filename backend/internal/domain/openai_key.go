@@ -3,8 +3,9 @@ package domain
 import "time"
 
 type OpenAIKey struct {
-	ID        string
-	Name      string
-	APIKey    string
-	CreatedAt time.Time
+	ID                    string
+	Name                  string
+	APIKey                string
+	MonthlyBudgetMicroUSD int64
+	CreatedAt             time.Time
 }
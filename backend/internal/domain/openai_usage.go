@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// OpenAIUsage は 1 回の Chat Completions 呼び出しのトークン使用量と推定コストです。
+// KeyID は共有キーの ID（環境変数のキーは "env"）です。
+type OpenAIUsage struct {
+	ID               string
+	KeyID            string
+	UserID           string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostMicroUSD     int64
+	CreatedAt        time.Time
+}
@@ -1,9 +1,11 @@
 package domain
 
 type ProfileSettings struct {
-	UserID        string
-	Visibility    string
-	OpenAIEnabled bool
-	OpenAIModel   string
-	OpenAIAPIKey  string
+	UserID         string
+	Visibility     string
+	OpenAIEnabled  bool
+	OpenAIModel    string
+	OpenAIAPIKey   string
+	MonthlyBudget  int
+	BudgetCurrency string
 }
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type Prompt struct {
+	ID        string    `json:"id"`
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	Note      string    `json:"note"`
+	Active    bool      `json:"active"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// RefreshToken はローカル認証モードで発行したリフレッシュトークンです。
+// トークン本体は保存せず、SHA-256 ハッシュのみを保持します。
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	FamilyID   string
+	TokenHash  string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy string
+	CreatedAt  time.Time
+}
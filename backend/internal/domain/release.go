@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Release はお気に入りシリーズについて見つかった発売予定の巻です。
+type Release struct {
+	ID           string    `json:"id"`
+	SeriesID     string    `json:"seriesId"`
+	ISBN13       string    `json:"isbn13"`
+	Title        string    `json:"title"`
+	Authors      []string  `json:"authors"`
+	Publisher    string    `json:"publisher"`
+	VolumeLabel  string    `json:"volumeLabel"`
+	VolumeNumber int       `json:"volumeNumber"`
+	ReleaseDate  string    `json:"releaseDate"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	Source       string    `json:"source"`
+	DiscoveredAt time.Time `json:"discoveredAt"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// SeriesGuessCache は ISBN ごとの LLM によるシリーズ推定結果です。
+// 推定に使ったモデルとプロンプトのハッシュが一致する場合のみ再利用します。
+type SeriesGuessCache struct {
+	ISBN13       string
+	IsSeries     bool
+	SeriesName   string
+	VolumeNumber int
+	Confidence   int
+	Model        string
+	PromptHash   string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Session はリフレッシュトークン単位のログインセッション（端末）です。
+// トークン本体は保存せず、最新のリフレッシュトークンの SHA-256 ハッシュのみを保持します。
+type Session struct {
+	ID         string
+	UserID     string
+	DeviceName string
+	UserAgent  string
+	IP         string
+	TokenHash  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  *time.Time
+}
@@ -1,12 +1,16 @@
 package domain
 
 type UserBook struct {
-	ID           string `json:"id"`
-	UserID       string `json:"userId"`
-	BookID       string `json:"bookId"`
-	Note         string `json:"note"`
-	AcquiredAt   string `json:"acquiredAt"`
-	SeriesID     string `json:"seriesId"`
-	VolumeNumber int    `json:"volumeNumber"`
-	SeriesSource string `json:"seriesSource"`
+	ID            string `json:"id"`
+	UserID        string `json:"userId"`
+	BookID        string `json:"bookId"`
+	Note          string `json:"note"`
+	AcquiredAt    string `json:"acquiredAt"`
+	SeriesID      string `json:"seriesId"`
+	VolumeNumber  int    `json:"volumeNumber"`
+	VolumeLabel   string `json:"volumeLabel"`
+	SeriesSource  string `json:"seriesSource"`
+	PurchasePrice int    `json:"purchasePrice"`
+	Currency      string `json:"currency"`
+	Store         string `json:"store"`
 }
@@ -0,0 +1,20 @@
+package domain
+
+// VolumeKind は巻数表記の種類です。
+type VolumeKind string
+
+const (
+	VolumeKindNone    VolumeKind = ""
+	VolumeKindNumber  VolumeKind = "number"  // 1, 10.5, 0 など
+	VolumeKindRange   VolumeKind = "range"   // 1-3 などの合本
+	VolumeKindPart    VolumeKind = "part"    // 上/中/下, 前編/後編
+	VolumeKindSpecial VolumeKind = "special" // 特装版など巻数を持たないもの
+)
+
+// Volume は巻数の表示ラベルと並び替え用の数値キーを保持します。
+type Volume struct {
+	Label   string     `json:"label"`
+	Kind    VolumeKind `json:"kind"`
+	SortKey float64    `json:"sortKey"`
+	End     float64    `json:"end"`
+}
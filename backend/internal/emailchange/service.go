@@ -0,0 +1,129 @@
+package emailchange
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/repository"
+)
+
+var (
+	ErrSameEmail    = errors.New("email is unchanged")
+	ErrEmailExists  = errors.New("email already exists")
+	ErrInvalidToken = errors.New("invalid email change token")
+	ErrNotFound     = errors.New("email change not found")
+)
+
+// TokenTTL は新しいアドレスに送る確認リンクの有効期間です。
+const TokenTTL = 24 * time.Hour
+
+// Service はメールアドレス変更の確認フローを扱います。
+// 変更は新しいアドレスで確認リンクが開かれるまで保留し、ユーザーごとに最新の 1 件だけを有効にします。
+type Service struct {
+	repo  repository.EmailChangeRepository
+	users repository.UserRepository
+	now   func() time.Time
+}
+
+func NewService(repo repository.EmailChangeRepository, users repository.UserRepository) *Service {
+	return &Service{
+		repo:  repo,
+		users: users,
+		now:   time.Now,
+	}
+}
+
+// Request は確認待ちの変更を作成し、記録と平文の確認トークンを返します。
+// 以前の確認待ちの変更は取り消します。
+func (s *Service) Request(userID, oldEmail, newEmail string) (domain.EmailChange, string, error) {
+	if strings.EqualFold(oldEmail, newEmail) {
+		return domain.EmailChange{}, "", ErrSameEmail
+	}
+	if existing, ok := s.users.FindByEmail(newEmail); ok && existing.ID != userID {
+		return domain.EmailChange{}, "", ErrEmailExists
+	}
+	raw, err := newToken()
+	if err != nil {
+		return domain.EmailChange{}, "", err
+	}
+	s.repo.DeleteForUser(userID)
+	now := s.now()
+	change := domain.EmailChange{
+		ID:        idgen.NewEmailChange(),
+		UserID:    userID,
+		OldEmail:  oldEmail,
+		NewEmail:  newEmail,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(TokenTTL),
+		CreatedAt: now,
+	}
+	if err := s.repo.Create(change); err != nil {
+		return domain.EmailChange{}, "", err
+	}
+	return change, raw, nil
+}
+
+// Pending はユーザーの確認待ち（未確認・期限内）の変更を返します。
+func (s *Service) Pending(userID string) (domain.EmailChange, bool) {
+	now := s.now()
+	for _, change := range s.repo.ListByUser(userID) {
+		if change.ConfirmedAt == nil && now.Before(change.ExpiresAt) {
+			return change, true
+		}
+	}
+	return domain.EmailChange{}, false
+}
+
+// Cancel はユーザーの確認待ちの変更を取り消します。
+func (s *Service) Cancel(userID string) error {
+	if _, ok := s.Pending(userID); !ok {
+		return ErrNotFound
+	}
+	s.repo.DeleteForUser(userID)
+	return nil
+}
+
+// Confirm は確認トークンを検証し、apply で認証バックエンドとユーザーのメールアドレスを更新してから変更を確定します。
+// apply が失敗した場合、トークンは未使用のまま残ります。
+func (s *Service) Confirm(raw string, apply func(change domain.EmailChange) error) (domain.EmailChange, error) {
+	change, ok := s.repo.FindByHash(hashToken(raw))
+	if !ok || change.ConfirmedAt != nil {
+		return domain.EmailChange{}, ErrInvalidToken
+	}
+	now := s.now()
+	if !now.Before(change.ExpiresAt) {
+		return domain.EmailChange{}, ErrInvalidToken
+	}
+	// 確認待ちの間にほかのユーザーが同じアドレスを使い始めた場合は確定しない
+	if existing, ok := s.users.FindByEmail(change.NewEmail); ok && existing.ID != change.UserID {
+		return domain.EmailChange{}, ErrEmailExists
+	}
+	if err := apply(change); err != nil {
+		return domain.EmailChange{}, err
+	}
+	change.ConfirmedAt = &now
+	if !s.repo.Update(change) {
+		return domain.EmailChange{}, errors.New("failed to confirm email change")
+	}
+	return change, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newToken() (string, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(seed), nil
+}
@@ -52,6 +52,22 @@ func NewAdminClient(ctx context.Context, creds AdminCredentials) (*AdminClient,
 	return &AdminClient{authClient: authClient}, nil
 }
 
+// NewEmulatorAdminClient は Firebase Auth エミュレーターに接続する Admin SDK クライアントを初期化します
+// Admin SDK は FIREBASE_AUTH_EMULATOR_HOST を参照するため、サービスアカウントの認証情報は不要です
+func NewEmulatorAdminClient(ctx context.Context, projectID string) (*AdminClient, error) {
+	app, err := firebase.NewApp(ctx, &firebase.Config{ProjectID: projectID})
+	if err != nil {
+		return nil, err
+	}
+
+	authClient, err := app.Auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdminClient{authClient: authClient}, nil
+}
+
 // RevokeRefreshTokens は指定したユーザーのすべてのリフレッシュトークンを失効させます
 func (c *AdminClient) RevokeRefreshTokens(ctx context.Context, uid string) error {
 	return c.authClient.RevokeRefreshTokens(ctx, uid)
@@ -67,6 +83,18 @@ func (c *AdminClient) DeleteUser(ctx context.Context, uid string) error {
 	return c.authClient.DeleteUser(ctx, uid)
 }
 
+// SetVerifiedEmail はユーザーのメールアドレスを確認済みとして変更します
+func (c *AdminClient) SetVerifiedEmail(ctx context.Context, uid, email string) error {
+	params := (&auth.UserToUpdate{}).Email(email).EmailVerified(true)
+	if _, err := c.authClient.UpdateUser(ctx, uid, params); err != nil {
+		if auth.IsEmailAlreadyExists(err) {
+			return ErrEmailExists
+		}
+		return err
+	}
+	return nil
+}
+
 // normalizePrivateKey は秘密鍵を正規化します
 // 以下の形式をサポート:
 // 1. Base64エンコードされた秘密鍵（推奨）
@@ -21,9 +21,20 @@ var (
 	ErrTooManyAttempts     = errors.New("too many attempts")
 )
 
+const (
+	identityToolkitURL = "https://identitytoolkit.googleapis.com/v1/"
+	secureTokenURL     = "https://securetoken.googleapis.com/v1/token"
+
+	// EmulatorHostEnv は Firebase Auth エミュレーターのホスト（例: localhost:9099）を指定する環境変数です。
+	// Admin SDK も同じ環境変数を参照します。
+	EmulatorHostEnv = "FIREBASE_AUTH_EMULATOR_HOST"
+)
+
 type Client struct {
-	apiKey string
-	client *http.Client
+	apiKey             string
+	client             *http.Client
+	identityToolkitURL string
+	secureTokenURL     string
 }
 
 type AuthResult struct {
@@ -36,8 +47,21 @@ type AuthResult struct {
 
 func NewClient(apiKey string) *Client {
 	return &Client{
-		apiKey: strings.TrimSpace(apiKey),
-		client: &http.Client{Timeout: 10 * time.Second},
+		apiKey:             strings.TrimSpace(apiKey),
+		client:             &http.Client{Timeout: 10 * time.Second},
+		identityToolkitURL: identityToolkitURL,
+		secureTokenURL:     secureTokenURL,
+	}
+}
+
+// UseEmulator は REST API の呼び出し先を Firebase Auth エミュレーターに切り替えます。
+// エミュレーターは API キーを検証しないため、未設定の場合は仮の値を使います。
+func (c *Client) UseEmulator(host string) {
+	base := "http://" + strings.TrimSuffix(strings.TrimSpace(host), "/")
+	c.identityToolkitURL = base + "/identitytoolkit.googleapis.com/v1/"
+	c.secureTokenURL = base + "/securetoken.googleapis.com/v1/token"
+	if c.apiKey == "" {
+		c.apiKey = "fake-api-key"
 	}
 }
 
@@ -107,11 +131,32 @@ func (c *Client) UpdateEmail(idToken, email string) (AuthResult, error) {
 		DisplayName:  resp.DisplayName,
 	}, nil
 }
+
+// UpdatePassword はパスワードを変更し、新しいトークンを返します（既存のリフレッシュトークンは無効になります）
+func (c *Client) UpdatePassword(idToken, password string) (AuthResult, error) {
+	payload := map[string]any{
+		"idToken":           idToken,
+		"password":          password,
+		"returnSecureToken": true,
+	}
+	var resp signInResponse
+	if err := c.postJSON("accounts:update", payload, &resp); err != nil {
+		return AuthResult{}, err
+	}
+	return AuthResult{
+		IDToken:      resp.IDToken,
+		RefreshToken: resp.RefreshToken,
+		LocalID:      resp.LocalID,
+		Email:        resp.Email,
+		DisplayName:  resp.DisplayName,
+	}, nil
+}
+
 func (c *Client) Refresh(refreshToken string) (AuthResult, error) {
 	form := url.Values{}
 	form.Set("grant_type", "refresh_token")
 	form.Set("refresh_token", refreshToken)
-	endpoint := "https://securetoken.googleapis.com/v1/token?key=" + url.QueryEscape(c.apiKey)
+	endpoint := c.secureTokenURL + "?key=" + url.QueryEscape(c.apiKey)
 	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return AuthResult{}, err
@@ -140,7 +185,7 @@ func (c *Client) postJSON(path string, payload any, out any) error {
 	if c.apiKey == "" {
 		return errors.New("firebase api key is empty")
 	}
-	endpoint := "https://identitytoolkit.googleapis.com/v1/" + path + "?key=" + url.QueryEscape(c.apiKey)
+	endpoint := c.identityToolkitURL + path + "?key=" + url.QueryEscape(c.apiKey)
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
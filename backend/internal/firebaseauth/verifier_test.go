@@ -0,0 +1,213 @@
+package firebaseauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const testProjectID = "book-manager-test"
+
+func testClaims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"aud":            testProjectID,
+		"iss":            "https://securetoken.google.com/" + testProjectID,
+		"sub":            "uid-1",
+		"user_id":        "uid-1",
+		"email":          "reader@example.com",
+		"name":           "Reader",
+		"email_verified": true,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func encodeSegment(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	signingInput := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func unsignedToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	return encodeSegment(t, map[string]string{"alg": "none", "typ": "JWT"}) + "." + encodeSegment(t, claims) + "."
+}
+
+func jwksFor(t *testing.T, kid string, key *rsa.PublicKey) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": kid,
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newStaticVerifier(t *testing.T, keySet []byte) *Verifier {
+	t.Helper()
+	keys, err := LoadKeySet(keySet)
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	verifier := NewVerifier(testProjectID)
+	verifier.SetStaticKeys(keys)
+	return verifier
+}
+
+func TestVerifyIDTokenWithStaticJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := newStaticVerifier(t, jwksFor(t, "kid-1", &key.PublicKey))
+
+	info, err := verifier.VerifyIDToken(context.Background(), signToken(t, key, "kid-1", testClaims()))
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	want := AuthInfo{UserID: "uid-1", Email: "reader@example.com", Name: "Reader", EmailVerified: true}
+	if info != want {
+		t.Errorf("info = %+v, want %+v", info, want)
+	}
+
+	expired := testClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongAudience := testClaims()
+	wrongAudience["aud"] = "another-project"
+	wrongIssuer := testClaims()
+	wrongIssuer["iss"] = "https://securetoken.google.com/another-project"
+	tampered := signToken(t, key, "kid-1", testClaims())
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{name: "unknown kid", token: signToken(t, key, "kid-2", testClaims()), want: ErrUnauthorized},
+		{name: "signed by another key", token: signToken(t, other, "kid-1", testClaims()), want: ErrUnauthorized},
+		{name: "tampered signature", token: tampered, want: ErrUnauthorized},
+		{name: "expired", token: signToken(t, key, "kid-1", expired), want: ErrUnauthorized},
+		{name: "wrong audience", token: signToken(t, key, "kid-1", wrongAudience), want: ErrUnauthorized},
+		{name: "wrong issuer", token: signToken(t, key, "kid-1", wrongIssuer), want: ErrUnauthorized},
+		{name: "unsigned", token: unsignedToken(t, testClaims()), want: ErrInvalidToken},
+		{name: "malformed", token: "not-a-token", want: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.VerifyIDToken(context.Background(), tt.token); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenWithStaticPEMKeySet(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySet, err := json.Marshal(map[string]string{
+		"kid-pem": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := newStaticVerifier(t, keySet)
+	if _, err := verifier.VerifyIDToken(context.Background(), signToken(t, key, "kid-pem", testClaims())); err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+}
+
+func TestLoadKeySetRejectsEmptySets(t *testing.T) {
+	for _, data := range []string{`{"keys":[{"kty":"EC","kid":"ec-1"}]}`, `{"kid-1":"not a pem"}`, `not json`} {
+		if _, err := LoadKeySet([]byte(data)); err == nil {
+			t.Errorf("LoadKeySet(%s) succeeded", data)
+		}
+	}
+}
+
+func TestVerifyIDTokenEmulator(t *testing.T) {
+	verifier := NewVerifier(testProjectID)
+	token := unsignedToken(t, testClaims())
+
+	// UseEmulator を呼ぶまでは署名なしのトークンを受け付けない
+	if _, err := verifier.VerifyIDToken(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("before UseEmulator: err = %v, want ErrInvalidToken", err)
+	}
+
+	verifier.UseEmulator()
+	info, err := verifier.VerifyIDToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if info.UserID != "uid-1" || info.Email != "reader@example.com" || !info.EmailVerified {
+		t.Errorf("info = %+v", info)
+	}
+
+	// エミュレーターでもクレームの検証は省略しない
+	wrongAudience := testClaims()
+	wrongAudience["aud"] = "another-project"
+	expired := testClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	noSubject := testClaims()
+	delete(noSubject, "sub")
+	tests := []struct {
+		name   string
+		claims map[string]any
+		want   error
+	}{
+		{name: "wrong audience", claims: wrongAudience, want: ErrUnauthorized},
+		{name: "expired", claims: expired, want: ErrUnauthorized},
+		{name: "no subject", claims: noSubject, want: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.VerifyIDToken(context.Background(), unsignedToken(t, tt.claims)); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package follows
 
-import "sync"
+import (
+	"sort"
+	"sync"
+)
 
 type Service struct {
 	mu        sync.RWMutex
@@ -54,3 +57,47 @@ func (s *Service) CountFollowers(userID string) int {
 
 	return len(s.followers[userID])
 }
+
+// Following はユーザーがフォローしているユーザーIDの一覧を返します。
+func (s *Service) Following(userID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedKeys(s.following[userID])
+}
+
+// Followers はユーザーをフォローしているユーザーIDの一覧を返します。
+func (s *Service) Followers(userID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedKeys(s.followers[userID])
+}
+
+// RemoveUser はアカウント削除時にユーザーのフォロー・フォロワー関係をすべて削除し、削除した件数を返します。
+func (s *Service) RemoveUser(userID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for followeeID := range s.following[userID] {
+		delete(s.followers[followeeID], userID)
+		count++
+	}
+	for followerID := range s.followers[userID] {
+		delete(s.following[followerID], userID)
+		count++
+	}
+	delete(s.following, userID)
+	delete(s.followers, userID)
+	return count
+}
+
+func sortedKeys(items map[string]struct{}) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,40 +1,51 @@
 package handler
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"book_manager/backend/internal/accounts"
 	"book_manager/backend/internal/admininvitations"
 	"book_manager/backend/internal/adminusers"
 	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/aiusage"
+	"book_manager/backend/internal/apitokens"
+	"book_manager/backend/internal/auditlogs"
+	"book_manager/backend/internal/auth"
 	"book_manager/backend/internal/authctx"
+	"book_manager/backend/internal/bookreports"
 	"book_manager/backend/internal/books"
+	"book_manager/backend/internal/calendar"
 	"book_manager/backend/internal/config"
 	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/emailchange"
 	"book_manager/backend/internal/favorites"
-	"book_manager/backend/internal/firebaseauth"
 	"book_manager/backend/internal/follows"
 	"book_manager/backend/internal/isbn"
 	"book_manager/backend/internal/nexttobuy"
 	"book_manager/backend/internal/openaikeys"
 	"book_manager/backend/internal/pagination"
+	"book_manager/backend/internal/prompts"
+	"book_manager/backend/internal/readnext"
 	"book_manager/backend/internal/recommendations"
+	"book_manager/backend/internal/releases"
 	"book_manager/backend/internal/reports"
 	"book_manager/backend/internal/series"
+	"book_manager/backend/internal/sessions"
+	"book_manager/backend/internal/spending"
 	"book_manager/backend/internal/userbooks"
 	"book_manager/backend/internal/users"
 	"book_manager/backend/internal/validation"
 )
 
 type Handler struct {
-	firebaseClient     *firebaseauth.Client
-	firebaseVerifier   *firebaseauth.Verifier
-	firebaseAdmin      *firebaseauth.AdminClient
+	authBackend        auth.Backend
 	isbn               *isbn.Service
 	books              *books.Service
 	userBooks          *userbooks.Service
@@ -48,15 +59,29 @@ type Handler struct {
 	openAIKeys         *openaikeys.Service
 	adminInvitations   *admininvitations.Service
 	adminUsers         *adminusers.Service
+	releases           *releases.Service
+	calendar           *calendar.Service
+	spending           *spending.Service
+	seriesGuesses      *ai.GuessCache
+	aiUsage            *aiusage.Service
+	readNext           *readnext.Service
+	aiBreaker          *ai.CircuitBreaker
+	aiMetrics          *ai.Metrics
+	openAIBaseURL      string
 	openAIAPIKey       string
 	openAIDefaultModel string
-	aiPrompt           string
+	prompts            *prompts.Service
+	sessions           *sessions.Service
+	apiTokens          *apitokens.Service
+	accounts           *accounts.Service
+	emailChanges       *emailchange.Service
+	auditLogs          *auditlogs.Service
+	bookReports        *bookreports.Service
+	loginThrottle      *auth.LoginThrottle
 }
 
 func New(
-	firebaseClient *firebaseauth.Client,
-	firebaseVerifier *firebaseauth.Verifier,
-	firebaseAdmin *firebaseauth.AdminClient,
+	authBackend auth.Backend,
 	isbnService *isbn.Service,
 	bookService *books.Service,
 	userBookService *userbooks.Service,
@@ -70,14 +95,27 @@ func New(
 	openAIKeyService *openaikeys.Service,
 	adminInvitationsService *admininvitations.Service,
 	adminUsersService *adminusers.Service,
+	releasesService *releases.Service,
+	calendarService *calendar.Service,
+	spendingService *spending.Service,
+	seriesGuessCache *ai.GuessCache,
+	aiUsageService *aiusage.Service,
+	readNextService *readnext.Service,
+	aiBreaker *ai.CircuitBreaker,
+	aiMetrics *ai.Metrics,
+	openAIBaseURL string,
 	openAIAPIKey string,
 	openAIDefaultModel string,
-	aiPrompt string,
+	promptsService *prompts.Service,
+	sessionsService *sessions.Service,
+	apiTokensService *apitokens.Service,
+	accountsService *accounts.Service,
+	emailChangeService *emailchange.Service,
+	auditLogsService *auditlogs.Service,
+	bookReportsService *bookreports.Service,
 ) *Handler {
 	return &Handler{
-		firebaseClient:     firebaseClient,
-		firebaseVerifier:   firebaseVerifier,
-		firebaseAdmin:      firebaseAdmin,
+		authBackend:        authBackend,
 		isbn:               isbnService,
 		books:              bookService,
 		userBooks:          userBookService,
@@ -91,9 +129,25 @@ func New(
 		openAIKeys:         openAIKeyService,
 		adminInvitations:   adminInvitationsService,
 		adminUsers:         adminUsersService,
+		releases:           releasesService,
+		calendar:           calendarService,
+		spending:           spendingService,
+		seriesGuesses:      seriesGuessCache,
+		aiUsage:            aiUsageService,
+		readNext:           readNextService,
+		aiBreaker:          aiBreaker,
+		aiMetrics:          aiMetrics,
+		openAIBaseURL:      openAIBaseURL,
 		openAIAPIKey:       openAIAPIKey,
 		openAIDefaultModel: openAIDefaultModel,
-		aiPrompt:           aiPrompt,
+		prompts:            promptsService,
+		sessions:           sessionsService,
+		apiTokens:          apiTokensService,
+		accounts:           accountsService,
+		emailChanges:       emailChangeService,
+		auditLogs:          auditLogsService,
+		bookReports:        bookReportsService,
+		loginThrottle:      auth.NewLoginThrottle(),
 	}
 }
 
@@ -115,8 +169,7 @@ func (h *Handler) IsbnLookup(w http.ResponseWriter, r *http.Request) {
 	}
 	userID := userIDFromRequest(r)
 	settings := domain.ProfileSettings{}
-	sharedKey := domain.OpenAIKey{}
-	hasShared := false
+	var keys []openAIKeyCandidate
 	var settingsWG sync.WaitGroup
 	settingsWG.Add(2)
 	go func() {
@@ -125,7 +178,7 @@ func (h *Handler) IsbnLookup(w http.ResponseWriter, r *http.Request) {
 	}()
 	go func() {
 		defer settingsWG.Done()
-		sharedKey, hasShared = h.openAIKeys.First()
+		keys = h.openAIKeyCandidates()
 	}()
 
 	var book domain.Book
@@ -133,7 +186,11 @@ func (h *Handler) IsbnLookup(w http.ResponseWriter, r *http.Request) {
 	seriesSource := "simple"
 	if existing, ok := h.books.FindByISBN(isbnValue); ok {
 		book = existing
-		seriesGuess = isbn.InferSeries(book.Title, book.SeriesName)
+		rawTitle := book.OriginalTitle
+		if rawTitle == "" {
+			rawTitle = book.Title
+		}
+		seriesGuess = isbn.InferSeries(rawTitle, book.SeriesName)
 	} else {
 		fetched, guess, err := h.isbn.Lookup(isbnValue)
 		if err != nil {
@@ -182,49 +239,29 @@ func (h *Handler) IsbnLookup(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	settingsWG.Wait()
-	apiKey := h.openAIAPIKey
-	if hasShared {
-		apiKey = sharedKey.APIKey
-	}
-	if apiKey != "" {
-		rawTitle := book.OriginalTitle
-		if rawTitle == "" {
-			rawTitle = book.Title
-		}
-		model := h.openAIDefaultModel
-		if settings.OpenAIModel != "" {
-			model = settings.OpenAIModel
-		}
-		client := ai.NewOpenAIClient(apiKey, model, h.aiPrompt)
-		guess, err := client.GuessSeries(r.Context(), ai.SeriesInput{
-			Title:         rawTitle,
-			RawTitle:      rawTitle,
-			Authors:       book.Authors,
-			Publisher:     book.Publisher,
-			PublishedDate: book.PublishedDate,
-			ISBN13:        book.ISBN13,
-			SeriesName:    book.SeriesName,
-		})
-		if err != nil {
-			log.Printf("openai guess error: %v", err)
-		} else if guess.IsSeries && strings.TrimSpace(guess.Name) != "" {
-			seriesGuess = isbn.SeriesGuess{
-				Name:         guess.Name,
-				VolumeNumber: guess.VolumeNumber,
-			}
-			seriesSource = "openai"
-		} else if !guess.IsSeries {
-			seriesGuess = isbn.SeriesGuess{}
-			seriesSource = "openai"
+	classifier := h.userSeriesClassifier(userID, settings, keys)
+	rawTitle := book.OriginalTitle
+	if rawTitle == "" {
+		rawTitle = book.Title
+	}
+	guess, err := classifier.GuessSeries(r.Context(), ai.SeriesInput{
+		Title:         rawTitle,
+		RawTitle:      rawTitle,
+		Authors:       book.Authors,
+		Publisher:     book.Publisher,
+		PublishedDate: book.PublishedDate,
+		ISBN13:        book.ISBN13,
+		SeriesName:    book.SeriesName,
+	})
+	if err != nil {
+		log.Printf("series guess error: %v", err)
+		seriesGuess = mergeSeriesGuess(seriesGuess, nil)
+	} else {
+		seriesGuess = mergeSeriesGuess(seriesGuess, &guess)
+		if !guess.IsSeries || strings.TrimSpace(guess.Name) != "" {
+			seriesSource = guess.Source
 		}
 	}
-	if seriesGuess.Name != "" {
-		normalizedName := isbn.NormalizeSeriesName(seriesGuess.Name)
-		seriesGuess.Name = normalizedName
-	}
-	if seriesGuess.Name == "" {
-		seriesGuess.VolumeNumber = 0
-	}
 	seriesID := ""
 	if seriesGuess.Name != "" {
 		if item, err := h.series.Ensure(seriesGuess.Name); err == nil {
@@ -249,19 +286,19 @@ func (h *Handler) IsbnLookup(w http.ResponseWriter, r *http.Request) {
 			userBookID = created.ID
 		}
 	}
-	if userBookID != "" && (seriesID != "" || seriesGuess.VolumeNumber > 0) {
+	if userBookID != "" && (seriesID != "" || seriesGuess.Volume.Label != "") {
 		input := userbooks.UpdateInput{}
 		if seriesID != "" {
 			seriesIDCopy := seriesID
 			input.SeriesID = &seriesIDCopy
 		}
-		if seriesGuess.VolumeNumber > 0 {
-			volume := seriesGuess.VolumeNumber
-			input.VolumeNumber = &volume
+		if seriesGuess.Volume.Label != "" {
+			label := seriesGuess.Volume.Label
+			input.VolumeLabel = &label
 		}
 		source := "auto"
 		input.SeriesSource = &source
-		if input.SeriesID != nil || input.VolumeNumber != nil {
+		if input.SeriesID != nil || input.VolumeLabel != nil {
 			_, _ = h.userBooks.Update(userBookID, input)
 		}
 	}
@@ -277,6 +314,7 @@ func (h *Handler) IsbnLookup(w http.ResponseWriter, r *http.Request) {
 		"seriesId":      seriesID,
 		"seriesName":    seriesGuess.Name,
 		"volumeNumber":  seriesGuess.VolumeNumber,
+		"volumeLabel":   seriesGuess.Volume.Label,
 		"seriesSource":  seriesSource,
 	})
 }
@@ -318,6 +356,15 @@ func (h *Handler) Books(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "volumeNumber must be positive")
 			return
 		}
+		var volumeLabel *string
+		if req.VolumeLabel != nil && strings.TrimSpace(*req.VolumeLabel) != "" {
+			volume, ok := isbn.ParseVolume(*req.VolumeLabel)
+			if !ok {
+				badRequest(w, "invalid volumeLabel")
+				return
+			}
+			volumeLabel = &volume.Label
+		}
 		seriesName := ""
 		if req.IsSeries {
 			seriesName = isbn.NormalizeSeriesName(req.SeriesName)
@@ -365,11 +412,12 @@ func (h *Handler) Books(w http.ResponseWriter, r *http.Request) {
 				volume := *req.VolumeNumber
 				input.VolumeNumber = &volume
 			}
+			input.VolumeLabel = volumeLabel
 			if seriesID != "" {
 				source := "manual"
 				input.SeriesSource = &source
 			}
-			if input.SeriesID != nil || input.VolumeNumber != nil {
+			if input.SeriesID != nil || input.VolumeNumber != nil || input.VolumeLabel != nil {
 				_, _ = h.userBooks.Update(userBookID, input)
 			}
 		}
@@ -600,19 +648,27 @@ func (h *Handler) SeriesDetail(w http.ResponseWriter, r *http.Request) {
 	}
 	type seriesBookItem struct {
 		domain.UserBook
-		Book domain.Book `json:"book"`
+		Book   domain.Book   `json:"book"`
+		Volume domain.Volume `json:"volume"`
 	}
 	items := make([]seriesBookItem, 0, len(filtered))
+	volumes := make([]domain.Volume, 0, len(filtered))
 	for _, item := range filtered {
 		book, ok := booksByID[item.BookID]
 		if !ok {
 			continue
 		}
+		volume := isbn.UserBookVolume(item)
 		items = append(items, seriesBookItem{
 			UserBook: item,
 			Book:     book,
+			Volume:   volume,
 		})
+		volumes = append(volumes, volume)
 	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return isbn.VolumeLess(items[i].Volume, items[j].Volume)
+	})
 	seriesName := ""
 	for _, item := range series {
 		if item.ID == seriesID {
@@ -624,10 +680,11 @@ func (h *Handler) SeriesDetail(w http.ResponseWriter, r *http.Request) {
 		seriesName = items[0].Book.SeriesName
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"seriesId":   seriesID,
-		"seriesName": seriesName,
-		"items":      items,
-		"favorites":  favorites,
+		"seriesId":       seriesID,
+		"seriesName":     seriesName,
+		"items":          items,
+		"missingVolumes": isbn.MissingVolumes(volumes),
+		"favorites":      favorites,
 	})
 }
 
@@ -746,15 +803,23 @@ func (h *Handler) UserBooks(w http.ResponseWriter, r *http.Request) {
 		})
 	case http.MethodPost:
 		var req struct {
-			UserID     string `json:"userId"`
-			BookID     string `json:"bookId"`
-			Note       string `json:"note"`
-			AcquiredAt string `json:"acquiredAt"`
+			UserID        string  `json:"userId"`
+			BookID        string  `json:"bookId"`
+			Note          string  `json:"note"`
+			AcquiredAt    string  `json:"acquiredAt"`
+			PurchasePrice *int    `json:"purchasePrice"`
+			Currency      *string `json:"currency"`
+			Store         *string `json:"store"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			badRequest(w, "invalid json")
 			return
 		}
+		normalizePurchaseFields(req.Currency, req.Store)
+		if err := validatePurchaseFields(req.PurchasePrice, req.Currency); err != nil {
+			badRequest(w, err.Error())
+			return
+		}
 		if strings.TrimSpace(req.BookID) == "" {
 			badRequest(w, "bookId is required")
 			return
@@ -776,6 +841,15 @@ func (h *Handler) UserBooks(w http.ResponseWriter, r *http.Request) {
 			internalError(w)
 			return
 		}
+		if req.PurchasePrice != nil || req.Currency != nil || req.Store != nil {
+			if updated, ok := h.userBooks.Update(item.ID, userbooks.UpdateInput{
+				PurchasePrice: req.PurchasePrice,
+				Currency:      req.Currency,
+				Store:         req.Store,
+			}); ok {
+				item = updated
+			}
+		}
 		writeJSON(w, http.StatusOK, item)
 	default:
 		methodNotAllowed(w, http.MethodGet, http.MethodPost)
@@ -791,24 +865,35 @@ func (h *Handler) UserBooksByID(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		var req struct {
-			Note       *string `json:"note"`
-			AcquiredAt *string `json:"acquiredAt"`
+			Note          *string `json:"note"`
+			AcquiredAt    *string `json:"acquiredAt"`
+			PurchasePrice *int    `json:"purchasePrice"`
+			Currency      *string `json:"currency"`
+			Store         *string `json:"store"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			badRequest(w, "invalid json")
 			return
 		}
-		if req.Note == nil && req.AcquiredAt == nil {
-			badRequest(w, "note or acquiredAt is required")
+		if req.Note == nil && req.AcquiredAt == nil && req.PurchasePrice == nil && req.Currency == nil && req.Store == nil {
+			badRequest(w, "no fields to update")
 			return
 		}
 		if req.AcquiredAt != nil && *req.AcquiredAt != "" && !isISODate(*req.AcquiredAt) {
 			badRequest(w, "acquiredAt must be YYYY-MM-DD")
 			return
 		}
+		normalizePurchaseFields(req.Currency, req.Store)
+		if err := validatePurchaseFields(req.PurchasePrice, req.Currency); err != nil {
+			badRequest(w, err.Error())
+			return
+		}
 		item, ok := h.userBooks.Update(id, userbooks.UpdateInput{
-			Note:       req.Note,
-			AcquiredAt: req.AcquiredAt,
+			Note:          req.Note,
+			AcquiredAt:    req.AcquiredAt,
+			PurchasePrice: req.PurchasePrice,
+			Currency:      req.Currency,
+			Store:         req.Store,
 		})
 		if !ok {
 			notFound(w)
@@ -837,11 +922,12 @@ func (h *Handler) UserSeriesOverride(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var req struct {
-		BookID       string `json:"bookId"`
-		SeriesID     string `json:"seriesId"`
-		VolumeNumber *int   `json:"volumeNumber"`
-		UserID       string `json:"userId"`
-		IsSeries     *bool  `json:"isSeries"`
+		BookID       string  `json:"bookId"`
+		SeriesID     string  `json:"seriesId"`
+		VolumeNumber *int    `json:"volumeNumber"`
+		VolumeLabel  *string `json:"volumeLabel"`
+		UserID       string  `json:"userId"`
+		IsSeries     *bool   `json:"isSeries"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
 		badRequest(w, "invalid json")
@@ -860,18 +946,30 @@ func (h *Handler) UserSeriesOverride(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "seriesId is required")
 			return
 		}
-		if req.VolumeNumber == nil {
-			badRequest(w, "volumeNumber is required")
-			return
-		}
-		if *req.VolumeNumber <= 0 {
-			badRequest(w, "volumeNumber must be positive")
-			return
+		if req.VolumeLabel != nil && strings.TrimSpace(*req.VolumeLabel) != "" {
+			volume, ok := isbn.ParseVolume(*req.VolumeLabel)
+			if !ok {
+				badRequest(w, "invalid volumeLabel")
+				return
+			}
+			req.VolumeLabel = &volume.Label
+			req.VolumeNumber = nil
+		} else {
+			req.VolumeLabel = nil
+			if req.VolumeNumber == nil {
+				badRequest(w, "volumeNumber is required")
+				return
+			}
+			if *req.VolumeNumber <= 0 {
+				badRequest(w, "volumeNumber must be positive")
+				return
+			}
 		}
 	} else {
 		req.SeriesID = ""
 		zero := 0
 		req.VolumeNumber = &zero
+		req.VolumeLabel = nil
 	}
 	userID := strings.TrimSpace(req.UserID)
 	if userID == "" {
@@ -883,11 +981,11 @@ func (h *Handler) UserSeriesOverride(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 		seriesID := req.SeriesID
-		volume := *req.VolumeNumber
 		source := "manual"
 		updated, ok := h.userBooks.Update(item.ID, userbooks.UpdateInput{
 			SeriesID:     &seriesID,
-			VolumeNumber: &volume,
+			VolumeNumber: req.VolumeNumber,
+			VolumeLabel:  req.VolumeLabel,
 			SeriesSource: &source,
 		})
 		if !ok {
@@ -903,11 +1001,11 @@ func (h *Handler) UserSeriesOverride(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	seriesID := req.SeriesID
-	volume := *req.VolumeNumber
 	source := "manual"
 	updated, ok := h.userBooks.Update(created.ID, userbooks.UpdateInput{
 		SeriesID:     &seriesID,
-		VolumeNumber: &volume,
+		VolumeNumber: req.VolumeNumber,
+		VolumeLabel:  req.VolumeLabel,
 		SeriesSource: &source,
 	})
 	if !ok {
@@ -999,48 +1097,95 @@ func (h *Handler) NextToBuy(w http.ResponseWriter, r *http.Request) {
 	items := make([]map[string]any, 0, len(manualItems))
 	for _, item := range manualItems {
 		items = append(items, map[string]any{
-			"id":           item.ID,
-			"title":        item.Title,
-			"seriesName":   item.SeriesName,
-			"volumeNumber": item.VolumeNumber,
-			"note":         item.Note,
-			"source":       "manual",
+			"id":            item.ID,
+			"title":         item.Title,
+			"seriesName":    item.SeriesName,
+			"volumeNumber":  item.VolumeNumber,
+			"note":          item.Note,
+			"targetDate":    item.TargetDate,
+			"priority":      item.Priority,
+			"expectedPrice": item.ExpectedPrice,
+			"currency":      item.Currency,
+			"store":         item.Store,
+			"storeUrl":      item.StoreURL,
+			"source":        "manual",
 		})
 	}
 	seriesMap := make(map[string]string)
 	for _, series := range h.series.List() {
 		seriesMap[series.ID] = series.Name
 	}
-	maxBySeries := make(map[string]int)
+	volumesBySeries := make(map[string][]domain.Volume)
 	for _, item := range h.userBooks.ListByUser(userID) {
-		if item.SeriesID == "" || item.VolumeNumber <= 0 {
+		if item.SeriesID == "" {
 			continue
 		}
-		if item.VolumeNumber > maxBySeries[item.SeriesID] {
-			maxBySeries[item.SeriesID] = item.VolumeNumber
-		}
+		volumesBySeries[item.SeriesID] = append(volumesBySeries[item.SeriesID], isbn.UserBookVolume(item))
+	}
+	upcomingBySeries := make(map[string][]domain.Release)
+	for _, release := range h.releases.ListUpcoming(userID) {
+		upcomingBySeries[release.SeriesID] = append(upcomingBySeries[release.SeriesID], release)
 	}
 	for _, fav := range h.favorites.ListByUser(userID) {
 		if fav.Type != "series" || fav.SeriesID == "" {
 			continue
 		}
 		name := seriesMap[fav.SeriesID]
-		nextVolume := maxBySeries[fav.SeriesID] + 1
-		if nextVolume == 1 {
-			nextVolume = 1
+		volumes := volumesBySeries[fav.SeriesID]
+		nextVolume := isbn.LatestVolume(volumes) + 1
+		releaseDate := ""
+		nextReleaseID := ""
+		for _, release := range upcomingBySeries[fav.SeriesID] {
+			if release.VolumeNumber > 0 && release.VolumeNumber == nextVolume {
+				releaseDate = release.ReleaseDate
+				nextReleaseID = release.ID
+				break
+			}
 		}
 		items = append(items, map[string]any{
-			"id":           "auto:" + fav.SeriesID,
-			"title":        name,
-			"seriesName":   name,
-			"volumeNumber": nextVolume,
-			"note":         "お気に入りから自動提案",
-			"source":       "auto",
+			"id":             "auto:" + fav.SeriesID,
+			"title":          name,
+			"seriesName":     name,
+			"volumeNumber":   nextVolume,
+			"missingVolumes": isbn.MissingVolumes(volumes),
+			"releaseDate":    releaseDate,
+			"note":           "お気に入りから自動提案",
+			"source":         "auto",
 		})
+		// 次巻以外に見つかった発売予定も発売日付きで提案する
+		seenReleases := make(map[string]struct{})
+		for _, release := range upcomingBySeries[fav.SeriesID] {
+			if release.ID == nextReleaseID {
+				continue
+			}
+			if _, ok := seenReleases[release.ID]; ok {
+				continue
+			}
+			seenReleases[release.ID] = struct{}{}
+			items = append(items, map[string]any{
+				"id":           "release:" + release.ID,
+				"title":        release.Title,
+				"seriesName":   name,
+				"volumeNumber": release.VolumeNumber,
+				"volumeLabel":  release.VolumeLabel,
+				"releaseDate":  release.ReleaseDate,
+				"note":         "発売予定",
+				"source":       "release",
+			})
+		}
 	}
-	writeJSON(w, http.StatusOK, map[string]any{
+	response := map[string]any{
 		"items": items,
-	})
+	}
+	// 月の予算が設定されていれば、当月の支出と購入予定額が予算を超えるかを知らせる
+	if settings := h.users.GetSettings(userID); settings.MonthlyBudget > 0 {
+		budget := h.spending.Budget(userID, settings, manualItems, time.Now())
+		response["budget"] = budget
+		if budget.Exceeded {
+			response["warning"] = "今月の購入予定が予算を超えています"
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
 }
 
 func (h *Handler) NextToBuyManual(w http.ResponseWriter, r *http.Request) {
@@ -1049,10 +1194,16 @@ func (h *Handler) NextToBuyManual(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var req struct {
-		Title        string `json:"title"`
-		SeriesName   string `json:"seriesName"`
-		VolumeNumber *int   `json:"volumeNumber"`
-		Note         string `json:"note"`
+		Title         string `json:"title"`
+		SeriesName    string `json:"seriesName"`
+		VolumeNumber  *int   `json:"volumeNumber"`
+		Note          string `json:"note"`
+		TargetDate    string `json:"targetDate"`
+		Priority      int    `json:"priority"`
+		ExpectedPrice int    `json:"expectedPrice"`
+		Currency      string `json:"currency"`
+		Store         string `json:"store"`
+		StoreURL      string `json:"storeUrl"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
 		badRequest(w, "invalid json")
@@ -1066,12 +1217,33 @@ func (h *Handler) NextToBuyManual(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "volumeNumber must be 0 or positive")
 		return
 	}
+	if req.TargetDate != "" && !isISODate(req.TargetDate) {
+		badRequest(w, "targetDate must be YYYY-MM-DD")
+		return
+	}
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	req.StoreURL = strings.TrimSpace(req.StoreURL)
+	if err := validateNextToBuyFields(&req.Priority, &req.ExpectedPrice, &req.Currency, &req.StoreURL); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
 	userID := userIDFromRequest(r)
 	volume := 0
 	if req.VolumeNumber != nil {
 		volume = *req.VolumeNumber
 	}
-	item, err := h.nextToBuy.Create(userID, req.Title, req.SeriesName, volume, req.Note)
+	item, err := h.nextToBuy.Create(userID, nexttobuy.CreateInput{
+		Title:         req.Title,
+		SeriesName:    req.SeriesName,
+		VolumeNumber:  volume,
+		Note:          req.Note,
+		TargetDate:    req.TargetDate,
+		Priority:      req.Priority,
+		ExpectedPrice: req.ExpectedPrice,
+		Currency:      req.Currency,
+		Store:         strings.TrimSpace(req.Store),
+		StoreURL:      req.StoreURL,
+	})
 	if err != nil {
 		internalError(w)
 		return
@@ -1080,6 +1252,10 @@ func (h *Handler) NextToBuyManual(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) NextToBuyManualByID(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathIDWithAction("/next-to-buy/manual/", "/purchase", r.URL.Path); ok {
+		h.nextToBuyPurchase(w, r, id)
+		return
+	}
 	if _, ok := pathID("/next-to-buy/manual/", r.URL.Path); !ok {
 		notFound(w)
 		return
@@ -1092,16 +1268,23 @@ func (h *Handler) NextToBuyManualByID(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		var req struct {
-			Title        *string `json:"title"`
-			SeriesName   *string `json:"seriesName"`
-			VolumeNumber *int    `json:"volumeNumber"`
-			Note         *string `json:"note"`
+			Title         *string `json:"title"`
+			SeriesName    *string `json:"seriesName"`
+			VolumeNumber  *int    `json:"volumeNumber"`
+			Note          *string `json:"note"`
+			TargetDate    *string `json:"targetDate"`
+			Priority      *int    `json:"priority"`
+			ExpectedPrice *int    `json:"expectedPrice"`
+			Currency      *string `json:"currency"`
+			Store         *string `json:"store"`
+			StoreURL      *string `json:"storeUrl"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			badRequest(w, "invalid json")
 			return
 		}
-		if req.Title == nil && req.SeriesName == nil && req.VolumeNumber == nil && req.Note == nil {
+		if req.Title == nil && req.SeriesName == nil && req.VolumeNumber == nil && req.Note == nil && req.TargetDate == nil &&
+			req.Priority == nil && req.ExpectedPrice == nil && req.Currency == nil && req.Store == nil && req.StoreURL == nil {
 			badRequest(w, "no fields to update")
 			return
 		}
@@ -1109,11 +1292,37 @@ func (h *Handler) NextToBuyManualByID(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "volumeNumber must be 0 or positive")
 			return
 		}
+		if req.TargetDate != nil && *req.TargetDate != "" && !isISODate(*req.TargetDate) {
+			badRequest(w, "targetDate must be YYYY-MM-DD")
+			return
+		}
+		if req.Currency != nil {
+			currency := strings.ToUpper(strings.TrimSpace(*req.Currency))
+			req.Currency = &currency
+		}
+		if req.Store != nil {
+			store := strings.TrimSpace(*req.Store)
+			req.Store = &store
+		}
+		if req.StoreURL != nil {
+			storeURL := strings.TrimSpace(*req.StoreURL)
+			req.StoreURL = &storeURL
+		}
+		if err := validateNextToBuyFields(req.Priority, req.ExpectedPrice, req.Currency, req.StoreURL); err != nil {
+			badRequest(w, err.Error())
+			return
+		}
 		item, ok := h.nextToBuy.Update(id, nexttobuy.UpdateInput{
-			Title:        req.Title,
-			SeriesName:   req.SeriesName,
-			VolumeNumber: req.VolumeNumber,
-			Note:         req.Note,
+			Title:         req.Title,
+			SeriesName:    req.SeriesName,
+			VolumeNumber:  req.VolumeNumber,
+			Note:          req.Note,
+			TargetDate:    req.TargetDate,
+			Priority:      req.Priority,
+			ExpectedPrice: req.ExpectedPrice,
+			Currency:      req.Currency,
+			Store:         req.Store,
+			StoreURL:      req.StoreURL,
 		})
 		if !ok {
 			notFound(w)
@@ -1273,6 +1482,7 @@ func (h *Handler) UsersProfile(w http.ResponseWriter, r *http.Request) {
 		notFound(w)
 		return
 	}
+	role, permissions := h.userPermissions(user.ID)
 	writeJSON(w, http.StatusOK, map[string]any{
 		"user": map[string]string{
 			"id":          user.ID,
@@ -1280,7 +1490,9 @@ func (h *Handler) UsersProfile(w http.ResponseWriter, r *http.Request) {
 			"userId":      user.UserID,
 			"displayName": user.DisplayName,
 		},
-		"isAdmin": h.isAdminUser(user.ID),
+		"isAdmin":     h.isAdminUser(user.ID),
+		"role":        role,
+		"permissions": permissions,
 	})
 }
 
@@ -1357,68 +1569,139 @@ func (h *Handler) UsersMe(w http.ResponseWriter, r *http.Request) {
 			}
 			displayName = &value
 		}
-		var email *string
+		// メールアドレスは直接変更せず、新しいアドレスでの確認を待つ変更として受け付ける
+		var email string
 		if req.Email != nil {
-			value := strings.TrimSpace(*req.Email)
-			if value == "" {
-				badRequest(w, "email_required")
+			if !h.allowEmailChange(w, r) {
 				return
 			}
-			if !validation.IsValidEmail(value) {
-				badRequest(w, "invalid_email")
+			value, ok := parseNewEmail(w, *req.Email)
+			if !ok {
 				return
 			}
-			email = &value
+			email = value
 		}
 		userIDFromToken := userIDFromRequest(r)
-		user, err := h.users.UpdateProfile(userIDFromToken, displayName, email)
+		var pending map[string]any
+		if current, ok := h.users.Get(userIDFromToken); ok && email != "" && !strings.EqualFold(email, current.Email) {
+			if h.emailChanges == nil {
+				internalError(w)
+				return
+			}
+			change, ok := h.requestEmailChange(w, r, userIDFromToken, email)
+			if !ok {
+				return
+			}
+			pending = emailChangeResponse(change)
+		}
+		user, err := h.users.UpdateProfile(userIDFromToken, displayName, nil)
 		if err != nil {
 			if errors.Is(err, users.ErrUserNotFound) {
 				notFound(w)
 				return
 			}
-			if errors.Is(err, users.ErrEmailExists) {
-				conflict(w, "email_exists")
-				return
-			}
 			internalError(w)
 			return
 		}
-		writeJSON(w, http.StatusOK, map[string]any{
+		response := map[string]any{
 			"user": map[string]string{
 				"id":          user.ID,
 				"email":       user.Email,
 				"userId":      user.UserID,
 				"displayName": user.DisplayName,
 			},
-		})
+		}
+		if pending != nil {
+			response["pending"] = pending
+		}
+		writeJSON(w, http.StatusOK, response)
 	case http.MethodDelete:
+		// 漏えいしたアクセストークンでアカウントを削除できないよう、ログインセッションからのみ許可する
+		if rejectAPIToken(w, r, "api tokens cannot delete accounts") {
+			return
+		}
 		userID := userIDFromRequest(r)
-		for _, item := range h.recs.List() {
-			if item.UserID == userID {
-				h.recs.Delete(item.ID)
-			}
+		if !h.checkAccountDeletion(w, userID) {
+			return
 		}
-		if !h.users.Delete(userID) {
-			notFound(w)
+		deleted, err := h.accounts.Delete(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, accounts.ErrUserNotFound) {
+				notFound(w)
+				return
+			}
+			log.Printf("ERROR: failed to delete account %s: %v", userID, err)
+			internalError(w)
 			return
 		}
-		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+		writeJSON(w, http.StatusOK, map[string]any{
+			"ok":      true,
+			"deleted": deleted,
+		})
 	default:
 		methodNotAllowed(w, http.MethodPatch, http.MethodDelete)
 	}
 }
 
+// checkAccountDeletion は ADMIN_USER_IDS のユーザーと最後の admin のアカウント削除を拒否します。
+// ほかのロールは削除と同じトランザクションで取り除きます。
+func (h *Handler) checkAccountDeletion(w http.ResponseWriter, userID string) bool {
+	if h.adminUsers == nil {
+		return true
+	}
+	user, ok := h.users.Get(userID)
+	if !ok {
+		notFound(w)
+		return false
+	}
+	switch err := h.adminUsers.CheckAccountDeletion(user.UserID); {
+	case errors.Is(err, adminusers.ErrEnvAdmin):
+		conflict(w, "env_admin")
+		return false
+	case errors.Is(err, adminusers.ErrLastAdmin):
+		conflict(w, "last_admin")
+		return false
+	}
+	return true
+}
+
+// UsersMeTakeout はユーザーの個人データを JSON ファイルにまとめた ZIP を返します。
+func (h *Handler) UsersMeTakeout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	userID := userIDFromRequest(r)
+	var buf bytes.Buffer
+	if err := h.accounts.WriteTakeout(&buf, userID); err != nil {
+		if errors.Is(err, accounts.ErrUserNotFound) {
+			notFound(w)
+			return
+		}
+		log.Printf("ERROR: failed to build takeout for %s: %v", userID, err)
+		internalError(w)
+		return
+	}
+	filename := "book_manager-takeout-" + time.Now().Format("20060102") + ".zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
 func (h *Handler) UsersMeSettings(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPatch {
 		methodNotAllowed(w, http.MethodPatch)
 		return
 	}
 	var req struct {
-		Visibility    string `json:"visibility"`
-		OpenAIEnabled *bool  `json:"openaiEnabled"`
-		OpenAIModel   string `json:"openaiModel"`
-		OpenAIAPIKey  string `json:"openaiApiKey"`
+		Visibility     string  `json:"visibility"`
+		OpenAIEnabled  *bool   `json:"openaiEnabled"`
+		OpenAIModel    string  `json:"openaiModel"`
+		OpenAIAPIKey   string  `json:"openaiApiKey"`
+		MonthlyBudget  *int    `json:"monthlyBudget"`
+		BudgetCurrency *string `json:"budgetCurrency"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
 		badRequest(w, "invalid json")
@@ -1432,10 +1715,22 @@ func (h *Handler) UsersMeSettings(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "use shared openai keys")
 		return
 	}
-	if req.OpenAIModel != "" && !h.isAdminUser(userID) {
+	if req.OpenAIModel != "" && !h.hasPermission(userID, adminusers.PermManageOpenAI) {
 		forbidden(w, "admin only")
 		return
 	}
+	budgetCurrency := ""
+	if req.BudgetCurrency != nil {
+		budgetCurrency = strings.ToUpper(strings.TrimSpace(*req.BudgetCurrency))
+		if budgetCurrency != "" && !isCurrencyCode(budgetCurrency) {
+			badRequest(w, "budgetCurrency must be a 3-letter code")
+			return
+		}
+	}
+	if req.MonthlyBudget != nil && *req.MonthlyBudget < 0 {
+		badRequest(w, "monthlyBudget must be 0 or positive")
+		return
+	}
 	settings, err := h.users.UpdateSettings(userID, req.Visibility, req.OpenAIEnabled, req.OpenAIModel, req.OpenAIAPIKey)
 	if err != nil {
 		if errors.Is(err, users.ErrInvalidVisibility) {
@@ -1445,28 +1740,35 @@ func (h *Handler) UsersMeSettings(w http.ResponseWriter, r *http.Request) {
 		internalError(w)
 		return
 	}
+	if req.MonthlyBudget != nil || req.BudgetCurrency != nil {
+		amount := settings.MonthlyBudget
+		if req.MonthlyBudget != nil {
+			amount = *req.MonthlyBudget
+		}
+		if req.BudgetCurrency == nil {
+			budgetCurrency = settings.BudgetCurrency
+		}
+		settings, err = h.users.SetMonthlyBudget(userID, amount, budgetCurrency)
+		if err != nil {
+			internalError(w)
+			return
+		}
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
 		"settings": map[string]any{
-			"visibility":    settings.Visibility,
-			"openaiEnabled": settings.OpenAIEnabled,
-			"openaiModel":   settings.OpenAIModel,
-			"openaiHasKey":  settings.OpenAIAPIKey != "",
+			"visibility":     settings.Visibility,
+			"openaiEnabled":  settings.OpenAIEnabled,
+			"openaiModel":    settings.OpenAIModel,
+			"openaiHasKey":   settings.OpenAIAPIKey != "",
+			"monthlyBudget":  settings.MonthlyBudget,
+			"budgetCurrency": settings.BudgetCurrency,
 		},
 	})
 }
 
 func (h *Handler) isAdminUser(userID string) bool {
-	if userID == "" {
-		return false
-	}
-	user, ok := h.users.Get(userID)
-	if !ok {
-		return false
-	}
-	if h.adminUsers == nil {
-		return false
-	}
-	return h.adminUsers.IsAdmin(user.UserID)
+	role, _ := h.userRole(userID)
+	return role == adminusers.RoleAdmin
 }
 
 func (h *Handler) Follows(w http.ResponseWriter, r *http.Request) {
@@ -1521,6 +1823,12 @@ func (h *Handler) BookReports(w http.ResponseWriter, r *http.Request) {
 		notFoundWithMessage(w, "book not found")
 		return
 	}
+	// モデレーターが管理 API で対応できるよう保存し、従来どおりメールでも知らせる
+	if _, err := h.bookReports.Submit(userIDFromRequest(r), req.BookID, req.Suggestion, req.Note); err != nil {
+		log.Printf("ERROR: failed to store book report: book=%s err=%v", req.BookID, err)
+		internalError(w)
+		return
+	}
 	h.reports.SendBookReport(reports.BookReport{
 		BookID:     req.BookID,
 		Suggestion: req.Suggestion,
@@ -1590,37 +1898,43 @@ func (h *Handler) SeriesByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) AdminOpenAIKeys(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
-		return
-	}
 	switch r.Method {
 	case http.MethodGet:
 		items := h.openAIKeys.List()
+		now := time.Now()
 		out := make([]map[string]any, 0, len(items))
 		for _, item := range items {
 			out = append(out, map[string]any{
-				"id":        item.ID,
-				"name":      item.Name,
-				"maskedKey": openaikeys.MaskKey(item.APIKey),
-				"createdAt": item.CreatedAt,
-				"source":    "stored",
+				"id":               item.ID,
+				"name":             item.Name,
+				"maskedKey":        openaikeys.MaskKey(item.APIKey),
+				"createdAt":        item.CreatedAt,
+				"source":           "stored",
+				"monthlyBudgetUsd": aiusage.MicroUSDToUSD(item.MonthlyBudgetMicroUSD),
+				"monthSpentUsd":    aiusage.MicroUSDToUSD(h.aiUsage.KeySpentThisMonthMicroUSD(item.ID)),
+				"withinBudget":     h.aiUsage.KeyWithinBudget(item),
+				"health":           h.openAIKeys.Health(item.ID, now),
 			})
 		}
 		if strings.TrimSpace(h.openAIAPIKey) != "" {
 			out = append(out, map[string]any{
-				"id":        "env:openai_api_key",
-				"name":      "環境変数",
-				"maskedKey": openaikeys.MaskKey(h.openAIAPIKey),
-				"createdAt": "",
-				"source":    "env",
+				"id":               "env:openai_api_key",
+				"name":             "環境変数",
+				"maskedKey":        openaikeys.MaskKey(h.openAIAPIKey),
+				"createdAt":        "",
+				"source":           "env",
+				"monthlyBudgetUsd": 0,
+				"monthSpentUsd":    aiusage.MicroUSDToUSD(h.aiUsage.KeySpentThisMonthMicroUSD(aiusage.EnvKeyID)),
+				"withinBudget":     true,
+				"health":           h.openAIKeys.Health(aiusage.EnvKeyID, now),
 			})
 		}
 		writeJSON(w, http.StatusOK, map[string]any{"items": out})
 	case http.MethodPost:
 		var req struct {
-			Name   string `json:"name"`
-			APIKey string `json:"apiKey"`
+			Name             string   `json:"name"`
+			APIKey           string   `json:"apiKey"`
+			MonthlyBudgetUSD *float64 `json:"monthlyBudgetUsd"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			badRequest(w, "invalid json")
@@ -1630,12 +1944,22 @@ func (h *Handler) AdminOpenAIKeys(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "name and apiKey are required")
 			return
 		}
+		if req.MonthlyBudgetUSD != nil && *req.MonthlyBudgetUSD < 0 {
+			badRequest(w, "monthlyBudgetUsd must be 0 or positive")
+			return
+		}
 		item := h.openAIKeys.Create(req.Name, req.APIKey)
+		if req.MonthlyBudgetUSD != nil {
+			if updated, ok := h.openAIKeys.SetMonthlyBudget(item.ID, aiusage.USDToMicroUSD(*req.MonthlyBudgetUSD)); ok {
+				item = updated
+			}
+		}
 		writeJSON(w, http.StatusOK, map[string]any{
-			"id":        item.ID,
-			"name":      item.Name,
-			"maskedKey": openaikeys.MaskKey(item.APIKey),
-			"createdAt": item.CreatedAt,
+			"id":               item.ID,
+			"name":             item.Name,
+			"maskedKey":        openaikeys.MaskKey(item.APIKey),
+			"createdAt":        item.CreatedAt,
+			"monthlyBudgetUsd": aiusage.MicroUSDToUSD(item.MonthlyBudgetMicroUSD),
 		})
 	default:
 		methodNotAllowed(w, http.MethodGet, http.MethodPost)
@@ -1643,78 +1967,288 @@ func (h *Handler) AdminOpenAIKeys(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) AdminOpenAIKeysByID(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
-		return
-	}
 	if _, ok := pathID("/admin/openai-keys/", r.URL.Path); !ok {
 		notFound(w)
 		return
 	}
-	if r.Method != http.MethodDelete {
-		methodNotAllowed(w, http.MethodDelete)
-		return
-	}
 	id, _ := pathID("/admin/openai-keys/", r.URL.Path)
-	if !h.openAIKeys.Delete(id) {
-		notFound(w)
-		return
+	switch r.Method {
+	case http.MethodPatch:
+		var req struct {
+			MonthlyBudgetUSD *float64 `json:"monthlyBudgetUsd"`
+		}
+		if err := decodeJSON(r, &req); err != nil {
+			badRequest(w, "invalid json")
+			return
+		}
+		if req.MonthlyBudgetUSD == nil {
+			badRequest(w, "monthlyBudgetUsd is required")
+			return
+		}
+		if *req.MonthlyBudgetUSD < 0 {
+			badRequest(w, "monthlyBudgetUsd must be 0 or positive")
+			return
+		}
+		item, ok := h.openAIKeys.SetMonthlyBudget(id, aiusage.USDToMicroUSD(*req.MonthlyBudgetUSD))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"id":               item.ID,
+			"name":             item.Name,
+			"maskedKey":        openaikeys.MaskKey(item.APIKey),
+			"createdAt":        item.CreatedAt,
+			"monthlyBudgetUsd": aiusage.MicroUSDToUSD(item.MonthlyBudgetMicroUSD),
+		})
+	case http.MethodDelete:
+		if !h.openAIKeys.Delete(id) {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	default:
+		methodNotAllowed(w, http.MethodPatch, http.MethodDelete)
 	}
-	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
 
 func (h *Handler) AdminOpenAIModels(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
-		return
-	}
 	if r.Method != http.MethodGet {
 		methodNotAllowed(w, http.MethodGet)
 		return
 	}
-	sharedKey, ok := h.openAIKeys.First()
-	apiKey := h.openAIAPIKey
-	if ok && strings.TrimSpace(sharedKey.APIKey) != "" {
-		apiKey = sharedKey.APIKey
+	keys := h.openAIKeyCandidates()
+	if len(keys) == 0 {
+		keys = []openAIKeyCandidate{{}}
 	}
-	if strings.TrimSpace(apiKey) == "" {
+	var lastErr error
+	tried := false
+	for _, key := range keys {
+		client := ai.NewOpenAIClient(h.openAIBaseURL, strings.TrimSpace(key.apiKey), "", "")
+		// 使えないキーがあっても残りのキーで取得を試す
+		if !client.Available() {
+			continue
+		}
+		tried = true
+		models, err := client.ListModels(r.Context())
+		h.reportOpenAIKeyResult(key.id, err)
+		if err == nil {
+			writeJSON(w, http.StatusOK, map[string]any{"items": h.filterChatModels(models)})
+			return
+		}
+		lastErr = err
+		if !ai.IsKeyError(err) {
+			break
+		}
+	}
+	if !tried {
 		badRequest(w, "shared api key is required")
 		return
 	}
-	models, err := fetchOpenAIModels(apiKey)
-	if err != nil {
-		internalError(w)
+	log.Printf("openai models fetch error: %v", lastErr)
+	internalError(w)
+}
+
+type openAIKeyCandidate struct {
+	id     string
+	apiKey string
+}
+
+// openAIKeyCandidates は試す順に並べた API キーを返します。
+// 当月の予算内にある正常な共有キーを選択戦略の順に並べ、最後に環境変数のキーを加えます。
+func (h *Handler) openAIKeyCandidates() []openAIKeyCandidate {
+	candidates := make([]openAIKeyCandidate, 0)
+	for _, key := range h.openAIKeys.Candidates(h.aiUsage.KeyTokensThisMonth) {
+		if !h.aiUsage.KeyWithinBudget(key) {
+			continue
+		}
+		candidates = append(candidates, openAIKeyCandidate{id: key.ID, apiKey: key.APIKey})
+	}
+	if strings.TrimSpace(h.openAIAPIKey) != "" {
+		candidates = append(candidates, openAIKeyCandidate{id: aiusage.EnvKeyID, apiKey: h.openAIAPIKey})
+	}
+	return candidates
+}
+
+// reportOpenAIKeyResult は API 呼び出しの結果をキーの稼働状況に反映します。
+// キーの失効やレート制限のみ異常とし、それ以外のエラーではキーの状態を変えません。
+func (h *Handler) reportOpenAIKeyResult(keyID string, err error) {
+	if keyID == "" {
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": models})
+	if err == nil {
+		h.openAIKeys.MarkHealthy(keyID)
+		return
+	}
+	var statusErr *ai.StatusError
+	if !ai.IsKeyError(err) || !errors.As(err, &statusErr) {
+		return
+	}
+	log.Printf("openai key unhealthy: key=%s status=%d", keyID, statusErr.StatusCode)
+	h.openAIKeys.MarkUnhealthy(keyID, statusErr.StatusCode, http.StatusText(statusErr.StatusCode), statusErr.RetryAfter)
 }
 
-type openAIModelsResponse struct {
-	Data []struct {
-		ID string `json:"id"`
-	} `json:"data"`
+// openAIClient はトークン使用量をキーとユーザーに記録するクライアントを返します。
+// API キーがなく、OpenAI 互換サーバーも設定されていない場合は false を返します。
+func (h *Handler) openAIClient(userID string, key openAIKeyCandidate, model string) (*ai.OpenAIClient, bool) {
+	client := ai.NewOpenAIClient(h.openAIBaseURL, key.apiKey, model, h.prompts.Current())
+	if !client.Available() {
+		return nil, false
+	}
+	client.SetCircuitBreaker(h.aiBreaker)
+	client.SetMetrics(h.aiMetrics)
+	keyID := key.id
+	client.OnUsage(func(usage ai.Usage) {
+		if err := h.aiUsage.Record(keyID, userID, usage); err != nil {
+			log.Printf("openai usage record error: %v", err)
+		}
+	})
+	return client, true
 }
 
-func (h *Handler) AdminInvitations(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
-		return
+// userSeriesClassifier はユーザーの設定と既存シリーズを使ったシリーズ推定器を返します。
+// ローカル推定で十分な信頼度が得られれば LLM には問い合わせず、1 日の上限に達したユーザーはローカル推定のみです。
+func (h *Handler) userSeriesClassifier(userID string, settings domain.ProfileSettings, keys []openAIKeyCandidate) ai.SeriesClassifier {
+	model := h.openAIDefaultModel
+	if settings.OpenAIModel != "" {
+		model = settings.OpenAIModel
+	}
+	var remote ai.SeriesClassifier
+	if !h.aiUsage.AllowUser(userID) {
+		log.Printf("openai daily quota exceeded: user=%s", userID)
+	} else if classifier, ok := h.seriesClassifier(userID, keys, model); ok {
+		remote = classifier
+	}
+	return ai.NewCombinedClassifier(ai.NewLocalClassifier(h.knownSeries(userID)), remote)
+}
+
+// seriesClassifier はシリーズ推定に使うクライアントを返します。推定結果は ISBN ごとにキャッシュされ、
+// API を呼び出した場合はトークン使用量をキーとユーザーに記録します。
+// キーが失効・レート制限で失敗した場合は次のキーで再試行します。
+// API キーがなく、OpenAI 互換サーバーも設定されていない場合は false を返します。
+func (h *Handler) seriesClassifier(userID string, keys []openAIKeyCandidate, model string) (ai.SeriesClassifier, bool) {
+	if len(keys) == 0 {
+		keys = []openAIKeyCandidate{{}}
+	}
+	candidates := make([]ai.KeyedClassifier, 0, len(keys))
+	var first *ai.OpenAIClient
+	for _, key := range keys {
+		client, ok := h.openAIClient(userID, key, model)
+		if !ok {
+			continue
+		}
+		if first == nil {
+			first = client
+		}
+		candidates = append(candidates, ai.KeyedClassifier{KeyID: key.id, Classifier: client})
+	}
+	if first == nil {
+		return nil, false
 	}
+	failover := ai.NewFailoverClassifier(candidates, h.reportOpenAIKeyResult)
+	return h.seriesGuesses.Wrap(failover, first.Model(), first.PromptHash()), true
+}
+
+// mergeSeriesGuess はタイトルから推定した巻数に分類器の推定結果を反映し、シリーズ名を正規化します。
+// guess が nil（推定に失敗）またはシリーズ名のないシリーズ判定の場合はタイトルからの推定を使います。
+func mergeSeriesGuess(base isbn.SeriesGuess, guess *ai.SeriesGuess) isbn.SeriesGuess {
+	if guess != nil {
+		if guess.IsSeries && strings.TrimSpace(guess.Name) != "" {
+			volume := base.Volume
+			if guess.VolumeNumber > 0 && guess.VolumeNumber != isbn.VolumeNumberOf(volume) {
+				volume = isbn.NumberVolume(guess.VolumeNumber)
+			}
+			base = isbn.SeriesGuess{
+				Name:         guess.Name,
+				VolumeNumber: isbn.VolumeNumberOf(volume),
+				Volume:       volume,
+			}
+		} else if !guess.IsSeries {
+			base = isbn.SeriesGuess{}
+		}
+	}
+	if base.Name != "" {
+		base.Name = isbn.NormalizeSeriesName(base.Name)
+	}
+	if base.Name == "" {
+		base.VolumeNumber = 0
+		base.Volume = domain.Volume{}
+	}
+	return base
+}
+
+// knownSeries はユーザーの所蔵からシリーズごとの著者・タイトルを集めます。
+func (h *Handler) knownSeries(userID string) []ai.KnownSeries {
+	userItems := h.userBooks.ListByUser(userID)
+	bookIDs := make([]string, 0, len(userItems))
+	for _, item := range userItems {
+		if item.SeriesID != "" {
+			bookIDs = append(bookIDs, item.BookID)
+		}
+	}
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	booksByID := make(map[string]domain.Book)
+	for _, book := range h.books.ListByIDs(bookIDs) {
+		booksByID[book.ID] = book
+	}
+	seriesNames := make(map[string]string)
+	for _, series := range h.series.List() {
+		seriesNames[series.ID] = series.Name
+	}
+	indexes := make(map[string]int)
+	known := make([]ai.KnownSeries, 0)
+	for _, item := range userItems {
+		name := seriesNames[item.SeriesID]
+		book, ok := booksByID[item.BookID]
+		if name == "" || !ok {
+			continue
+		}
+		index, ok := indexes[item.SeriesID]
+		if !ok {
+			index = len(known)
+			indexes[item.SeriesID] = index
+			known = append(known, ai.KnownSeries{Name: name})
+		}
+		known[index].Authors = append(known[index].Authors, book.Authors...)
+		title := book.OriginalTitle
+		if title == "" {
+			title = book.Title
+		}
+		known[index].Titles = append(known[index].Titles, title)
+	}
+	return known
+}
+
+// filterChatModels は OpenAI 本体の場合のみ、チャット用のモデルに絞り込みます。
+// 互換サーバーのモデル名は命名規則が異なるためそのまま返します。
+func (h *Handler) filterChatModels(models []string) []string {
+	baseURL := strings.TrimRight(strings.TrimSpace(h.openAIBaseURL), "/")
+	if baseURL != "" && baseURL != ai.DefaultBaseURL {
+		return models
+	}
+	filtered := make([]string, 0, len(models))
+	for _, model := range models {
+		if strings.HasPrefix(model, "gpt-") || strings.HasPrefix(model, "o") {
+			filtered = append(filtered, model)
+		}
+	}
+	return filtered
+}
+
+func (h *Handler) AdminInvitations(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		items := h.adminInvitations.List()
+		items, err := h.adminInvitations.ListByStatus(strings.TrimSpace(r.URL.Query().Get("status")))
+		if err != nil {
+			badRequest(w, "invalid_status")
+			return
+		}
+		now := time.Now()
 		out := make([]map[string]any, 0, len(items))
 		for _, item := range items {
-			out = append(out, map[string]any{
-				"id":        item.ID,
-				"userId":    item.UserID,
-				"email":     item.Email,
-				"createdBy": item.CreatedBy,
-				"expiresAt": item.ExpiresAt,
-				"usedAt":    item.UsedAt,
-				"usedBy":    item.UsedBy,
-				"createdAt": item.CreatedAt,
-			})
+			out = append(out, invitationResponse(item, now))
 		}
 		writeJSON(w, http.StatusOK, map[string]any{"items": out})
 	case http.MethodPost:
@@ -1747,13 +2281,8 @@ func (h *Handler) AdminInvitations(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		// 管理者ロールは招待の受諾（POST /auth/signup/admin）時に付与する
 		createdBy := userIDFromRequest(r)
-		if h.adminUsers != nil && !h.adminUsers.IsAdmin(userID) {
-			if err := h.adminUsers.Add(userID, createdBy); err != nil && !errors.Is(err, adminusers.ErrAlreadyAdmin) {
-				internalError(w)
-				return
-			}
-		}
 		invitation, err := h.adminInvitations.Create(createdBy, userID, email)
 		if err != nil {
 			if errors.Is(err, admininvitations.ErrUserIDAlreadyTaken) {
@@ -1767,14 +2296,7 @@ func (h *Handler) AdminInvitations(w http.ResponseWriter, r *http.Request) {
 			internalError(w)
 			return
 		}
-		if h.reports != nil && email != "" {
-			h.reports.SendAdminInvitation(reports.AdminInvitationEmail{
-				To:        email,
-				UserID:    userID,
-				Token:     invitation.Token,
-				ExpiresAt: invitation.ExpiresAt,
-			})
-		}
+		h.sendAdminInvitation(invitation)
 		writeJSON(w, http.StatusOK, map[string]any{
 			"id":        invitation.ID,
 			"token":     invitation.Token,
@@ -1788,12 +2310,50 @@ func (h *Handler) AdminInvitations(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// AdminInvitationsByID は招待の取り消し（DELETE）と、
+// POST /admin/invitations/{id}/resend によるトークンの再発行・再送、POST /admin/invitations/{id}/extend による有効期限の延長を行います。
 func (h *Handler) AdminInvitationsByID(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
+	if id, ok := pathIDWithAction("/admin/invitations/", "/resend", r.URL.Path); ok {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		invitation, err := h.adminInvitations.Resend(id)
+		if err != nil {
+			writeInvitationError(w, err)
+			return
+		}
+		h.sendAdminInvitation(invitation)
+		response := invitationResponse(invitation, time.Now())
+		response["token"] = invitation.Token
+		writeJSON(w, http.StatusOK, response)
 		return
 	}
-	if _, ok := pathID("/admin/invitations/", r.URL.Path); !ok {
+	if id, ok := pathIDWithAction("/admin/invitations/", "/extend", r.URL.Path); ok {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		var req struct {
+			Hours int `json:"hours"`
+		}
+		if err := decodeJSON(r, &req); err != nil {
+			badRequest(w, "invalid json")
+			return
+		}
+		if req.Hours == 0 {
+			req.Hours = admininvitations.ExpirationHours
+		}
+		invitation, err := h.adminInvitations.Extend(id, req.Hours)
+		if err != nil {
+			writeInvitationError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, invitationResponse(invitation, time.Now()))
+		return
+	}
+	id, ok := pathID("/admin/invitations/", r.URL.Path)
+	if !ok {
 		notFound(w)
 		return
 	}
@@ -1801,7 +2361,6 @@ func (h *Handler) AdminInvitationsByID(w http.ResponseWriter, r *http.Request) {
 		methodNotAllowed(w, http.MethodDelete)
 		return
 	}
-	id, _ := pathID("/admin/invitations/", r.URL.Path)
 	if !h.adminInvitations.Delete(id) {
 		notFound(w)
 		return
@@ -1809,11 +2368,46 @@ func (h *Handler) AdminInvitationsByID(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
 
-func (h *Handler) AdminUsers(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
+func (h *Handler) sendAdminInvitation(invitation domain.AdminInvitation) {
+	if h.reports == nil || invitation.Email == "" {
 		return
 	}
+	h.reports.SendAdminInvitation(reports.AdminInvitationEmail{
+		To:        invitation.Email,
+		UserID:    invitation.UserID,
+		Token:     invitation.Token,
+		ExpiresAt: invitation.ExpiresAt,
+	})
+}
+
+func invitationResponse(item domain.AdminInvitation, now time.Time) map[string]any {
+	return map[string]any{
+		"id":        item.ID,
+		"userId":    item.UserID,
+		"email":     item.Email,
+		"status":    admininvitations.Status(item, now),
+		"createdBy": item.CreatedBy,
+		"expiresAt": item.ExpiresAt,
+		"usedAt":    item.UsedAt,
+		"usedBy":    item.UsedBy,
+		"createdAt": item.CreatedAt,
+	}
+}
+
+func writeInvitationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, admininvitations.ErrInvitationNotFound):
+		notFound(w)
+	case errors.Is(err, admininvitations.ErrInvitationUsed):
+		conflict(w, "invitation_already_used")
+	case errors.Is(err, admininvitations.ErrInvalidExtension):
+		badRequest(w, "invalid_hours")
+	default:
+		internalError(w)
+	}
+}
+
+func (h *Handler) AdminUsers(w http.ResponseWriter, r *http.Request) {
 	if h.adminUsers == nil {
 		internalError(w)
 		return
@@ -1832,6 +2426,7 @@ func (h *Handler) AdminUsers(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		var req struct {
 			UserID string `json:"userId"`
+			Role   string `json:"role"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			badRequest(w, "invalid json")
@@ -1842,12 +2437,20 @@ func (h *Handler) AdminUsers(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "user_id_required")
 			return
 		}
+		role := strings.TrimSpace(req.Role)
+		if role == "" {
+			role = adminusers.RoleAdmin
+		}
 		if !h.users.IsUserIDTaken(userID) {
 			notFound(w)
 			return
 		}
 		createdBy := userIDFromRequest(r)
-		if err := h.adminUsers.Add(userID, createdBy); err != nil {
+		if err := h.adminUsers.Add(userID, role, createdBy); err != nil {
+			if errors.Is(err, adminusers.ErrInvalidRole) {
+				badRequest(w, "invalid_role")
+				return
+			}
 			if errors.Is(err, adminusers.ErrAlreadyAdmin) {
 				conflict(w, "already_admin")
 				return
@@ -1855,6 +2458,7 @@ func (h *Handler) AdminUsers(w http.ResponseWriter, r *http.Request) {
 			internalError(w)
 			return
 		}
+		h.notifyRoleGranted(r, userID, role)
 		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 	default:
 		methodNotAllowed(w, http.MethodGet, http.MethodPost)
@@ -1862,10 +2466,6 @@ func (h *Handler) AdminUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) AdminUsersByID(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdminUser(userIDFromRequest(r)) {
-		forbidden(w, "admin only")
-		return
-	}
 	if h.adminUsers == nil {
 		internalError(w)
 		return
@@ -1874,8 +2474,12 @@ func (h *Handler) AdminUsersByID(w http.ResponseWriter, r *http.Request) {
 		notFound(w)
 		return
 	}
+	if r.Method == http.MethodPatch {
+		h.updateAdminUserRole(w, r)
+		return
+	}
 	if r.Method != http.MethodDelete {
-		methodNotAllowed(w, http.MethodDelete)
+		methodNotAllowed(w, http.MethodPatch, http.MethodDelete)
 		return
 	}
 	confirm := strings.TrimSpace(r.URL.Query().Get("confirm"))
@@ -1889,6 +2493,10 @@ func (h *Handler) AdminUsersByID(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "cannot_remove_env_admin")
 			return
 		}
+		if errors.Is(err, adminusers.ErrLastAdmin) {
+			conflict(w, "last_admin")
+			return
+		}
 		if errors.Is(err, adminusers.ErrNotDBAdmin) {
 			notFound(w)
 			return
@@ -1899,36 +2507,6 @@ func (h *Handler) AdminUsersByID(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
 
-func fetchOpenAIModels(apiKey string) ([]string, error) {
-	req, err := http.NewRequest(http.MethodGet, "https://api.openai.com/v1/models", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("openai models fetch failed")
-	}
-	var payload openAIModelsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, err
-	}
-	models := make([]string, 0, len(payload.Data))
-	for _, model := range payload.Data {
-		if model.ID == "" {
-			continue
-		}
-		if strings.HasPrefix(model.ID, "gpt-") || strings.HasPrefix(model.ID, "o") {
-			models = append(models, model.ID)
-		}
-	}
-	return models, nil
-}
-
 func pathID(prefix, path string) (string, bool) {
 	if !strings.HasPrefix(path, prefix) {
 		return "", false
@@ -1940,6 +2518,14 @@ func pathID(prefix, path string) (string, bool) {
 	return id, true
 }
 
+// pathIDWithAction は "/prefix/{id}/action" 形式のパスから ID を取り出します。
+func pathIDWithAction(prefix, action, path string) (string, bool) {
+	if !strings.HasSuffix(path, action) {
+		return "", false
+	}
+	return pathID(prefix, strings.TrimSuffix(path, action))
+}
+
 func userIDFromRequest(r *http.Request) string {
 	return strings.TrimSpace(authctx.UserIDFromContext(r.Context()))
 }
@@ -1976,6 +2562,7 @@ type bookRequest struct {
 	IsSeries      bool     `json:"isSeries"`
 	SeriesName    string   `json:"seriesName"`
 	VolumeNumber  *int     `json:"volumeNumber"`
+	VolumeLabel   *string  `json:"volumeLabel"`
 }
 
 func normalizeISBN13(value string) string {
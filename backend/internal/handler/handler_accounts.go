@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"book_manager/backend/internal/accounts"
+	"book_manager/backend/internal/adminusers"
+	"book_manager/backend/internal/config"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/pagination"
+)
+
+// AdminAccounts は全ユーザーのアカウントを検索・並び替えして返します。
+// GET /admin/accounts?query=&status=active|suspended&sort=userId|libraryCount|lastActiveAt|emailVerified&order=asc|desc&page=&pageSize=
+func (h *Handler) AdminAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if h.accounts == nil {
+		internalError(w)
+		return
+	}
+	params := r.URL.Query()
+	order := strings.TrimSpace(params.Get("order"))
+	if order != "" && order != "asc" && order != "desc" {
+		badRequest(w, "invalid_order")
+		return
+	}
+	items, err := h.accounts.Search(accounts.Query{
+		Search: params.Get("query"),
+		Status: strings.TrimSpace(params.Get("status")),
+		Sort:   strings.TrimSpace(params.Get("sort")),
+		Desc:   order == "desc",
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, accounts.ErrInvalidSort):
+			badRequest(w, "invalid_sort")
+		case errors.Is(err, accounts.ErrInvalidStatus):
+			badRequest(w, "invalid_status")
+		default:
+			internalError(w)
+		}
+		return
+	}
+	paging := pagination.ParseParams(r, config.AdminPageSize)
+	total := len(items)
+	start, end := paging.SliceRange(total)
+	out := make([]map[string]any, 0, end-start)
+	for _, item := range items[start:end] {
+		out = append(out, h.accountResponse(item))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":    out,
+		"total":    total,
+		"page":     paging.Page,
+		"pageSize": paging.PageSize,
+	})
+}
+
+// AdminAccountsByID はアカウントの詳細（GET /admin/accounts/{userId}）と、
+// 利用停止（POST .../suspend）・停止の解除（POST .../unsuspend）・強制ログアウト（POST .../logout）を扱います。
+func (h *Handler) AdminAccountsByID(w http.ResponseWriter, r *http.Request) {
+	if h.accounts == nil {
+		internalError(w)
+		return
+	}
+	for _, action := range []string{"/suspend", "/unsuspend", "/logout"} {
+		userID, ok := pathIDWithAction("/admin/accounts/", action, r.URL.Path)
+		if !ok {
+			continue
+		}
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		user, ok := h.users.GetByUserID(userID)
+		if !ok {
+			notFound(w)
+			return
+		}
+		if !h.canManageAccount(w, r, user) {
+			return
+		}
+		switch action {
+		case "/suspend":
+			h.suspendAccount(w, r, user)
+		case "/unsuspend":
+			h.unsuspendAccount(w, user)
+		case "/logout":
+			revoked, err := h.forceLogout(r.Context(), user)
+			if err != nil {
+				internalError(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"ok": true, "revokedSessions": revoked})
+		}
+		return
+	}
+	userID, ok := pathID("/admin/accounts/", r.URL.Path)
+	if !ok {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	user, ok := h.users.GetByUserID(userID)
+	if !ok {
+		notFound(w)
+		return
+	}
+	summary, err := h.accounts.Summary(user.ID)
+	if err != nil {
+		if errors.Is(err, accounts.ErrUserNotFound) {
+			notFound(w)
+			return
+		}
+		internalError(w)
+		return
+	}
+	response := h.accountResponse(summary)
+	if h.sessions != nil {
+		response["activeSessions"] = len(h.sessions.List(user.ID))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) suspendAccount(w http.ResponseWriter, r *http.Request, user domain.User) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			badRequest(w, "invalid json")
+			return
+		}
+	}
+	suspension, err := h.accounts.Suspend(user.ID, userIDFromRequest(r), req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, accounts.ErrReasonTooLong):
+			badRequest(w, "reason_too_long")
+		case errors.Is(err, accounts.ErrUserNotFound):
+			notFound(w)
+		default:
+			internalError(w)
+		}
+		return
+	}
+	// 停止中のユーザーのリクエストはミドルウェアで拒否するが、リフレッシュトークンも失効させて再ログインを求める
+	revoked, err := h.forceLogout(r.Context(), user)
+	if err != nil {
+		log.Printf("WARNING: account %s suspended but force logout failed: %v", user.ID, err)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":              true,
+		"suspension":      suspensionResponse(suspension),
+		"revokedSessions": revoked,
+	})
+}
+
+func (h *Handler) unsuspendAccount(w http.ResponseWriter, user domain.User) {
+	if err := h.accounts.Unsuspend(user.ID); err != nil {
+		switch {
+		case errors.Is(err, accounts.ErrNotSuspended):
+			conflict(w, "not_suspended")
+		case errors.Is(err, accounts.ErrUserNotFound):
+			notFound(w)
+		default:
+			internalError(w)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// canManageAccount は自分自身と、ロールの管理権限がない場合はロールを持つユーザーへの操作を拒否します。
+func (h *Handler) canManageAccount(w http.ResponseWriter, r *http.Request, target domain.User) bool {
+	callerID := userIDFromRequest(r)
+	if target.ID == callerID {
+		badRequest(w, "cannot_manage_self")
+		return false
+	}
+	if h.adminUsers != nil && h.adminUsers.HasRole(target.UserID) && !h.hasPermission(callerID, adminusers.PermManageRoles) {
+		forbidden(w, "cannot_manage_privileged_account")
+		return false
+	}
+	return true
+}
+
+// forceLogout は認証バックエンドのリフレッシュトークン（Firebase は Admin SDK）とセッションをすべて失効させます。
+// 発行済みのアクセストークンは有効期限まで検証を通るため、即時に止める場合は利用停止と併用します。
+func (h *Handler) forceLogout(ctx context.Context, user domain.User) (int, error) {
+	if h.authBackend == nil {
+		return 0, errors.New("auth backend is not configured")
+	}
+	if err := h.authBackend.RevokeRefreshTokens(ctx, user.ID); err != nil {
+		log.Printf("ERROR: failed to revoke refresh tokens for %s: %v", user.ID, err)
+		return 0, err
+	}
+	if h.sessions == nil {
+		return 0, nil
+	}
+	return h.sessions.RevokeAll(user.ID), nil
+}
+
+// isSuspended は /auth/* でトークンを発行する前に利用停止中のアカウントを確認します。
+func (h *Handler) isSuspended(userID string) bool {
+	return h.accounts != nil && h.accounts.IsSuspended(userID)
+}
+
+func (h *Handler) accountResponse(summary accounts.Summary) map[string]any {
+	role := ""
+	if h.adminUsers != nil {
+		role, _ = h.adminUsers.RoleOf(summary.User.UserID)
+	}
+	response := map[string]any{
+		"id":            summary.User.ID,
+		"userId":        summary.User.UserID,
+		"email":         summary.User.Email,
+		"displayName":   summary.User.DisplayName,
+		"emailVerified": summary.User.EmailVerified,
+		"libraryCount":  summary.LibraryCount,
+		"lastActiveAt":  summary.LastActiveAt,
+		"role":          role,
+		"suspended":     summary.Suspension != nil,
+		"suspension":    nil,
+	}
+	if summary.Suspension != nil {
+		response["suspension"] = suspensionResponse(*summary.Suspension)
+	}
+	return response
+}
+
+func suspensionResponse(suspension domain.AccountSuspension) map[string]any {
+	return map[string]any{
+		"reason":      suspension.Reason,
+		"suspendedBy": suspension.SuspendedBy,
+		"suspendedAt": suspension.SuspendedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"book_manager/backend/internal/apitokens"
+	"book_manager/backend/internal/authctx"
+	"book_manager/backend/internal/domain"
+)
+
+// UsersMeAPITokens は個人用アクセストークンの一覧取得と発行を行います。
+// 発行したトークンの平文は作成時のレスポンスでのみ返します。
+func (h *Handler) UsersMeAPITokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.apiTokenOwner(w, r)
+	if !ok {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		items := h.apiTokens.List(userID)
+		result := make([]map[string]any, 0, len(items))
+		for _, token := range items {
+			result = append(result, apiTokenResponse(token))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items": result,
+		})
+	case http.MethodPost:
+		var req struct {
+			Name          string `json:"name"`
+			Scope         string `json:"scope"`
+			ExpiresInDays int    `json:"expiresInDays"`
+		}
+		if err := decodeJSON(r, &req); err != nil {
+			badRequest(w, "invalid json")
+			return
+		}
+		token, raw, err := h.apiTokens.Create(userID, req.Name, req.Scope, req.ExpiresInDays)
+		if err != nil {
+			switch {
+			case errors.Is(err, apitokens.ErrInvalidName):
+				badRequest(w, "name is required (max 100 characters)")
+			case errors.Is(err, apitokens.ErrInvalidScope):
+				badRequest(w, "scope must be read or read-write")
+			case errors.Is(err, apitokens.ErrInvalidExpiry):
+				badRequest(w, "expiresInDays must be between 1 and 365")
+			case errors.Is(err, apitokens.ErrTooManyTokens):
+				conflict(w, "too many active tokens")
+			default:
+				log.Printf("api token create error: %v", err)
+				internalError(w)
+			}
+			return
+		}
+		result := apiTokenResponse(token)
+		result["token"] = raw
+		writeJSON(w, http.StatusCreated, result)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// UsersMeAPITokensByID はトークンを失効させます（DELETE /users/me/api-tokens/{id}）。
+func (h *Handler) UsersMeAPITokensByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID("/users/me/api-tokens/", r.URL.Path)
+	if !ok {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	userID, ok := h.apiTokenOwner(w, r)
+	if !ok {
+		return
+	}
+	if err := h.apiTokens.Revoke(userID, id); err != nil {
+		if errors.Is(err, apitokens.ErrTokenNotFound) {
+			notFound(w)
+			return
+		}
+		internalError(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// apiTokenOwner はトークンを管理するユーザーを返します。
+// 漏えいしたトークンで新しいトークンを作れないよう、トークンの管理はログインセッションからのみ許可します。
+func (h *Handler) apiTokenOwner(w http.ResponseWriter, r *http.Request) (string, bool) {
+	info, ok := authctx.AuthInfoFromContext(r.Context())
+	if !ok || info.UserID == "" {
+		unauthorized(w)
+		return "", false
+	}
+	if rejectAPIToken(w, r, "api tokens cannot manage api tokens") {
+		return "", false
+	}
+	if h.apiTokens == nil {
+		internalError(w)
+		return "", false
+	}
+	return info.UserID, true
+}
+
+// rejectAPIToken は個人用アクセストークンで認証したリクエストを 403 で拒否し、拒否した場合は true を返します。
+// 漏えいしたトークンから管理操作や新しい認証情報の発行ができないよう、ログインセッションに限る操作で使います。
+func rejectAPIToken(w http.ResponseWriter, r *http.Request, message string) bool {
+	if info, _ := authctx.AuthInfoFromContext(r.Context()); info.APITokenScope != "" {
+		forbidden(w, message)
+		return true
+	}
+	return false
+}
+
+func apiTokenResponse(token domain.APIToken) map[string]any {
+	var lastUsedAt any
+	if token.LastUsedAt != nil {
+		lastUsedAt = token.LastUsedAt.Format(time.RFC3339)
+	}
+	return map[string]any{
+		"id":         token.ID,
+		"name":       token.Name,
+		"scope":      token.Scope,
+		"prefix":     token.Prefix,
+		"createdAt":  token.CreatedAt.Format(time.RFC3339),
+		"expiresAt":  token.ExpiresAt.Format(time.RFC3339),
+		"lastUsedAt": lastUsedAt,
+	}
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/auditlogs"
+	"book_manager/backend/internal/config"
+	"book_manager/backend/internal/domain"
+)
+
+var auditLogCSVHeader = []string{"id", "createdAt", "uid", "userId", "action", "entity", "entityId", "ip", "userAgent", "payload"}
+
+// AdminAuditLogs は監査ログを新しい順に検索します。
+// GET /admin/audit-logs?userId=&uid=&entity=&entityId=&action=&from=&to=&cursor=&limit=
+func (h *Handler) AdminAuditLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if h.auditLogs == nil {
+		internalError(w)
+		return
+	}
+	filter, ok := h.parseAuditLogFilter(w, r)
+	if !ok {
+		return
+	}
+	limit := config.AdminPageSize
+	if value := strings.TrimSpace(r.URL.Query().Get("limit")); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			badRequest(w, "invalid_limit")
+			return
+		}
+		limit = min(parsed, config.MaxPageSize)
+	}
+	page, err := h.auditLogs.Search(filter, strings.TrimSpace(r.URL.Query().Get("cursor")), limit)
+	if err != nil {
+		writeAuditLogError(w, err)
+		return
+	}
+	userIDs := map[string]string{}
+	items := make([]map[string]any, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, auditLogResponse(item, h.publicUserID(userIDs, item.UserID)))
+	}
+	var next any
+	if page.NextCursor != "" {
+		next = page.NextCursor
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":      items,
+		"nextCursor": next,
+	})
+}
+
+// AdminAuditLogsExport は検索条件に一致する監査ログを CSV または NDJSON で書き出します。
+// GET /admin/audit-logs/export?format=csv|ndjson&（AdminAuditLogs と同じ絞り込み条件）
+func (h *Handler) AdminAuditLogsExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if h.auditLogs == nil {
+		internalError(w)
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		badRequest(w, "invalid_format")
+		return
+	}
+	filter, ok := h.parseAuditLogFilter(w, r)
+	if !ok {
+		return
+	}
+
+	// ヘッダーは最初の記録を書き出す直前に送り、件数超過などのエラーは通常の JSON で返す
+	buf := bufio.NewWriter(w)
+	csvWriter := csv.NewWriter(buf)
+	encoder := json.NewEncoder(buf)
+	userIDs := map[string]string{}
+	started := false
+	start := func() error {
+		started = true
+		filename := "audit-logs-" + time.Now().Format("20060102-150405") + "." + format
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		if format == "csv" {
+			return csvWriter.Write(auditLogCSVHeader)
+		}
+		return nil
+	}
+	err := h.auditLogs.Export(filter, func(item domain.AuditLog) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		userID := h.publicUserID(userIDs, item.UserID)
+		if format == "ndjson" {
+			return encoder.Encode(auditLogResponse(item, userID))
+		}
+		return csvWriter.Write(auditLogCSVRecord(item, userID))
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil && !started {
+		writeAuditLogError(w, err)
+		return
+	}
+	csvWriter.Flush()
+	if err == nil {
+		err = csvWriter.Error()
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		log.Printf("ERROR: failed to export audit logs: %v", err)
+	}
+}
+
+// parseAuditLogFilter はクエリ文字列から絞り込み条件を作ります。
+// userId は公開ユーザー ID で、退会済みのユーザーや未ログインの記録は uid（記録された内部 ID）で絞り込みます。
+// from / to は RFC 3339 か YYYY-MM-DD（to の日付は当日を含む）です。
+func (h *Handler) parseAuditLogFilter(w http.ResponseWriter, r *http.Request) (auditlogs.Filter, bool) {
+	params := r.URL.Query()
+	filter := auditlogs.Filter{
+		UserID:   strings.TrimSpace(params.Get("uid")),
+		Entity:   params.Get("entity"),
+		EntityID: params.Get("entityId"),
+		Action:   params.Get("action"),
+	}
+	if userID := strings.TrimSpace(params.Get("userId")); userID != "" {
+		if filter.UserID != "" {
+			badRequest(w, "userId and uid are exclusive")
+			return auditlogs.Filter{}, false
+		}
+		user, ok := h.users.GetByUserID(userID)
+		if !ok {
+			notFound(w)
+			return auditlogs.Filter{}, false
+		}
+		filter.UserID = user.ID
+	}
+	var err error
+	if filter.From, err = parseAuditLogTime(params.Get("from"), false); err != nil {
+		badRequest(w, "invalid_from")
+		return auditlogs.Filter{}, false
+	}
+	if filter.To, err = parseAuditLogTime(params.Get("to"), true); err != nil {
+		badRequest(w, "invalid_to")
+		return auditlogs.Filter{}, false
+	}
+	return filter, true
+}
+
+func parseAuditLogTime(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
+}
+
+func writeAuditLogError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auditlogs.ErrInvalidCursor):
+		badRequest(w, "invalid_cursor")
+	case errors.Is(err, auditlogs.ErrInvalidAction):
+		badRequest(w, "invalid_action")
+	case errors.Is(err, auditlogs.ErrInvalidRange):
+		badRequest(w, "from must be before to")
+	case errors.Is(err, auditlogs.ErrTooManyRows):
+		badRequest(w, "too_many_rows")
+	default:
+		log.Printf("ERROR: failed to query audit logs: %v", err)
+		internalError(w)
+	}
+}
+
+// publicUserID は記録された内部 ID を公開ユーザー ID に変換します。同じリクエスト内では cache を使います。
+func (h *Handler) publicUserID(cache map[string]string, uid string) string {
+	if uid == "" {
+		return ""
+	}
+	if userID, ok := cache[uid]; ok {
+		return userID
+	}
+	userID := ""
+	if user, ok := h.users.Get(uid); ok {
+		userID = user.UserID
+	}
+	cache[uid] = userID
+	return userID
+}
+
+func auditLogResponse(item domain.AuditLog, userID string) map[string]any {
+	return map[string]any{
+		"id":        item.ID,
+		"createdAt": item.CreatedAt,
+		"uid":       item.UserID,
+		"userId":    userID,
+		"action":    item.Action,
+		"entity":    item.Entity,
+		"entityId":  item.EntityID,
+		"ip":        item.IP,
+		"userAgent": item.UserAgent,
+		"payload":   item.Payload,
+	}
+}
+
+func auditLogCSVRecord(item domain.AuditLog, userID string) []string {
+	payload, err := json.Marshal(item.Payload)
+	if err != nil {
+		payload = []byte("{}")
+	}
+	record := []string{
+		item.ID,
+		item.CreatedAt.Format(time.RFC3339Nano),
+		item.UserID,
+		userID,
+		item.Action,
+		item.Entity,
+		item.EntityID,
+		item.IP,
+		item.UserAgent,
+		string(payload),
+	}
+	for i, cell := range record {
+		record[i] = csvSafeCell(cell)
+	}
+	return record
+}
+
+// csvSafeCell は表計算ソフトで数式として解釈される文字で始まるセルの先頭に ' を付けます。
+// User-Agent などクライアントが送った値がそのまま入るため、CSV でだけ無害化します（NDJSON はそのまま）。
+func csvSafeCell(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
@@ -8,8 +8,10 @@ import (
 
 	"book_manager/backend/internal/admininvitations"
 	"book_manager/backend/internal/adminusers"
+	"book_manager/backend/internal/auth"
 	"book_manager/backend/internal/config"
-	"book_manager/backend/internal/firebaseauth"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/reports"
 	"book_manager/backend/internal/users"
 	"book_manager/backend/internal/validation"
 )
@@ -24,6 +26,7 @@ func (h *Handler) AuthSignup(w http.ResponseWriter, r *http.Request) {
 		Password    string `json:"password"`
 		UserID      string `json:"userId"`
 		DisplayName string `json:"displayName"`
+		DeviceName  string `json:"deviceName"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
 		badRequest(w, "invalid json")
@@ -66,7 +69,7 @@ func (h *Handler) AuthSignup(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "display_name_too_long")
 		return
 	}
-	if h.firebaseClient == nil {
+	if h.authBackend == nil {
 		internalError(w)
 		return
 	}
@@ -74,43 +77,27 @@ func (h *Handler) AuthSignup(w http.ResponseWriter, r *http.Request) {
 		conflict(w, "user_id_exists")
 		return
 	}
-	if h.adminUsers != nil && h.adminUsers.IsAdmin(normalizedUserID) {
+	if h.isReservedUserID(normalizedUserID) {
 		conflict(w, "user_id_reserved")
 		return
 	}
-	result, err := h.firebaseClient.SignUp(req.Email, req.Password, normalizedUserID)
+	result, err := h.authBackend.SignUp(r.Context(), req.Email, req.Password, normalizedUserID, displayName)
 	if err != nil {
-		switch {
-		case errors.Is(err, firebaseauth.ErrEmailExists):
-			conflict(w, "email_exists")
-		case errors.Is(err, firebaseauth.ErrWeakPassword):
-			badRequest(w, "weak_password")
-		case errors.Is(err, firebaseauth.ErrInvalidEmail):
-			badRequest(w, "invalid_email")
-		case errors.Is(err, firebaseauth.ErrTooManyAttempts):
-			http.Error(w, "too_many_attempts", http.StatusTooManyRequests)
-		default:
-			internalError(w)
-		}
+		writeAuthError(w, err)
 		return
 	}
-	if err := h.firebaseClient.SendEmailVerification(result.IDToken); err != nil {
-		// メール送信に失敗してもサインアップ処理は続行する（ユーザーは後で再送信できる）
-		log.Printf("WARNING: failed to send verification email to %s: %v", req.Email, err)
-	}
-	user, err := h.users.Create(result.LocalID, req.Email, normalizedUserID, displayName)
+	user, err := h.ensureAuthUser(result, normalizedUserID, displayName)
 	if err != nil {
-		// ローカルDB作成失敗時はFirebaseユーザーを削除してロールバック
-		if h.firebaseAdmin != nil {
-			if deleteErr := h.firebaseAdmin.DeleteUser(r.Context(), result.LocalID); deleteErr != nil {
-				log.Printf("CRITICAL: failed to delete firebase user %s during signup rollback: %v", result.LocalID, deleteErr)
-			}
+		// ローカルDB作成失敗時は認証バックエンドのユーザーを削除してロールバック
+		if deleteErr := h.authBackend.DeleteUser(r.Context(), result.UID); deleteErr != nil {
+			log.Printf("CRITICAL: failed to delete auth user %s during signup rollback: %v", result.UID, deleteErr)
 		}
 		internalError(w)
 		return
 	}
+	h.startSession(r, user.ID, result.RefreshToken, req.DeviceName)
 	writeJSON(w, http.StatusOK, map[string]any{
-		"accessToken":  result.IDToken,
+		"accessToken":  result.AccessToken,
 		"refreshToken": result.RefreshToken,
 		"user": map[string]string{
 			"id":          user.ID,
@@ -128,8 +115,9 @@ func (h *Handler) AuthLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var req struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email      string `json:"email"`
+		Password   string `json:"password"`
+		DeviceName string `json:"deviceName"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
 		badRequest(w, "invalid json")
@@ -143,50 +131,62 @@ func (h *Handler) AuthLogin(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "invalid_email")
 		return
 	}
-	if h.firebaseClient == nil {
+	if h.authBackend == nil {
 		internalError(w)
 		return
 	}
-	result, err := h.firebaseClient.Login(req.Email, req.Password)
-	if err != nil {
-		log.Printf("auth login error for email=%s: %v", req.Email, err)
-		if errors.Is(err, firebaseauth.ErrEmailNotFound) {
-			unauthorizedWithMessage(w, "email_not_found")
-			return
-		}
-		if errors.Is(err, firebaseauth.ErrInvalidCredentials) {
-			unauthorizedWithMessage(w, "invalid_password")
-			return
-		}
-		internalError(w)
+	ip := clientIP(r)
+	if err := h.loginThrottle.Check(req.Email, ip); err != nil {
+		tooManyAttempts(w)
 		return
 	}
-	info, err := h.firebaseVerifier.VerifyIDToken(r.Context(), result.IDToken)
+	result, err := h.authBackend.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		log.Printf("WARNING: ID token verification failed after successful login: %v", err)
-		unauthorized(w)
+		log.Printf("auth login error for email=%s: %v", req.Email, err)
+		// 未登録のメールアドレスとパスワードの誤りは区別せずに返す
+		switch {
+		case errors.Is(err, auth.ErrEmailNotFound), errors.Is(err, auth.ErrInvalidCredentials):
+			h.loginThrottle.Fail(req.Email, ip)
+			unauthorizedWithMessage(w, "invalid_credentials")
+		case errors.Is(err, auth.ErrTooManyAttempts):
+			tooManyAttempts(w)
+		case errors.Is(err, auth.ErrInvalidToken):
+			unauthorized(w)
+		default:
+			internalError(w)
+		}
 		return
 	}
-	user, ok := h.users.Get(result.LocalID)
+	h.loginThrottle.Succeed(req.Email)
+	user, ok := h.users.Get(result.UID)
 	if !ok {
 		userID := strings.TrimSpace(result.DisplayName)
 		if userID == "" {
-			userID = result.LocalID // Firebase DisplayNameが空の場合、LocalIDをデフォルトとして使用
+			userID = result.UID // DisplayNameが空の場合、UIDをデフォルトとして使用
 		}
 		// 管理者として予約されているUserIDを一般ユーザーが使用することを防ぐ
-		if h.adminUsers != nil && h.adminUsers.IsAdmin(userID) {
+		if h.isReservedUserID(userID) {
 			conflict(w, "user_id_reserved")
 			return
 		}
-		created, err := h.users.Create(result.LocalID, result.Email, userID, userID)
+		created, err := h.users.Create(result.UID, result.Email, userID, userID)
 		if err != nil {
 			internalError(w)
 			return
 		}
 		user = created
 	}
+	if h.isSuspended(user.ID) {
+		forbidden(w, "account_suspended")
+		return
+	}
+	h.startSession(r, user.ID, result.RefreshToken, req.DeviceName)
+	h.notifySecurityEvent(r, user, reports.SecurityNotice{
+		Event:      reports.SecurityEventNewSession,
+		DeviceName: req.DeviceName,
+	})
 	writeJSON(w, http.StatusOK, map[string]any{
-		"accessToken":  result.IDToken,
+		"accessToken":  result.AccessToken,
 		"refreshToken": result.RefreshToken,
 		"user": map[string]string{
 			"id":          user.ID,
@@ -194,7 +194,7 @@ func (h *Handler) AuthLogin(w http.ResponseWriter, r *http.Request) {
 			"userId":      user.UserID,
 			"displayName": user.DisplayName,
 		},
-		"emailVerified": info.EmailVerified,
+		"emailVerified": result.EmailVerified,
 	})
 }
 
@@ -218,21 +218,31 @@ func (h *Handler) AuthRefresh(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "refreshToken is required")
 		return
 	}
-	if h.firebaseClient == nil {
+	if h.authBackend == nil {
 		internalError(w)
 		return
 	}
-	result, err := h.firebaseClient.Refresh(req.RefreshToken)
+	if !h.checkSession(w, req.RefreshToken) {
+		return
+	}
+	result, err := h.authBackend.Refresh(r.Context(), req.RefreshToken)
 	if err != nil {
-		if errors.Is(err, firebaseauth.ErrInvalidCredentials) {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
 			unauthorized(w)
 			return
 		}
 		internalError(w)
 		return
 	}
+	if h.isSuspended(result.UID) {
+		forbidden(w, "account_suspended")
+		return
+	}
+	if !h.touchSession(w, r, result.UID, req.RefreshToken, result.RefreshToken) {
+		return
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"accessToken":  result.IDToken,
+		"accessToken":  result.AccessToken,
 		"refreshToken": result.RefreshToken,
 	})
 }
@@ -253,29 +263,16 @@ func (h *Handler) AuthLogout(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "refreshToken is required")
 		return
 	}
-	// リフレッシュトークンからユーザーIDを取得
-	if h.firebaseClient == nil {
+	if h.authBackend == nil {
 		internalError(w)
 		return
 	}
-	result, err := h.firebaseClient.Refresh(req.RefreshToken)
-	if err != nil {
-		if errors.Is(err, firebaseauth.ErrInvalidCredentials) {
-			// トークンが既に無効な場合は成功として扱う
-			writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
-			return
-		}
+	if err := h.authBackend.Logout(r.Context(), req.RefreshToken); err != nil {
 		internalError(w)
 		return
 	}
-	// Firebase Admin SDKでリフレッシュトークンを失効
-	// 注: 失効に失敗してもログアウト自体は成功として扱う
-	// 理由: クライアント側でトークンを削除すれば実質的にログアウトとなり、
-	// ユーザー体験を優先する（Admin SDKが利用不可でも動作させる）
-	if h.firebaseAdmin != nil {
-		if err := h.firebaseAdmin.RevokeRefreshTokens(r.Context(), result.LocalID); err != nil {
-			log.Printf("WARNING: failed to revoke refresh tokens for user %s: %v", result.LocalID, err)
-		}
+	if h.sessions != nil {
+		h.sessions.EndByToken(req.RefreshToken)
 	}
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
@@ -296,26 +293,27 @@ func (h *Handler) AuthResendVerify(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "refreshToken is required")
 		return
 	}
-	if h.firebaseClient == nil {
+	if h.authBackend == nil {
 		internalError(w)
 		return
 	}
-	result, err := h.firebaseClient.Refresh(req.RefreshToken)
-	if err != nil {
-		if errors.Is(err, firebaseauth.ErrInvalidCredentials) {
+	if !h.checkSession(w, req.RefreshToken) {
+		return
+	}
+	if err := h.authBackend.ResendVerification(r.Context(), req.RefreshToken); err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
 			unauthorized(w)
 			return
 		}
 		internalError(w)
 		return
 	}
-	if err := h.firebaseClient.SendEmailVerification(result.IDToken); err != nil {
-		internalError(w)
-		return
-	}
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
 
+// AuthUpdateEmail はメールアドレスの変更を受け付けます。
+// 確認済みのアドレスを持つユーザーは新しいアドレスでの確認が必要なため、確認待ちの変更を作成して 202 を返します。
+// 未確認のユーザー（登録時の入力ミスなど）は従来どおりすぐに変更します。
 func (h *Handler) AuthUpdateEmail(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPatch {
 		methodNotAllowed(w, http.MethodPatch)
@@ -329,46 +327,66 @@ func (h *Handler) AuthUpdateEmail(w http.ResponseWriter, r *http.Request) {
 		badRequest(w, "invalid json")
 		return
 	}
-	email := strings.TrimSpace(req.Email)
-	if email == "" {
-		badRequest(w, "email_required")
-		return
-	}
-	if !validation.IsValidEmail(email) {
-		badRequest(w, "invalid_email")
+	email, ok := parseNewEmail(w, req.Email)
+	if !ok {
 		return
 	}
 	if strings.TrimSpace(req.RefreshToken) == "" {
 		badRequest(w, "refreshToken is required")
 		return
 	}
-	if h.firebaseClient == nil {
+	if h.authBackend == nil || h.emailChanges == nil {
 		internalError(w)
 		return
 	}
-	refreshed, err := h.firebaseClient.Refresh(req.RefreshToken)
+	if !h.checkSession(w, req.RefreshToken) {
+		return
+	}
+	refreshed, err := h.authBackend.Refresh(r.Context(), req.RefreshToken)
 	if err != nil {
-		if errors.Is(err, firebaseauth.ErrInvalidCredentials) {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
 			unauthorized(w)
 			return
 		}
 		internalError(w)
 		return
 	}
-	updated, err := h.firebaseClient.UpdateEmail(refreshed.IDToken, email)
+	if !h.touchSession(w, r, refreshed.UID, req.RefreshToken, refreshed.RefreshToken) {
+		return
+	}
+	info, err := h.authBackend.VerifyAccessToken(r.Context(), refreshed.AccessToken)
 	if err != nil {
-		if errors.Is(err, firebaseauth.ErrEmailExists) {
-			conflict(w, "email_exists")
+		unauthorized(w)
+		return
+	}
+	if info.EmailVerified {
+		change, ok := h.requestEmailChange(w, r, refreshed.UID, email)
+		if !ok {
 			return
 		}
-		internalError(w)
+		writeJSON(w, http.StatusAccepted, map[string]any{
+			"accessToken":  refreshed.AccessToken,
+			"refreshToken": refreshed.RefreshToken,
+			"pending":      emailChangeResponse(change),
+		})
 		return
 	}
-	if err := h.firebaseClient.SendEmailVerification(updated.IDToken); err != nil {
-		internalError(w)
+	updated, err := h.authBackend.UpdateEmail(r.Context(), refreshed.RefreshToken, email)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			unauthorized(w)
+		case errors.Is(err, auth.ErrEmailExists):
+			conflict(w, "email_exists")
+		default:
+			internalError(w)
+		}
 		return
 	}
-	user, err := h.users.UpdateProfile(updated.LocalID, nil, &email)
+	if !h.touchSession(w, r, updated.UID, refreshed.RefreshToken, updated.RefreshToken) {
+		return
+	}
+	user, err := h.users.UpdateProfile(updated.UID, nil, &email)
 	if err != nil {
 		if errors.Is(err, users.ErrEmailExists) {
 			conflict(w, "email_exists")
@@ -378,7 +396,7 @@ func (h *Handler) AuthUpdateEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"accessToken":  updated.IDToken,
+		"accessToken":  updated.AccessToken,
 		"refreshToken": updated.RefreshToken,
 		"user": map[string]string{
 			"id":          user.ID,
@@ -395,14 +413,8 @@ func (h *Handler) AuthStatus(w http.ResponseWriter, r *http.Request) {
 		methodNotAllowed(w, http.MethodGet)
 		return
 	}
-	token := validation.BearerToken(r.Header.Get("Authorization"))
-	if token == "" {
-		unauthorized(w)
-		return
-	}
-	info, err := h.firebaseVerifier.VerifyIDToken(r.Context(), token)
-	if err != nil {
-		unauthorized(w)
+	info, ok := h.verifyBearerToken(w, r)
+	if !ok {
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
@@ -424,6 +436,7 @@ func (h *Handler) AuthSignupAdmin(w http.ResponseWriter, r *http.Request) {
 		Email       string `json:"email"`
 		Password    string `json:"password"`
 		DisplayName string `json:"displayName"`
+		DeviceName  string `json:"deviceName"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
 		badRequest(w, "invalid json")
@@ -485,19 +498,19 @@ func (h *Handler) AuthSignupAdmin(w http.ResponseWriter, r *http.Request) {
 		displayName = invitation.UserID
 	}
 
-	if h.firebaseClient == nil {
+	if h.authBackend == nil {
 		internalError(w)
 		return
 	}
 
 	// ロールバック用の状態管理
 	var (
-		firebaseUserCreated bool
-		localUserCreated    bool
-		invitationMarked    bool
-		adminRoleAdded      bool
-		success             bool
-		firebaseUID         string
+		authUserCreated  bool
+		localUserCreated bool
+		invitationMarked bool
+		adminRoleAdded   bool
+		success          bool
+		authUID          string
 	)
 
 	// エラー時のロールバック処理
@@ -516,43 +529,38 @@ func (h *Handler) AuthSignupAdmin(w http.ResponseWriter, r *http.Request) {
 				log.Printf("CRITICAL: rollback failed - could not unmark invitation %s: %v", invitation.ID, unmarkErr)
 			}
 		}
-		if localUserCreated && firebaseUID != "" {
-			if !h.users.Delete(firebaseUID) {
-				log.Printf("CRITICAL: rollback failed - could not delete local user %s", firebaseUID)
+		if localUserCreated && authUID != "" {
+			if !h.users.Delete(authUID) {
+				log.Printf("CRITICAL: rollback failed - could not delete local user %s", authUID)
 			}
 		}
-		if firebaseUserCreated && h.firebaseAdmin != nil && firebaseUID != "" {
-			if deleteErr := h.firebaseAdmin.DeleteUser(r.Context(), firebaseUID); deleteErr != nil {
-				log.Printf("CRITICAL: rollback failed - could not delete firebase user %s: %v", firebaseUID, deleteErr)
+		if authUserCreated && authUID != "" {
+			if deleteErr := h.authBackend.DeleteUser(r.Context(), authUID); deleteErr != nil {
+				log.Printf("CRITICAL: rollback failed - could not delete auth user %s: %v", authUID, deleteErr)
 			}
 		}
 	}()
 
-	result, signupErr := h.firebaseClient.SignUp(email, password, invitation.UserID)
+	result, signupErr := h.authBackend.SignUp(r.Context(), email, password, invitation.UserID, displayName)
 	if signupErr != nil {
-		if errors.Is(signupErr, firebaseauth.ErrEmailExists) {
+		if errors.Is(signupErr, auth.ErrEmailExists) {
 			conflict(w, "email_exists")
 			return
 		}
 		internalError(w)
 		return
 	}
-	firebaseUserCreated = true
-	firebaseUID = result.LocalID
-
-	if verifyErr := h.firebaseClient.SendEmailVerification(result.IDToken); verifyErr != nil {
-		log.Printf("WARNING: failed to send email verification: %v", verifyErr)
-		// メール送信失敗は継続（ユーザーは後で再送信できる）
-	}
+	authUserCreated = true
+	authUID = result.UID
 
-	user, createErr := h.users.Create(result.LocalID, email, invitation.UserID, displayName)
+	user, createErr := h.ensureAuthUser(result, invitation.UserID, displayName)
 	if createErr != nil {
 		internalError(w)
 		return
 	}
 	localUserCreated = true
 
-	if markErr := h.adminInvitations.MarkUsed(invitation.ID, result.LocalID); markErr != nil {
+	if markErr := h.adminInvitations.MarkUsed(invitation.ID, result.UID); markErr != nil {
 		log.Printf("ERROR: failed to mark invitation as used: %v", markErr)
 		internalError(w)
 		return
@@ -560,20 +568,26 @@ func (h *Handler) AuthSignupAdmin(w http.ResponseWriter, r *http.Request) {
 	invitationMarked = true
 
 	if h.adminUsers != nil {
-		if addErr := h.adminUsers.Add(invitation.UserID, invitation.CreatedBy); addErr != nil {
-			if !errors.Is(addErr, adminusers.ErrAlreadyAdmin) {
-				log.Printf("ERROR: failed to add admin user: %v", addErr)
-				internalError(w)
-				return
-			}
-			// ErrAlreadyAdmin の場合は既に管理者なので問題なし
+		addErr := h.adminUsers.Add(invitation.UserID, adminusers.RoleAdmin, invitation.CreatedBy)
+		switch {
+		case addErr == nil:
+			adminRoleAdded = true
+		case errors.Is(addErr, adminusers.ErrAlreadyAdmin):
+			// 既に管理者の場合は元からのロールなので、ロールバックで外さない
+		default:
+			log.Printf("ERROR: failed to add admin user: %v", addErr)
+			internalError(w)
+			return
 		}
-		adminRoleAdded = true
 	}
 
 	success = true
+	h.startSession(r, user.ID, result.RefreshToken, req.DeviceName)
+	if adminRoleAdded {
+		h.notifyRoleGranted(r, invitation.UserID, adminusers.RoleAdmin)
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"accessToken":  result.IDToken,
+		"accessToken":  result.AccessToken,
 		"refreshToken": result.RefreshToken,
 		"user": map[string]string{
 			"id":          user.ID,
@@ -585,3 +599,75 @@ func (h *Handler) AuthSignupAdmin(w http.ResponseWriter, r *http.Request) {
 		"isAdmin":       true,
 	})
 }
+
+// AuthVerifyEmail はローカル認証モードで確認メールのトークンを検証し、メールアドレスを確認済みにします。
+// Firebase モードでは確認リンクを Firebase が処理するため 404 を返します。
+func (h *Handler) AuthVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		badRequest(w, "token_required")
+		return
+	}
+	if h.authBackend == nil {
+		internalError(w)
+		return
+	}
+	if err := h.authBackend.VerifyEmail(r.Context(), token); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUnsupported):
+			notFoundWithMessage(w, "not_supported")
+		case errors.Is(err, auth.ErrInvalidToken):
+			badRequest(w, "invalid_token")
+		default:
+			internalError(w)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// isReservedUserID は管理機能のロールを持つ UserID と、招待の受諾待ちの UserID を予約済みとして扱います。
+func (h *Handler) isReservedUserID(userID string) bool {
+	if h.adminUsers != nil && h.adminUsers.HasRole(userID) {
+		return true
+	}
+	return h.adminInvitations != nil && h.adminInvitations.IsPending(userID)
+}
+
+// ensureAuthUser は認証バックエンドで作成したユーザーに対応するローカルユーザーを返します。
+// ローカル認証モードではバックエンドがユーザーを作成済みなので、その場合は作成をスキップします。
+func (h *Handler) ensureAuthUser(session auth.Session, userID, displayName string) (domain.User, error) {
+	if user, ok := h.users.Get(session.UID); ok {
+		return user, nil
+	}
+	return h.users.Create(session.UID, session.Email, userID, displayName)
+}
+
+// writeAuthError はサインアップ時の認証バックエンドのエラーをレスポンスに変換します。
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrEmailExists):
+		conflict(w, "email_exists")
+	case errors.Is(err, auth.ErrUserIDExists):
+		conflict(w, "user_id_exists")
+	case errors.Is(err, auth.ErrWeakPassword):
+		badRequest(w, "weak_password")
+	case errors.Is(err, auth.ErrInvalidEmail):
+		badRequest(w, "invalid_email")
+	case errors.Is(err, auth.ErrTooManyAttempts):
+		tooManyAttempts(w)
+	default:
+		internalError(w)
+	}
+}
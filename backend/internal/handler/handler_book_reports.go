@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"book_manager/backend/internal/bookreports"
+	"book_manager/backend/internal/domain"
+)
+
+// AdminBookReports はユーザーから届いた書誌報告を新しい順に返します。
+// GET /admin/book-reports?status=open|resolved|rejected
+func (h *Handler) AdminBookReports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	items, err := h.bookReports.List(strings.TrimSpace(r.URL.Query().Get("status")))
+	if err != nil {
+		if errors.Is(err, bookreports.ErrInvalidStatus) {
+			badRequest(w, "invalid_status")
+			return
+		}
+		internalError(w)
+		return
+	}
+	userIDs := map[string]string{}
+	out := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		out = append(out, h.bookReportResponse(item, userIDs))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": out})
+}
+
+// AdminBookReportsByID は書誌報告を対応済み・却下にします。
+// PATCH /admin/book-reports/{id} {status: resolved|rejected}
+// 書誌そのものの修正は既存の書籍編集 API で行い、ここでは報告の状態だけを記録します。
+func (h *Handler) AdminBookReportsByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID("/admin/book-reports/", r.URL.Path)
+	if !ok {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodPatch {
+		methodNotAllowed(w, http.MethodPatch)
+		return
+	}
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	report, err := h.bookReports.Handle(id, strings.TrimSpace(req.Status), userIDFromRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, bookreports.ErrInvalidStatus):
+			badRequest(w, "invalid_status")
+		case errors.Is(err, bookreports.ErrReportNotFound):
+			notFound(w)
+		case errors.Is(err, bookreports.ErrAlreadyHandled):
+			conflict(w, "report_already_handled")
+		default:
+			log.Printf("ERROR: failed to handle book report %s: %v", id, err)
+			internalError(w)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, h.bookReportResponse(report, map[string]string{}))
+}
+
+func (h *Handler) bookReportResponse(report domain.BookReport, userIDs map[string]string) map[string]any {
+	response := map[string]any{
+		"id":         report.ID,
+		"userId":     h.publicUserID(userIDs, report.UserID),
+		"bookId":     report.BookID,
+		"suggestion": report.Suggestion,
+		"note":       report.Note,
+		"status":     report.Status,
+		"handledBy":  h.publicUserID(userIDs, report.HandledBy),
+		"handledAt":  report.HandledAt,
+		"createdAt":  report.CreatedAt,
+	}
+	if book, ok := h.books.Get(report.BookID); ok {
+		response["book"] = map[string]any{
+			"title":     book.Title,
+			"isbn13":    book.ISBN13,
+			"authors":   book.Authors,
+			"publisher": book.Publisher,
+		}
+	}
+	return response
+}
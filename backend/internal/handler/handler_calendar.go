@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/calendar"
+)
+
+// CalendarFeed はトークン付き URL で発売予定と購入予定日を iCalendar 形式で返します。
+// Firebase 認証は不要で、トークンがユーザーの識別を兼ねます。
+func (h *Handler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	name, ok := pathID("/calendar/", r.URL.Path)
+	if !ok || !strings.HasSuffix(name, ".ics") {
+		notFound(w)
+		return
+	}
+	userID, err := h.calendar.Resolve(strings.TrimSuffix(name, ".ics"))
+	if err != nil {
+		if errors.Is(err, calendar.ErrTokenNotFound) {
+			notFound(w)
+			return
+		}
+		log.Printf("calendar resolve error: %v", err)
+		internalError(w)
+		return
+	}
+	seriesMap := make(map[string]string)
+	for _, series := range h.series.List() {
+		seriesMap[series.ID] = series.Name
+	}
+	events := make([]calendar.Event, 0)
+	for _, release := range h.releases.ListUpcoming(userID) {
+		summary := release.Title
+		if summary == "" {
+			summary = strings.TrimSpace(seriesMap[release.SeriesID] + " " + release.VolumeLabel)
+		}
+		events = append(events, calendar.Event{
+			UID:         "release-" + release.ID + "@book_manager",
+			Date:        release.ReleaseDate,
+			Summary:     "発売: " + summary,
+			Description: strings.TrimSpace(strings.Join(release.Authors, ", ") + "\n" + release.Publisher),
+		})
+	}
+	for _, item := range h.nextToBuy.ListByUser(userID) {
+		if item.TargetDate == "" {
+			continue
+		}
+		summary := item.Title
+		if item.VolumeNumber > 0 {
+			summary = fmt.Sprintf("%s %d巻", summary, item.VolumeNumber)
+		}
+		events = append(events, calendar.Event{
+			UID:         "next-to-buy-" + item.ID + "@book_manager",
+			Date:        item.TargetDate,
+			Summary:     "購入予定: " + summary,
+			Description: item.Note,
+		})
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(calendar.Render("BookManager 発売予定", events, time.Now())))
+}
+
+// UsersMeCalendar は購読用トークンの確認・発行・無効化を扱います。
+// トークンはフィードの認証情報のため、個人用アクセストークンからは扱えません。
+func (h *Handler) UsersMeCalendar(w http.ResponseWriter, r *http.Request) {
+	if rejectAPIToken(w, r, "api tokens cannot manage calendar tokens") {
+		return
+	}
+	userID := userIDFromRequest(r)
+	switch r.Method {
+	case http.MethodGet:
+		token, ok := h.calendar.Get(userID)
+		if !ok {
+			writeJSON(w, http.StatusOK, map[string]any{
+				"enabled": false,
+			})
+			return
+		}
+		// トークンの平文は保存していないため、URL は発行時にだけ返す
+		writeJSON(w, http.StatusOK, map[string]any{
+			"enabled":   true,
+			"createdAt": token.CreatedAt,
+		})
+	case http.MethodPost:
+		token, raw, err := h.calendar.Issue(userID)
+		if err != nil {
+			log.Printf("calendar token issue error: %v", err)
+			internalError(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, calendarTokenResponse(r, raw, token.CreatedAt))
+	case http.MethodDelete:
+		if !h.calendar.Revoke(userID) {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost, http.MethodDelete)
+	}
+}
+
+func calendarTokenResponse(r *http.Request, token string, createdAt time.Time) map[string]any {
+	path := "/calendar/" + token + ".ics"
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return map[string]any{
+		"enabled":   true,
+		"path":      path,
+		"url":       scheme + "://" + r.Host + path,
+		"createdAt": createdAt,
+	}
+}
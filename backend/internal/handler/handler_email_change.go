@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/auth"
+	"book_manager/backend/internal/config"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/emailchange"
+	"book_manager/backend/internal/reports"
+	"book_manager/backend/internal/users"
+	"book_manager/backend/internal/validation"
+)
+
+// UsersMeEmailChange は確認待ちのメールアドレス変更を扱います。
+// GET で確認待ちの変更を返し、POST で変更を申請し、DELETE で取り消します。
+func (h *Handler) UsersMeEmailChange(w http.ResponseWriter, r *http.Request) {
+	if h.emailChanges == nil {
+		internalError(w)
+		return
+	}
+	userID := userIDFromRequest(r)
+	switch r.Method {
+	case http.MethodGet:
+		change, ok := h.emailChanges.Pending(userID)
+		if !ok {
+			writeJSON(w, http.StatusOK, map[string]any{"pending": nil})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"pending": emailChangeResponse(change)})
+	case http.MethodPost:
+		if !h.allowEmailChange(w, r) {
+			return
+		}
+		var req struct {
+			Email string `json:"email"`
+		}
+		if err := decodeJSON(r, &req); err != nil {
+			badRequest(w, "invalid json")
+			return
+		}
+		email, ok := parseNewEmail(w, req.Email)
+		if !ok {
+			return
+		}
+		change, ok := h.requestEmailChange(w, r, userID, email)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]any{"pending": emailChangeResponse(change)})
+	case http.MethodDelete:
+		if !h.allowEmailChange(w, r) {
+			return
+		}
+		if err := h.emailChanges.Cancel(userID); err != nil {
+			if errors.Is(err, emailchange.ErrNotFound) {
+				notFound(w)
+				return
+			}
+			internalError(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost, http.MethodDelete)
+	}
+}
+
+// AuthConfirmEmailChange は新しいアドレスに送った確認リンクのトークンで変更を確定します。
+// リンクは別の端末で開かれることがあるため、ログインは不要です。
+func (h *Handler) AuthConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		badRequest(w, "token_required")
+		return
+	}
+	if h.authBackend == nil || h.emailChanges == nil {
+		internalError(w)
+		return
+	}
+	var updated domain.User
+	change, err := h.emailChanges.Confirm(token, func(change domain.EmailChange) error {
+		if err := h.authBackend.SetVerifiedEmail(r.Context(), change.UserID, change.NewEmail); err != nil {
+			return err
+		}
+		user, err := h.users.UpdateProfile(change.UserID, nil, &change.NewEmail)
+		if err != nil {
+			// 認証バックエンドだけが新しいアドレスになると、ログインと表示のアドレスが食い違うため元に戻す
+			if restoreErr := h.authBackend.SetVerifiedEmail(r.Context(), change.UserID, change.OldEmail); restoreErr != nil {
+				log.Printf("CRITICAL: rollback failed - could not restore email for %s: %v", change.UserID, restoreErr)
+			}
+			return err
+		}
+		updated = user
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, emailchange.ErrInvalidToken):
+			badRequest(w, "invalid_token")
+		case errors.Is(err, emailchange.ErrEmailExists),
+			errors.Is(err, auth.ErrEmailExists),
+			errors.Is(err, users.ErrEmailExists):
+			conflict(w, "email_exists")
+		default:
+			log.Printf("ERROR: failed to confirm email change: %v", err)
+			internalError(w)
+		}
+		return
+	}
+	if h.reports != nil {
+		h.reports.SendSecurityNotice(reports.SecurityNotice{
+			To:         change.OldEmail,
+			UserID:     updated.UserID,
+			Event:      reports.SecurityEventEmailChanged,
+			OccurredAt: *change.ConfirmedAt,
+			NewEmail:   change.NewEmail,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":    true,
+		"email": updated.Email,
+	})
+}
+
+// AuthChangePassword は現在のパスワードを確認してからパスワードを変更します。
+// ほかの端末のログインはすべて失効し、呼び出し元には新しいトークンを返します。
+func (h *Handler) AuthChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req struct {
+		RefreshToken    string `json:"refreshToken"`
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+		DeviceName      string `json:"deviceName"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	if strings.TrimSpace(req.RefreshToken) == "" {
+		badRequest(w, "refreshToken is required")
+		return
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		badRequest(w, "password_required")
+		return
+	}
+	if len(req.NewPassword) < config.PasswordMinLength {
+		badRequest(w, "weak_password")
+		return
+	}
+	if h.authBackend == nil {
+		internalError(w)
+		return
+	}
+	if !h.checkSession(w, req.RefreshToken) {
+		return
+	}
+	result, err := h.authBackend.ChangePassword(r.Context(), req.RefreshToken, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			unauthorizedWithMessage(w, "invalid_password")
+		case errors.Is(err, auth.ErrInvalidToken):
+			unauthorized(w)
+		case errors.Is(err, auth.ErrWeakPassword):
+			badRequest(w, "weak_password")
+		case errors.Is(err, auth.ErrTooManyAttempts):
+			tooManyAttempts(w)
+		default:
+			internalError(w)
+		}
+		return
+	}
+	if h.sessions != nil {
+		h.sessions.RevokeAll(result.UID)
+	}
+	h.startSession(r, result.UID, result.RefreshToken, req.DeviceName)
+	if user, ok := h.users.Get(result.UID); ok {
+		h.notifySecurityEvent(r, user, reports.SecurityNotice{Event: reports.SecurityEventPasswordChanged})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"accessToken":  result.AccessToken,
+		"refreshToken": result.RefreshToken,
+	})
+}
+
+// requestEmailChange は確認待ちの変更を作成し、新しいアドレスに確認リンク、現在のアドレスに通知を送ります。
+func (h *Handler) requestEmailChange(w http.ResponseWriter, r *http.Request, userID, email string) (domain.EmailChange, bool) {
+	user, ok := h.users.Get(userID)
+	if !ok {
+		notFound(w)
+		return domain.EmailChange{}, false
+	}
+	change, token, err := h.emailChanges.Request(user.ID, user.Email, email)
+	if err != nil {
+		switch {
+		case errors.Is(err, emailchange.ErrSameEmail):
+			badRequest(w, "email_unchanged")
+		case errors.Is(err, emailchange.ErrEmailExists):
+			conflict(w, "email_exists")
+		default:
+			internalError(w)
+		}
+		return domain.EmailChange{}, false
+	}
+	if h.reports != nil {
+		h.reports.SendEmailChangeConfirmation(reports.EmailChangeConfirmationEmail{
+			To:        change.NewEmail,
+			UserID:    user.UserID,
+			OldEmail:  change.OldEmail,
+			Token:     token,
+			ExpiresAt: change.ExpiresAt,
+		})
+	}
+	h.notifySecurityEvent(r, user, reports.SecurityNotice{
+		Event:    reports.SecurityEventEmailChangeRequested,
+		NewEmail: change.NewEmail,
+	})
+	return change, true
+}
+
+// allowEmailChange は個人用アクセストークンからのメールアドレス変更を拒否します。
+// 漏えいしたトークンでアカウントを乗っ取られないよう、ログインセッションからのみ許可します。
+func (h *Handler) allowEmailChange(w http.ResponseWriter, r *http.Request) bool {
+	return !rejectAPIToken(w, r, "api tokens cannot change email")
+}
+
+// notifySecurityEvent はユーザーの現在のメールアドレスにセキュリティ通知を送ります。
+func (h *Handler) notifySecurityEvent(r *http.Request, user domain.User, notice reports.SecurityNotice) {
+	if h.reports == nil {
+		return
+	}
+	notice.To = user.Email
+	notice.UserID = user.UserID
+	if notice.OccurredAt.IsZero() {
+		notice.OccurredAt = time.Now()
+	}
+	if notice.IP == "" {
+		notice.IP = clientIP(r)
+	}
+	if notice.UserAgent == "" {
+		notice.UserAgent = r.UserAgent()
+	}
+	h.reports.SendSecurityNotice(notice)
+}
+
+func parseNewEmail(w http.ResponseWriter, value string) (string, bool) {
+	email := strings.TrimSpace(value)
+	if email == "" {
+		badRequest(w, "email_required")
+		return "", false
+	}
+	if !validation.IsValidEmail(email) {
+		badRequest(w, "invalid_email")
+		return "", false
+	}
+	return email, true
+}
+
+func emailChangeResponse(change domain.EmailChange) map[string]any {
+	return map[string]any{
+		"email":     change.NewEmail,
+		"createdAt": change.CreatedAt,
+		"expiresAt": change.ExpiresAt,
+	}
+}
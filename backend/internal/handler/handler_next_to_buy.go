@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/books"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/isbn"
+	"book_manager/backend/internal/nexttobuy"
+	"book_manager/backend/internal/userbooks"
+)
+
+// nextToBuyPurchase は「次に買う」項目を購入済みにします。
+// 書籍（ISBN 指定時は書誌を取得）と所蔵を作成し、項目を削除するまでを 1 回の呼び出しで行います。
+// 途中で失敗した場合は作成した所蔵と書籍を削除し、項目は残したままエラーを返します。
+func (h *Handler) nextToBuyPurchase(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	userID := userIDFromRequest(r)
+	item, ok := h.nextToBuy.Get(id)
+	if !ok || item.UserID != userID {
+		notFound(w)
+		return
+	}
+	var req struct {
+		ISBN13        string  `json:"isbn13"`
+		AcquiredAt    string  `json:"acquiredAt"`
+		PurchasePrice *int    `json:"purchasePrice"`
+		Currency      *string `json:"currency"`
+		Store         *string `json:"store"`
+	}
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(w, "invalid json")
+		return
+	}
+	normalizePurchaseFields(req.Currency, req.Store)
+	if err := validatePurchaseFields(req.PurchasePrice, req.Currency); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	isbn13 := ""
+	if strings.TrimSpace(req.ISBN13) != "" {
+		isbn13 = normalizeISBN13(req.ISBN13)
+		if len(isbn13) != 13 {
+			badRequest(w, "isbn13 must be 13 digits")
+			return
+		}
+	}
+	acquiredAt := strings.TrimSpace(req.AcquiredAt)
+	if acquiredAt == "" {
+		acquiredAt = time.Now().Format("2006-01-02")
+	} else if !isISODate(acquiredAt) {
+		badRequest(w, "acquiredAt must be YYYY-MM-DD")
+		return
+	}
+
+	// ロールバック用の状態管理
+	var (
+		bookCreated     bool
+		userBookCreated bool
+		success         bool
+		userBook        domain.UserBook
+	)
+	book, bookCreated, err := h.purchasedBook(userID, item, isbn13)
+	if err != nil {
+		log.Printf("next-to-buy purchase: book error: id=%s err=%v", item.ID, err)
+		internalError(w)
+		return
+	}
+
+	// エラー時のロールバック処理
+	defer func() {
+		if success {
+			return
+		}
+		// 逆順でロールバック
+		if userBookCreated && !h.userBooks.Delete(userBook.ID) {
+			log.Printf("CRITICAL: next-to-buy purchase rollback failed - could not delete user book %s", userBook.ID)
+		}
+		if bookCreated && !h.books.Delete(book.ID) {
+			log.Printf("CRITICAL: next-to-buy purchase rollback failed - could not delete book %s", book.ID)
+		}
+	}()
+
+	userBook, err = h.userBooks.Create(userID, book.ID, "", acquiredAt)
+	if err != nil {
+		if errors.Is(err, userbooks.ErrUserBookExists) {
+			conflict(w, "book already owned")
+			return
+		}
+		log.Printf("next-to-buy purchase: user book error: id=%s err=%v", item.ID, err)
+		internalError(w)
+		return
+	}
+	userBookCreated = true
+	// 価格・店舗は指定がなければ「次に買う本」の予定価格と店舗を引き継ぐ
+	input := userbooks.UpdateInput{
+		PurchasePrice: req.PurchasePrice,
+		Currency:      req.Currency,
+		Store:         req.Store,
+	}
+	if input.PurchasePrice == nil && item.ExpectedPrice > 0 {
+		price := item.ExpectedPrice
+		input.PurchasePrice = &price
+	}
+	if input.Currency == nil && item.Currency != "" {
+		currency := item.Currency
+		input.Currency = &currency
+	}
+	if input.Store == nil && item.Store != "" {
+		store := item.Store
+		input.Store = &store
+	}
+	seriesName := isbn.NormalizeSeriesName(item.SeriesName)
+	if seriesName == "" {
+		seriesName = book.SeriesName
+	}
+	if seriesName != "" {
+		if series, err := h.series.Ensure(seriesName); err == nil {
+			seriesID := series.ID
+			source := "manual"
+			input.SeriesID = &seriesID
+			input.SeriesSource = &source
+		}
+	}
+	if item.VolumeNumber > 0 {
+		volume := item.VolumeNumber
+		input.VolumeNumber = &volume
+	}
+	if input.SeriesID != nil || input.VolumeNumber != nil || input.PurchasePrice != nil || input.Currency != nil || input.Store != nil {
+		updated, ok := h.userBooks.Update(userBook.ID, input)
+		if !ok {
+			log.Printf("next-to-buy purchase: user book update failed: id=%s userBookId=%s", item.ID, userBook.ID)
+			internalError(w)
+			return
+		}
+		userBook = updated
+	}
+	if !h.nextToBuy.Delete(item.ID) {
+		log.Printf("next-to-buy purchase: delete failed: id=%s", item.ID)
+		internalError(w)
+		return
+	}
+	success = true
+	writeJSON(w, http.StatusOK, map[string]any{
+		"book":     book,
+		"userBook": userBook,
+	})
+}
+
+// purchasedBook は購入した本の書籍レコードを返します。既存の書籍があればそれを使い、
+// なければ ISBN から書誌を取得（見つからなければ項目の内容から手入力扱い）して作成します。
+// 2 番目の戻り値は、この呼び出しで書籍を作成したかどうかです。
+func (h *Handler) purchasedBook(userID string, item domain.NextToBuyManual, isbn13 string) (domain.Book, bool, error) {
+	book := domain.Book{
+		ISBN13: isbn13,
+		Title:  strings.TrimSpace(item.Title),
+		Source: "manual",
+	}
+	if isbn13 != "" {
+		if existing, ok := h.books.FindByISBN(isbn13); ok {
+			return existing, false, nil
+		}
+		fetched, _, err := h.isbn.Lookup(isbn13)
+		switch {
+		case err == nil:
+			book = fetched
+			book.OriginalTitle = fetched.Title
+		case errors.Is(err, isbn.ErrNotFound):
+		default:
+			return domain.Book{}, false, err
+		}
+	}
+	if cleaned := isbn.NormalizeTitle(book.Title); cleaned != "" {
+		book.Title = cleaned
+	}
+	if book.SeriesName == "" {
+		book.SeriesName = isbn.NormalizeSeriesName(item.SeriesName)
+	}
+	book.UserID = userID
+	created, err := h.books.Create(book)
+	if err != nil {
+		if errors.Is(err, books.ErrBookExists) && book.ISBN13 != "" {
+			if existing, ok := h.books.FindByISBN(book.ISBN13); ok {
+				return existing, false, nil
+			}
+		}
+		return domain.Book{}, false, err
+	}
+	return created, true, nil
+}
+
+func validateNextToBuyFields(priority, expectedPrice *int, currency, storeURL *string) error {
+	if priority != nil && (*priority < 0 || *priority > nexttobuy.MaxPriority) {
+		return fmt.Errorf("priority must be between 0 and %d", nexttobuy.MaxPriority)
+	}
+	if expectedPrice != nil && *expectedPrice < 0 {
+		return errors.New("expectedPrice must be 0 or positive")
+	}
+	if currency != nil && *currency != "" && !isCurrencyCode(*currency) {
+		return errors.New("currency must be a 3-letter code")
+	}
+	if storeURL != nil && *storeURL != "" {
+		parsed, err := url.Parse(*storeURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.New("storeUrl must be an http(s) URL")
+		}
+	}
+	return nil
+}
+
+func isCurrencyCode(value string) bool {
+	if len(value) != 3 {
+		return false
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
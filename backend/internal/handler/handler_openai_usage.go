@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AdminAIMetrics は起動後の AI 呼び出しの成功数・再試行数・失敗の分類ごとの件数と、サーキットブレーカーの状態を返します。
+func (h *Handler) AdminAIMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"metrics": h.aiMetrics.Snapshot(),
+		"circuit": h.aiBreaker.State(),
+	})
+}
+
+// AdminOpenAIUsage は期間内の OpenAI 使用量と推定コストをキー・ユーザー・モデルごとに返します。
+// from / to は YYYY-MM-DD（to を含む）で、省略時は当月 1 日から現在までです。
+func (h *Handler) AdminOpenAIUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := now
+	if value := strings.TrimSpace(r.URL.Query().Get("from")); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			badRequest(w, "from must be YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+	if value := strings.TrimSpace(r.URL.Query().Get("to")); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			badRequest(w, "to must be YYYY-MM-DD")
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		badRequest(w, "from must be before to")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.aiUsage.Summary(from, to))
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/prompts"
+	"book_manager/backend/internal/repository"
+)
+
+// AdminPrompts はシリーズ推定プロンプトのバージョン一覧の取得と、新しいバージョンの登録を行います。
+func (h *Handler) AdminPrompts(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	switch r.Method {
+	case http.MethodGet:
+		current := h.prompts.Current()
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items":       h.prompts.List(),
+			"currentHash": ai.PromptHash(current),
+			"fallback":    h.prompts.Fallback(),
+		})
+	case http.MethodPost:
+		var req struct {
+			Content  string `json:"content"`
+			Note     string `json:"note"`
+			Activate bool   `json:"activate"`
+		}
+		if err := decodeJSON(r, &req); err != nil {
+			badRequest(w, "invalid json")
+			return
+		}
+		item, err := h.prompts.Create(req.Content, req.Note, userID, req.Activate)
+		if err != nil {
+			if errors.Is(err, prompts.ErrEmptyPrompt) {
+				badRequest(w, "content is required")
+				return
+			}
+			if errors.Is(err, repository.ErrPromptVersionExists) {
+				conflict(w, "prompt_version_conflict")
+				return
+			}
+			internalError(w)
+			return
+		}
+		writeJSON(w, http.StatusCreated, item)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// AdminPromptsByID はバージョンの取得と、POST /admin/prompts/{id}/activate による切り替えを行います。
+// 切り替えは再起動なしで反映され、推定キャッシュもプロンプトのハッシュが変わるため再推定されます。
+func (h *Handler) AdminPromptsByID(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathIDWithAction("/admin/prompts/", "/activate", r.URL.Path); ok {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		item, ok := h.prompts.Activate(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+		return
+	}
+	id, ok := pathID("/admin/prompts/", r.URL.Path)
+	if !ok {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	item, ok := h.prompts.Get(id)
+	if !ok {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"book_manager/backend/internal/ai"
+)
+
+const (
+	defaultReadingSuggestions = 10
+	maxReadingSuggestions     = 20
+)
+
+// UsersMeReadingSuggestions は蔵書をもとに LLM が提案した「次に読む本」を、書誌検索で実在を確認して返します。
+// 所蔵済みの本は除外されます。キーの失効やレート制限で失敗した場合は次のキーで再試行します。
+func (h *Handler) UsersMeReadingSuggestions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	limit := defaultReadingSuggestions
+	if value := strings.TrimSpace(r.URL.Query().Get("limit")); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxReadingSuggestions {
+			badRequest(w, "limit must be between 1 and 20")
+			return
+		}
+		limit = parsed
+	}
+	userID := userIDFromRequest(r)
+	if !h.aiUsage.AllowUser(userID) {
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{
+			"error":   "too many requests",
+			"message": "daily ai quota exceeded",
+		})
+		return
+	}
+	settings := h.users.GetSettings(userID)
+	model := h.openAIDefaultModel
+	if settings.OpenAIModel != "" {
+		model = settings.OpenAIModel
+	}
+	keys := h.openAIKeyCandidates()
+	if len(keys) == 0 {
+		keys = []openAIKeyCandidate{{}}
+	}
+	available := false
+	var lastErr error
+	for _, key := range keys {
+		client, ok := h.openAIClient(userID, key, model)
+		if !ok {
+			continue
+		}
+		available = true
+		result, err := h.readNext.Suggest(r.Context(), userID, client, limit)
+		h.reportOpenAIKeyResult(key.id, err)
+		if err == nil {
+			writeJSON(w, http.StatusOK, result)
+			return
+		}
+		lastErr = err
+		if !ai.IsKeyError(err) {
+			break
+		}
+	}
+	if !available {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error":   "service unavailable",
+			"message": "ai is not configured",
+		})
+		return
+	}
+	log.Printf("reading suggestions error: user=%s err=%v", userID, lastErr)
+	writeJSON(w, http.StatusBadGateway, map[string]string{
+		"error":   "bad gateway",
+		"message": "ai request failed",
+	})
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+)
+
+func (h *Handler) ReleasesUpcoming(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	userID := userIDFromRequest(r)
+	seriesMap := make(map[string]string)
+	for _, series := range h.series.List() {
+		seriesMap[series.ID] = series.Name
+	}
+	upcoming := h.releases.ListUpcoming(userID)
+	items := make([]map[string]any, 0, len(upcoming))
+	for _, release := range upcoming {
+		items = append(items, map[string]any{
+			"id":           release.ID,
+			"seriesId":     release.SeriesID,
+			"seriesName":   seriesMap[release.SeriesID],
+			"isbn13":       release.ISBN13,
+			"title":        release.Title,
+			"authors":      release.Authors,
+			"publisher":    release.Publisher,
+			"volumeLabel":  release.VolumeLabel,
+			"volumeNumber": release.VolumeNumber,
+			"releaseDate":  release.ReleaseDate,
+			"thumbnailUrl": release.ThumbnailURL,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items": items,
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"book_manager/backend/internal/adminusers"
+	"book_manager/backend/internal/reports"
+)
+
+// RequirePermission はロールが権限を含むユーザーのリクエストだけを next に渡すミドルウェアです。
+// 管理 API のルーティングで使い、各ハンドラーでは権限を確認しません。
+// 個人用アクセストークンは管理者のものでも管理 API には使えません。
+func (h *Handler) RequirePermission(perm adminusers.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rejectAPIToken(w, r, "api tokens cannot access admin api") {
+			return
+		}
+		if !h.hasPermission(userIDFromRequest(r), perm) {
+			forbidden(w, "permission_required: "+string(perm))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// AdminRoles は定義済みのロールと権限の一覧を返します。
+func (h *Handler) AdminRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	roles := adminusers.Roles()
+	items := make([]map[string]any, 0, len(roles))
+	for _, name := range adminusers.RoleNames() {
+		items = append(items, map[string]any{
+			"role":        name,
+			"permissions": roles[name],
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// updateAdminUserRole はユーザーのロールを変更します（PATCH /admin/users/{userId}）。
+func (h *Handler) updateAdminUserRole(w http.ResponseWriter, r *http.Request) {
+	userID, _ := pathID("/admin/users/", r.URL.Path)
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	previous, _ := h.adminUsers.RoleOf(userID)
+	role := strings.TrimSpace(req.Role)
+	if err := h.adminUsers.SetRole(userID, role); err != nil {
+		switch {
+		case errors.Is(err, adminusers.ErrInvalidRole):
+			badRequest(w, "invalid_role")
+		case errors.Is(err, adminusers.ErrEnvAdmin):
+			badRequest(w, "cannot_change_env_admin")
+		case errors.Is(err, adminusers.ErrLastAdmin):
+			conflict(w, "last_admin")
+		case errors.Is(err, adminusers.ErrNotDBAdmin):
+			notFound(w)
+		default:
+			internalError(w)
+		}
+		return
+	}
+	if role != previous {
+		h.notifyRoleGranted(r, userID, role)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":   true,
+		"role": role,
+	})
+}
+
+// notifyRoleGranted はロールを付与されたユーザーにセキュリティ通知を送ります。
+func (h *Handler) notifyRoleGranted(r *http.Request, userID, role string) {
+	user, ok := h.users.GetByUserID(userID)
+	if !ok {
+		return
+	}
+	h.notifySecurityEvent(r, user, reports.SecurityNotice{
+		Event: reports.SecurityEventRoleGranted,
+		Role:  role,
+	})
+}
+
+// userRole は内部ユーザーIDからロールを返します。ロールの割り当ては UserID 単位です。
+func (h *Handler) userRole(userID string) (string, bool) {
+	if userID == "" || h.adminUsers == nil {
+		return "", false
+	}
+	user, ok := h.users.Get(userID)
+	if !ok {
+		return "", false
+	}
+	return h.adminUsers.RoleOf(user.UserID)
+}
+
+func (h *Handler) hasPermission(userID string, perm adminusers.Permission) bool {
+	if userID == "" || h.adminUsers == nil {
+		return false
+	}
+	user, ok := h.users.Get(userID)
+	if !ok {
+		return false
+	}
+	return h.adminUsers.HasPermission(user.UserID, perm)
+}
+
+// userPermissions はプロフィールに含める権限の一覧を返します（ロールがなければ空）。
+func (h *Handler) userPermissions(userID string) (string, []adminusers.Permission) {
+	role, ok := h.userRole(userID)
+	if !ok {
+		return "", []adminusers.Permission{}
+	}
+	return role, adminusers.Roles()[role]
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"book_manager/backend/internal/favorites"
+	"book_manager/backend/internal/userbooks"
+)
+
+// AdminSeriesMerge は重複したシリーズを 1 つにまとめます。
+// POST /admin/series/merge {sourceId, targetId}
+// source の所蔵とお気に入りを target に付け替えてから source を削除します。
+// 途中で失敗しても付け替え済みの分はそのままなので、同じリクエストを再実行すれば残りをまとめられます。
+func (h *Handler) AdminSeriesMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req struct {
+		SourceID string `json:"sourceId"`
+		TargetID string `json:"targetId"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	sourceID := strings.TrimSpace(req.SourceID)
+	targetID := strings.TrimSpace(req.TargetID)
+	if sourceID == "" || targetID == "" {
+		badRequest(w, "sourceId and targetId are required")
+		return
+	}
+	if sourceID == targetID {
+		badRequest(w, "sourceId and targetId must differ")
+		return
+	}
+	if _, ok := h.series.Get(sourceID); !ok {
+		notFoundWithMessage(w, "source series not found")
+		return
+	}
+	target, ok := h.series.Get(targetID)
+	if !ok {
+		notFoundWithMessage(w, "target series not found")
+		return
+	}
+
+	movedUserBooks := 0
+	for _, item := range h.userBooks.ListBySeriesID(sourceID) {
+		input := userbooks.UpdateInput{SeriesID: &targetID}
+		if _, ok := h.userBooks.Update(item.ID, input); !ok {
+			log.Printf("series merge: user book update failed: source=%s target=%s userBook=%s", sourceID, targetID, item.ID)
+			internalError(w)
+			return
+		}
+		movedUserBooks++
+	}
+
+	// お気に入りは (ユーザー, シリーズ) で一意なので、target を登録済みのユーザーは source の方を削除するだけにする
+	movedFavorites := 0
+	for _, fav := range h.favorites.ListBySeriesID(sourceID) {
+		_, err := h.favorites.Create(fav.UserID, fav.Type, fav.BookID, targetID)
+		switch {
+		case err == nil:
+			movedFavorites++
+		case errors.Is(err, favorites.ErrFavoriteExists):
+		default:
+			log.Printf("series merge: favorite create failed: source=%s target=%s favorite=%s err=%v", sourceID, targetID, fav.ID, err)
+			internalError(w)
+			return
+		}
+		if !h.favorites.Delete(fav.ID) {
+			log.Printf("series merge: favorite delete failed: source=%s favorite=%s", sourceID, fav.ID)
+			internalError(w)
+			return
+		}
+	}
+
+	if !h.series.Delete(sourceID) {
+		log.Printf("series merge: series delete failed: source=%s", sourceID)
+		internalError(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"series":         target,
+		"movedUserBooks": movedUserBooks,
+		"movedFavorites": movedFavorites,
+	})
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/isbn"
+	"book_manager/backend/internal/userbooks"
+)
+
+const (
+	defaultReclassifyLimit = 50
+	maxReclassifyLimit     = 200
+)
+
+type seriesAssignment struct {
+	SeriesID     string `json:"seriesId,omitempty"`
+	SeriesName   string `json:"seriesName"`
+	VolumeNumber int    `json:"volumeNumber"`
+	VolumeLabel  string `json:"volumeLabel"`
+	SeriesSource string `json:"seriesSource,omitempty"`
+	Confidence   int    `json:"confidence,omitempty"`
+}
+
+type reclassifyProposal struct {
+	UserBookID string           `json:"userBookId"`
+	BookID     string           `json:"bookId"`
+	Title      string           `json:"title"`
+	ISBN13     string           `json:"isbn13"`
+	Current    seriesAssignment `json:"current"`
+	Proposed   seriesAssignment `json:"proposed"`
+}
+
+// UsersMeSeriesReclassify は手動で設定したもの以外の所蔵についてシリーズを推定し直し、
+// 現在の設定と異なるものを変更案として返します。この時点では所蔵は更新しません。
+func (h *Handler) UsersMeSeriesReclassify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req struct {
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+	}
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(w, "invalid json")
+		return
+	}
+	if req.Limit < 0 || req.Limit > maxReclassifyLimit {
+		badRequest(w, "limit must be between 1 and 200")
+		return
+	}
+	if req.Offset < 0 {
+		badRequest(w, "offset must be 0 or positive")
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultReclassifyLimit
+	}
+	userID := userIDFromRequest(r)
+	targets := make([]domain.UserBook, 0)
+	bookIDs := make([]string, 0)
+	for _, item := range h.userBooks.ListByUser(userID) {
+		if item.SeriesSource == "manual" {
+			continue
+		}
+		targets = append(targets, item)
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	if req.Offset >= len(targets) {
+		targets = targets[:0]
+	} else {
+		targets = targets[req.Offset:]
+	}
+	remaining := 0
+	if len(targets) > req.Limit {
+		remaining = len(targets) - req.Limit
+		targets = targets[:req.Limit]
+	}
+	booksByID := make(map[string]domain.Book)
+	for _, book := range h.books.ListByIDs(bookIDs) {
+		booksByID[book.ID] = book
+	}
+	seriesNames := make(map[string]string)
+	for _, series := range h.series.List() {
+		seriesNames[series.ID] = series.Name
+	}
+
+	classifier := h.userSeriesClassifier(userID, h.users.GetSettings(userID), h.openAIKeyCandidates())
+	proposals := make([]reclassifyProposal, 0)
+	failed := 0
+	for _, item := range targets {
+		if err := r.Context().Err(); err != nil {
+			log.Printf("series reclassify canceled: user=%s err=%v", userID, err)
+			return
+		}
+		book, ok := booksByID[item.BookID]
+		if !ok {
+			continue
+		}
+		rawTitle := book.OriginalTitle
+		if rawTitle == "" {
+			rawTitle = book.Title
+		}
+		guess, err := classifier.GuessSeries(r.Context(), ai.SeriesInput{
+			Title:         rawTitle,
+			RawTitle:      rawTitle,
+			Authors:       book.Authors,
+			Publisher:     book.Publisher,
+			PublishedDate: book.PublishedDate,
+			ISBN13:        book.ISBN13,
+			SeriesName:    book.SeriesName,
+		})
+		if err != nil {
+			log.Printf("series reclassify: guess error: userBook=%s err=%v", item.ID, err)
+			failed++
+			continue
+		}
+		merged := mergeSeriesGuess(isbn.InferSeries(rawTitle, book.SeriesName), &guess)
+		current := seriesAssignment{
+			SeriesID:     item.SeriesID,
+			SeriesName:   seriesNames[item.SeriesID],
+			VolumeNumber: item.VolumeNumber,
+			VolumeLabel:  item.VolumeLabel,
+			SeriesSource: item.SeriesSource,
+		}
+		proposed := seriesAssignment{
+			SeriesName:   merged.Name,
+			VolumeNumber: merged.VolumeNumber,
+			VolumeLabel:  merged.Volume.Label,
+			SeriesSource: guess.Source,
+			Confidence:   guess.Confidence,
+		}
+		if current.SeriesName == proposed.SeriesName && current.VolumeNumber == proposed.VolumeNumber {
+			continue
+		}
+		proposals = append(proposals, reclassifyProposal{
+			UserBookID: item.ID,
+			BookID:     book.ID,
+			Title:      book.Title,
+			ISBN13:     book.ISBN13,
+			Current:    current,
+			Proposed:   proposed,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":     proposals,
+		"scanned":   len(targets),
+		"failed":    failed,
+		"remaining": remaining,
+	})
+}
+
+// UsersMeSeriesReclassifyApply は承認された変更案を所蔵に反映します。
+// 手動で設定された所蔵や他のユーザーの所蔵は更新せず、failed として返します。
+func (h *Handler) UsersMeSeriesReclassifyApply(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req struct {
+		Items []struct {
+			UserBookID  string `json:"userBookId"`
+			SeriesName  string `json:"seriesName"`
+			VolumeLabel string `json:"volumeLabel"`
+		} `json:"items"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		badRequest(w, "invalid json")
+		return
+	}
+	if len(req.Items) == 0 {
+		badRequest(w, "items is required")
+		return
+	}
+	if len(req.Items) > maxReclassifyLimit {
+		badRequest(w, "too many items")
+		return
+	}
+	userID := userIDFromRequest(r)
+	owned := make(map[string]domain.UserBook)
+	for _, item := range h.userBooks.ListByUser(userID) {
+		owned[item.ID] = item
+	}
+	type failure struct {
+		UserBookID string `json:"userBookId"`
+		Error      string `json:"error"`
+	}
+	applied := make([]domain.UserBook, 0, len(req.Items))
+	failed := make([]failure, 0)
+	for _, change := range req.Items {
+		item, ok := owned[change.UserBookID]
+		if !ok {
+			failed = append(failed, failure{UserBookID: change.UserBookID, Error: "not found"})
+			continue
+		}
+		if item.SeriesSource == "manual" {
+			failed = append(failed, failure{UserBookID: item.ID, Error: "series was set manually"})
+			continue
+		}
+		seriesID := ""
+		label := ""
+		if name := isbn.NormalizeSeriesName(change.SeriesName); name != "" {
+			series, err := h.series.Ensure(name)
+			if err != nil {
+				failed = append(failed, failure{UserBookID: item.ID, Error: "series could not be created"})
+				continue
+			}
+			seriesID = series.ID
+			label = strings.TrimSpace(change.VolumeLabel)
+		}
+		source := "auto"
+		updated, ok := h.userBooks.Update(item.ID, userbooks.UpdateInput{
+			SeriesID:     &seriesID,
+			VolumeLabel:  &label,
+			SeriesSource: &source,
+		})
+		if !ok {
+			failed = append(failed, failure{UserBookID: item.ID, Error: "update failed"})
+			continue
+		}
+		applied = append(applied, updated)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"applied": applied,
+		"failed":  failed,
+	})
+}
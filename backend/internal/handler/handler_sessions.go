@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"book_manager/backend/internal/apitokens"
+	"book_manager/backend/internal/auth"
+	"book_manager/backend/internal/authctx"
+	"book_manager/backend/internal/clientip"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/sessions"
+	"book_manager/backend/internal/validation"
+)
+
+// AuthSessions はログイン中のユーザーの有効なセッション（端末）の一覧を返します。
+// /auth/* は認証ミドルウェアの対象外のため、Bearer トークンをここで検証します。
+func (h *Handler) AuthSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	info, ok := h.bearerAuthInfo(w, r)
+	if !ok {
+		return
+	}
+	if h.sessions == nil {
+		internalError(w)
+		return
+	}
+	items := h.sessions.List(info.UserID)
+	result := make([]map[string]any, 0, len(items))
+	for _, session := range items {
+		result = append(result, sessionResponse(session))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":                result,
+		"perSessionRevocation": h.authBackend.Mode() != auth.ModeFirebase,
+	})
+}
+
+// AuthSessionsByID はセッションを失効させます（DELETE /auth/sessions/{id}）。
+// Firebase はリフレッシュトークンを個別に失効できないため、Firebase モードでは
+// RevokeRefreshTokens でユーザーのすべてのセッションを失効させます。
+func (h *Handler) AuthSessionsByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	id, ok := pathID("/auth/sessions/", r.URL.Path)
+	if !ok {
+		notFound(w)
+		return
+	}
+	info, ok := h.bearerAuthInfo(w, r)
+	if !ok {
+		return
+	}
+	if h.sessions == nil {
+		internalError(w)
+		return
+	}
+	if _, err := h.sessions.Revoke(info.UserID, id); err != nil {
+		if errors.Is(err, sessions.ErrSessionNotFound) {
+			notFound(w)
+			return
+		}
+		internalError(w)
+		return
+	}
+	revoked := 1
+	if h.authBackend.Mode() == auth.ModeFirebase {
+		if err := h.authBackend.RevokeRefreshTokens(r.Context(), info.UserID); err != nil {
+			log.Printf("ERROR: failed to revoke refresh tokens for user %s: %v", info.UserID, err)
+			internalError(w)
+			return
+		}
+		revoked += h.sessions.RevokeAll(info.UserID)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":      true,
+		"revoked": revoked,
+	})
+}
+
+// bearerAuthInfo は Authorization ヘッダーのアクセストークンを検証し、メールアドレス確認済みのユーザーだけを通します。
+// /auth/* は認証ミドルウェアを通らないため、ミドルウェアと同じ確認をここで行います。
+// 失敗した場合はレスポンスを書き込み false を返します。
+func (h *Handler) bearerAuthInfo(w http.ResponseWriter, r *http.Request) (authctx.AuthInfo, bool) {
+	info, ok := h.verifyBearerToken(w, r)
+	if !ok {
+		return authctx.AuthInfo{}, false
+	}
+	if !info.EmailVerified {
+		EmailNotVerified(w)
+		return authctx.AuthInfo{}, false
+	}
+	return info, true
+}
+
+// verifyBearerToken はアクセストークンを認証バックエンドで検証し、利用停止中のアカウントを拒否します。
+// メールアドレスの確認状態は確認しません（確認状態を返す GET /auth/status 向け）。
+func (h *Handler) verifyBearerToken(w http.ResponseWriter, r *http.Request) (authctx.AuthInfo, bool) {
+	token := validation.BearerToken(r.Header.Get("Authorization"))
+	if token == "" {
+		unauthorized(w)
+		return authctx.AuthInfo{}, false
+	}
+	// /auth/* はログインの管理のため、個人用アクセストークンでは扱えない
+	if apitokens.IsAPIToken(token) {
+		forbidden(w, "api tokens cannot be used for auth endpoints")
+		return authctx.AuthInfo{}, false
+	}
+	if h.authBackend == nil {
+		internalError(w)
+		return authctx.AuthInfo{}, false
+	}
+	info, err := h.authBackend.VerifyAccessToken(r.Context(), token)
+	if err != nil {
+		unauthorized(w)
+		return authctx.AuthInfo{}, false
+	}
+	if h.isSuspended(info.UserID) {
+		forbidden(w, "account_suspended")
+		return authctx.AuthInfo{}, false
+	}
+	return info, true
+}
+
+// startSession はサインアップ・ログインで発行したリフレッシュトークンをセッションとして記録します。
+// 記録に失敗してもログイン自体は成功として扱います。
+func (h *Handler) startSession(r *http.Request, userID, refreshToken, deviceName string) {
+	if h.sessions == nil {
+		return
+	}
+	if _, err := h.sessions.Start(userID, refreshToken, sessionClient(r, deviceName)); err != nil {
+		log.Printf("WARNING: failed to record session for user %s: %v", userID, err)
+	}
+}
+
+// checkSession は失効済みセッションのリフレッシュトークンを拒否します。
+func (h *Handler) checkSession(w http.ResponseWriter, refreshToken string) bool {
+	if h.sessions == nil {
+		return true
+	}
+	if err := h.sessions.Check(refreshToken); err != nil {
+		unauthorized(w)
+		return false
+	}
+	return true
+}
+
+// touchSession はリフレッシュ後のトークンでセッションを更新します。
+func (h *Handler) touchSession(w http.ResponseWriter, r *http.Request, userID, oldToken, newToken string) bool {
+	if h.sessions == nil {
+		return true
+	}
+	if _, err := h.sessions.Touch(userID, oldToken, newToken, sessionClient(r, "")); err != nil {
+		if errors.Is(err, sessions.ErrSessionRevoked) {
+			unauthorized(w)
+			return false
+		}
+		log.Printf("WARNING: failed to update session for user %s: %v", userID, err)
+	}
+	return true
+}
+
+func sessionClient(r *http.Request, deviceName string) sessions.Client {
+	return sessions.Client{
+		DeviceName: deviceName,
+		UserAgent:  r.UserAgent(),
+		IP:         clientIP(r),
+	}
+}
+
+// clientIP は送信元 IP を返します。X-Forwarded-For は TRUSTED_PROXIES のプロキシを経由した場合だけ使います。
+func clientIP(r *http.Request) string {
+	return clientip.FromRequest(r)
+}
+
+func sessionResponse(session domain.Session) map[string]any {
+	return map[string]any{
+		"id":         session.ID,
+		"deviceName": session.DeviceName,
+		"userAgent":  session.UserAgent,
+		"ip":         session.IP,
+		"createdAt":  session.CreatedAt.Format(time.RFC3339),
+		"lastUsedAt": session.LastUsedAt.Format(time.RFC3339),
+	}
+}
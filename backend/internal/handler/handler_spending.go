@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (h *Handler) UsersMeSpending(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	from := strings.TrimSpace(r.URL.Query().Get("from"))
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+	if from != "" && !isISOMonth(from) {
+		badRequest(w, "from must be YYYY-MM")
+		return
+	}
+	if to != "" && !isISOMonth(to) {
+		badRequest(w, "to must be YYYY-MM")
+		return
+	}
+	userID := userIDFromRequest(r)
+	settings := h.users.GetSettings(userID)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"report": h.spending.Report(userID, from, to),
+		"budget": h.spending.Budget(userID, settings, h.nextToBuy.ListByUser(userID), time.Now()),
+	})
+}
+
+func normalizePurchaseFields(currency, store *string) {
+	if currency != nil {
+		*currency = strings.ToUpper(strings.TrimSpace(*currency))
+	}
+	if store != nil {
+		*store = strings.TrimSpace(*store)
+	}
+}
+
+func validatePurchaseFields(price *int, currency *string) error {
+	if price != nil && *price < 0 {
+		return errors.New("purchasePrice must be 0 or positive")
+	}
+	if currency != nil && *currency != "" && !isCurrencyCode(*currency) {
+		return errors.New("currency must be a 3-letter code")
+	}
+	return nil
+}
+
+func isISOMonth(value string) bool {
+	if len(value) != 7 {
+		return false
+	}
+	_, err := time.Parse("2006-01", value)
+	return err == nil
+}
@@ -73,6 +73,10 @@ func forbidden(w http.ResponseWriter, message string) {
 	})
 }
 
+func Forbidden(w http.ResponseWriter, message string) {
+	forbidden(w, message)
+}
+
 func conflict(w http.ResponseWriter, message string) {
 	writeJSON(w, http.StatusConflict, map[string]string{
 		"error":   "conflict",
@@ -80,6 +84,13 @@ func conflict(w http.ResponseWriter, message string) {
 	})
 }
 
+func tooManyAttempts(w http.ResponseWriter) {
+	writeJSON(w, http.StatusTooManyRequests, map[string]string{
+		"error":   "too many requests",
+		"message": "too_many_attempts",
+	})
+}
+
 func internalError(w http.ResponseWriter) {
 	writeJSON(w, http.StatusInternalServerError, map[string]string{
 		"error": "internal server error",
@@ -44,3 +44,58 @@ func NewRecommendation() string {
 func NewNextToBuy() string {
 	return New("ntb")
 }
+
+// NewRelease は発売予定用のIDを生成します。
+func NewRelease() string {
+	return New("release")
+}
+
+// NewOpenAIUsage は OpenAI 使用量記録用のIDを生成します。
+func NewOpenAIUsage() string {
+	return New("usage")
+}
+
+// NewPrompt はプロンプトのバージョン用のIDを生成します。
+func NewPrompt() string {
+	return New("prompt")
+}
+
+// NewRefreshToken はリフレッシュトークン記録用のIDを生成します。
+func NewRefreshToken() string {
+	return New("rt")
+}
+
+// NewTokenFamily はリフレッシュトークンのローテーション系列用のIDを生成します。
+func NewTokenFamily() string {
+	return New("family")
+}
+
+// NewEmailVerification はメールアドレス確認用のIDを生成します。
+func NewEmailVerification() string {
+	return New("verify")
+}
+
+// NewUser はローカル認証モードのユーザー用の内部IDを生成します。
+func NewUser() string {
+	return New("user")
+}
+
+// NewSession はログインセッション用のIDを生成します。
+func NewSession() string {
+	return New("session")
+}
+
+// NewAPIToken は個人用アクセストークン用のIDを生成します。
+func NewAPIToken() string {
+	return New("apitoken")
+}
+
+// NewEmailChange はメールアドレス変更の確認用のIDを生成します。
+func NewEmailChange() string {
+	return New("emailchange")
+}
+
+// NewBookReport は書誌報告用のIDを生成します。
+func NewBookReport() string {
+	return New("bookreport")
+}
@@ -28,6 +28,7 @@ type Service struct {
 type SeriesGuess struct {
 	Name         string
 	VolumeNumber int
+	Volume       domain.Volume
 }
 
 func NewService(baseURL, apiKey string, ttl time.Duration, cache repository.IsbnCacheRepository) *Service {
@@ -79,6 +80,66 @@ func (s *Service) storeCache(isbn string, book domain.Book) {
 func (s *Service) fetchGoogleBooks(isbn string) (domain.Book, error) {
 	query := url.Values{}
 	query.Set("q", fmt.Sprintf("isbn:%s", isbn))
+	items, err := s.queryGoogleBooks(query)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if len(items) == 0 {
+		return domain.Book{}, ErrNotFound
+	}
+	return bookFromGoogleItem(items[0], isbn), nil
+}
+
+// SearchSeries はシリーズ名（と著者）で Google Books を新しい順に検索します。
+func (s *Service) SearchSeries(seriesName, author string) ([]domain.Book, error) {
+	seriesName = strings.TrimSpace(seriesName)
+	if seriesName == "" {
+		return nil, nil
+	}
+	terms := []string{fmt.Sprintf("intitle:%q", seriesName)}
+	if author = strings.TrimSpace(author); author != "" {
+		terms = append(terms, fmt.Sprintf("inauthor:%q", author))
+	}
+	query := url.Values{}
+	query.Set("q", strings.Join(terms, " "))
+	query.Set("orderBy", "newest")
+	query.Set("maxResults", "40")
+	items, err := s.queryGoogleBooks(query)
+	if err != nil {
+		return nil, err
+	}
+	books := make([]domain.Book, 0, len(items))
+	for _, item := range items {
+		books = append(books, bookFromGoogleItem(item, ""))
+	}
+	return books, nil
+}
+
+// SearchTitle はタイトル（と著者）で Google Books を関連度順に検索します。
+func (s *Service) SearchTitle(title, author string) ([]domain.Book, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, nil
+	}
+	terms := []string{fmt.Sprintf("intitle:%q", title)}
+	if author = strings.TrimSpace(author); author != "" {
+		terms = append(terms, fmt.Sprintf("inauthor:%q", author))
+	}
+	query := url.Values{}
+	query.Set("q", strings.Join(terms, " "))
+	query.Set("maxResults", "5")
+	items, err := s.queryGoogleBooks(query)
+	if err != nil {
+		return nil, err
+	}
+	books := make([]domain.Book, 0, len(items))
+	for _, item := range items {
+		books = append(books, bookFromGoogleItem(item, ""))
+	}
+	return books, nil
+}
+
+func (s *Service) queryGoogleBooks(query url.Values) ([]googleBooksItem, error) {
 	if s.apiKey != "" {
 		query.Set("key", s.apiKey)
 	}
@@ -86,36 +147,35 @@ func (s *Service) fetchGoogleBooks(isbn string) (domain.Book, error) {
 	requestURL := fmt.Sprintf("%s?%s", s.baseURL, query.Encode())
 	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 	if err != nil {
-		return domain.Book{}, err
+		return nil, err
 	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return domain.Book{}, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return domain.Book{}, fmt.Errorf("google books status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("google books status: %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
 	if err != nil {
-		return domain.Book{}, err
+		return nil, err
 	}
 
 	var payload googleBooksResponse
 	if err := json.Unmarshal(body, &payload); err != nil {
-		return domain.Book{}, err
-	}
-	if len(payload.Items) == 0 {
-		return domain.Book{}, ErrNotFound
+		return nil, err
 	}
+	return payload.Items, nil
+}
 
-	item := payload.Items[0]
-	book := domain.Book{
+func bookFromGoogleItem(item googleBooksItem, fallbackISBN string) domain.Book {
+	return domain.Book{
 		ID:            item.ID,
-		ISBN13:        extractISBN13(item.VolumeInfo.IndustryIdentifiers, isbn),
+		ISBN13:        extractISBN13(item.VolumeInfo.IndustryIdentifiers, fallbackISBN),
 		Title:         item.VolumeInfo.Title,
 		Authors:       item.VolumeInfo.Authors,
 		Publisher:     item.VolumeInfo.Publisher,
@@ -124,7 +184,6 @@ func (s *Service) fetchGoogleBooks(isbn string) (domain.Book, error) {
 		Source:        "google",
 		SeriesName:    item.VolumeInfo.Series,
 	}
-	return book, nil
 }
 
 func extractISBN13(identifiers []industryIdentifier, fallback string) string {
@@ -165,8 +224,8 @@ type googleBooksImageLinks struct {
 }
 
 var (
-	volumePattern         = regexp.MustCompile(`(?i)(?:第?\s*([0-9０-９]+)\s*(?:巻|冊|話)|vol\.?\s*([0-9０-９]+))`)
-	trailingNumberPattern = regexp.MustCompile(`\s*([0-9０-９]+)\s*$`)
+	volumePattern         = regexp.MustCompile(`(?i)(?:第?\s*(` + volumeNumberExpr + `)(?:` + volumeRangeExpr + `(` + volumeNumberExpr + `))?\s*(?:巻|冊|話)|vol\.?\s*(` + volumeNumberExpr + `)(?:` + volumeRangeExpr + `(` + volumeNumberExpr + `))?)`)
+	trailingNumberPattern = regexp.MustCompile(`\s*([0-9０-９]+(?:[.．][0-9０-９]+)?)\s*$`)
 	bracketPattern = regexp.MustCompile(`【[^】]+】`)
 	parenPattern = regexp.MustCompile(`[（(][^)）]+[)）]`)
 )
@@ -179,8 +238,12 @@ func inferSeries(title, seriesName string) SeriesGuess {
 	if title == "" {
 		return guess
 	}
+	guess.Volume = ExtractVolume(title)
+	guess.VolumeNumber = VolumeNumberOf(guess.Volume)
 	if guess.Name == "" {
 		guess.Name = strings.TrimSpace(volumePattern.ReplaceAllString(title, ""))
+		guess.Name = strings.TrimSpace(partPattern.ReplaceAllString(guess.Name, ""))
+		guess.Name = strings.TrimSpace(specialPattern.ReplaceAllString(guess.Name, ""))
 		guess.Name = strings.TrimSpace(trailingNumberPattern.ReplaceAllString(guess.Name, ""))
 		guess.Name = strings.Trim(guess.Name, " -‐–—・")
 	}
@@ -198,6 +261,8 @@ func NormalizeSeriesName(name string) string {
 	}
 	cleaned = bracketPattern.ReplaceAllString(cleaned, "")
 	cleaned = parenPattern.ReplaceAllString(cleaned, "")
+	cleaned = specialPattern.ReplaceAllString(cleaned, "")
+	cleaned = partPattern.ReplaceAllString(cleaned, "")
 	cleaned = volumePattern.ReplaceAllString(cleaned, "")
 	cleaned = trailingNumberPattern.ReplaceAllString(cleaned, "")
 	cleaned = strings.TrimSpace(cleaned)
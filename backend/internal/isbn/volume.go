@@ -0,0 +1,296 @@
+package isbn
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"book_manager/backend/internal/domain"
+)
+
+const (
+	volumeNumberExpr = `(?:[0-9０-９]+(?:[.．][0-9０-９]+)?|[〇零一二三四五六七八九十百千]+)`
+	volumeRangeExpr  = `\s*[-‐–~〜～]\s*`
+)
+
+var (
+	partPattern        = regexp.MustCompile(`(?:[(（]\s*(上|中|下|前編|後編)\s*[)）]|(上|中|下)巻)`)
+	specialPattern     = regexp.MustCompile(`特装版|限定版|特別版|豪華版|番外編`)
+	volumeLabelPattern = regexp.MustCompile(`(?i)^(?:第|vol\.?)?\s*(` + volumeNumberExpr + `)(?:` + volumeRangeExpr + `(` + volumeNumberExpr + `))?\s*(?:巻|冊|話)?$`)
+	partLabelPattern   = regexp.MustCompile(`^(上|中|下|前編|後編)巻?$`)
+)
+
+var partSortKeys = map[string]float64{
+	"上":  1,
+	"前編": 1,
+	"中":  2,
+	"下":  3,
+	"後編": 3,
+}
+
+var kanjiDigits = map[rune]int{
+	'〇': 0, '零': 0, '一': 1, '二': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+var kanjiUnits = map[rune]int{
+	'十': 10, '百': 100, '千': 1000,
+}
+
+// ParseVolume はユーザーが入力した巻数表記（"10.5", "上", "特装版", "1-3", "第十二巻" など）を解析します。
+// 解釈できない表記の場合は false を返します。
+func ParseVolume(label string) (domain.Volume, bool) {
+	value := strings.TrimSpace(label)
+	if value == "" {
+		return domain.Volume{}, false
+	}
+	edition := specialPattern.FindString(value)
+	rest := strings.TrimSpace(specialPattern.ReplaceAllString(value, ""))
+	rest = strings.TrimSpace(strings.Trim(rest, " ()（）"))
+	if rest == "" {
+		if edition == "" {
+			return domain.Volume{}, false
+		}
+		return withEdition(domain.Volume{}, edition), true
+	}
+	if m := volumeLabelPattern.FindStringSubmatch(rest); m != nil {
+		volume, ok := numberVolume(m[1], m[2])
+		if !ok {
+			return domain.Volume{}, false
+		}
+		return withEdition(volume, edition), true
+	}
+	if m := partLabelPattern.FindStringSubmatch(rest); m != nil {
+		return withEdition(partVolume(m[1]), edition), true
+	}
+	return domain.Volume{}, false
+}
+
+// ExtractVolume はタイトルに含まれる巻数表記を抽出します。見つからない場合はゼロ値を返します。
+func ExtractVolume(title string) domain.Volume {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return domain.Volume{}
+	}
+	edition := specialPattern.FindString(title)
+	volume := domain.Volume{}
+	if m := volumePattern.FindStringSubmatch(title); m != nil {
+		start, end := m[1], m[2]
+		if start == "" {
+			start, end = m[3], m[4]
+		}
+		volume, _ = numberVolume(start, end)
+	} else if m := partPattern.FindStringSubmatch(title); m != nil {
+		volume = partVolume(m[1] + m[2])
+	} else {
+		cleaned := specialPattern.ReplaceAllString(title, "")
+		cleaned = bracketPattern.ReplaceAllString(cleaned, "")
+		cleaned = strings.TrimSpace(parenPattern.ReplaceAllString(cleaned, ""))
+		if m := trailingNumberPattern.FindStringSubmatch(cleaned); m != nil &&
+			strings.TrimSpace(trailingNumberPattern.ReplaceAllString(cleaned, "")) != "" {
+			volume, _ = numberVolume(m[1], "")
+		}
+	}
+	return withEdition(volume, edition)
+}
+
+// NumberVolume は整数の巻数から Volume を作成します。0 以下の場合はゼロ値を返します。
+func NumberVolume(number int) domain.Volume {
+	if number <= 0 {
+		return domain.Volume{}
+	}
+	volume, _ := numberVolume(strconv.Itoa(number), "")
+	return volume
+}
+
+// UserBookVolume は所蔵の巻数ラベル（なければ整数の巻数）から Volume を求めます。
+func UserBookVolume(item domain.UserBook) domain.Volume {
+	if item.VolumeLabel != "" {
+		if volume, ok := ParseVolume(item.VolumeLabel); ok {
+			return volume
+		}
+	}
+	return NumberVolume(item.VolumeNumber)
+}
+
+// VolumeNumberOf は互換用の整数巻数を返します。数値で表せない巻は 0 になります。
+func VolumeNumberOf(volume domain.Volume) int {
+	switch volume.Kind {
+	case domain.VolumeKindNumber, domain.VolumeKindRange:
+		return int(math.Floor(volume.SortKey))
+	default:
+		return 0
+	}
+}
+
+// VolumeLess は巻の並び順を比較します。巻数のない特装版などは末尾に並びます。
+func VolumeLess(a, b domain.Volume) bool {
+	rankA, rankB := volumeRank(a), volumeRank(b)
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	if a.SortKey != b.SortKey {
+		return a.SortKey < b.SortKey
+	}
+	if a.End != b.End {
+		return a.End < b.End
+	}
+	return a.Label < b.Label
+}
+
+// LatestVolume は所持している整数巻のうち最大の巻数を返します。合本は末尾の巻まで含みます。
+func LatestVolume(volumes []domain.Volume) int {
+	_, latest := coveredVolumes(volumes)
+	return latest
+}
+
+// MissingVolumes は 1 巻から最新巻までのうち所持していない巻を返します。
+func MissingVolumes(volumes []domain.Volume) []int {
+	covered, latest := coveredVolumes(volumes)
+	missing := make([]int, 0)
+	for number := 1; number < latest; number++ {
+		if _, ok := covered[number]; !ok {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+func coveredVolumes(volumes []domain.Volume) (map[int]struct{}, int) {
+	covered := make(map[int]struct{})
+	latest := 0
+	for _, volume := range volumes {
+		start, end := volume.SortKey, volume.End
+		switch volume.Kind {
+		case domain.VolumeKindNumber:
+			if start != math.Trunc(start) {
+				continue
+			}
+			end = start
+		case domain.VolumeKindRange:
+		default:
+			continue
+		}
+		for number := int(math.Ceil(start)); number <= int(math.Floor(end)); number++ {
+			covered[number] = struct{}{}
+			if number > latest {
+				latest = number
+			}
+		}
+	}
+	return covered, latest
+}
+
+func volumeRank(volume domain.Volume) int {
+	switch volume.Kind {
+	case domain.VolumeKindNumber, domain.VolumeKindRange, domain.VolumeKindPart:
+		return 0
+	case domain.VolumeKindSpecial:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func numberVolume(start, end string) (domain.Volume, bool) {
+	first, ok := parseVolumeNumber(start)
+	if !ok {
+		return domain.Volume{}, false
+	}
+	if end == "" {
+		return domain.Volume{
+			Label:   formatVolumeNumber(first),
+			Kind:    domain.VolumeKindNumber,
+			SortKey: first,
+			End:     first,
+		}, true
+	}
+	last, ok := parseVolumeNumber(end)
+	if !ok || last < first {
+		return domain.Volume{}, false
+	}
+	if last == first {
+		return numberVolume(start, "")
+	}
+	return domain.Volume{
+		Label:   formatVolumeNumber(first) + "-" + formatVolumeNumber(last),
+		Kind:    domain.VolumeKindRange,
+		SortKey: first,
+		End:     last,
+	}, true
+}
+
+func partVolume(label string) domain.Volume {
+	key := partSortKeys[label]
+	return domain.Volume{
+		Label:   label,
+		Kind:    domain.VolumeKindPart,
+		SortKey: key,
+		End:     key,
+	}
+}
+
+func withEdition(volume domain.Volume, edition string) domain.Volume {
+	if edition == "" {
+		return volume
+	}
+	if volume.Kind == domain.VolumeKindNone {
+		return domain.Volume{
+			Label: edition,
+			Kind:  domain.VolumeKindSpecial,
+		}
+	}
+	volume.Label += " " + edition
+	return volume
+}
+
+func parseVolumeNumber(value string) (float64, bool) {
+	value = strings.TrimSpace(toHalfWidthDigits(value))
+	if value == "" {
+		return 0, false
+	}
+	if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+		return parsed, parsed >= 0
+	}
+	parsed, ok := parseKanjiNumber(value)
+	return float64(parsed), ok
+}
+
+// parseKanjiNumber は "十二" や "百二十三"、"一〇" のような漢数字を整数に変換します。
+func parseKanjiNumber(value string) (int, bool) {
+	total, current := 0, 0
+	for _, r := range value {
+		if digit, ok := kanjiDigits[r]; ok {
+			current = current*10 + digit
+			continue
+		}
+		unit, ok := kanjiUnits[r]
+		if !ok {
+			return 0, false
+		}
+		if current == 0 {
+			current = 1
+		}
+		total += current * unit
+		current = 0
+	}
+	return total + current, true
+}
+
+func toHalfWidthDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return '0' + (r - '０')
+		case r == '．':
+			return '.'
+		default:
+			return r
+		}
+	}, value)
+}
+
+func formatVolumeNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
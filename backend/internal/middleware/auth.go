@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"book_manager/backend/internal/apitokens"
+	"book_manager/backend/internal/authctx"
+	"book_manager/backend/internal/handler"
+	"book_manager/backend/internal/users"
+	"book_manager/backend/internal/validation"
+)
+
+// TokenVerifier は Bearer トークンを検証して認証情報を返します。
+// auth.Backend（Firebase / ローカル）のどちらもこのインターフェースを満たします。
+type TokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, token string) (authctx.AuthInfo, error)
+}
+
+// SuspensionChecker は管理者によって利用停止されたアカウントかを返します。
+type SuspensionChecker interface {
+	IsSuspended(userID string) bool
+}
+
+type AuthMiddleware struct {
+	verifier     TokenVerifier
+	usersService *users.Service
+	apiTokens    *apitokens.Service
+	suspensions  SuspensionChecker
+}
+
+func NewAuthMiddleware(verifier TokenVerifier, usersService *users.Service, apiTokens *apitokens.Service, suspensions SuspensionChecker) *AuthMiddleware {
+	return &AuthMiddleware{
+		verifier:     verifier,
+		usersService: usersService,
+		apiTokens:    apiTokens,
+		suspensions:  suspensions,
+	}
+}
+
+func (m *AuthMiddleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.Method == http.MethodOptions || strings.HasPrefix(r.URL.Path, "/auth/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// iCalendar フィードは URL 内のトークンで認証する
+		if strings.HasPrefix(r.URL.Path, "/calendar/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token := validation.BearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			handler.Unauthorized(w)
+			return
+		}
+		// 個人用アクセストークンはログインセッションの代わりに使える
+		if apitokens.IsAPIToken(token) {
+			m.serveAPIToken(w, r, next, token)
+			return
+		}
+		info, err := m.verifier.VerifyAccessToken(r.Context(), token)
+		if err != nil {
+			handler.Unauthorized(w)
+			return
+		}
+		if m.usersService != nil {
+			user, err := m.usersService.Ensure(info.UserID, info.Email, info.Name)
+			if err != nil {
+				handler.InternalError(w)
+				return
+			}
+			// 管理画面の確認状態の表示・並び替えに使うため、Firebase で確認済みになったことを記録する
+			if info.EmailVerified && !user.EmailVerified {
+				_ = m.usersService.MarkEmailVerified(user.ID)
+			}
+		}
+		if m.isSuspended(info.UserID) {
+			handler.Forbidden(w, "account_suspended")
+			return
+		}
+		if !info.EmailVerified {
+			handler.EmailNotVerified(w)
+			return
+		}
+		ctx := authctx.WithAuthInfo(r.Context(), info)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// serveAPIToken は個人用アクセストークンで認証し、スコープで許可されたリクエストのみ通します。
+func (m *AuthMiddleware) serveAPIToken(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	if m.apiTokens == nil || m.usersService == nil {
+		handler.Unauthorized(w)
+		return
+	}
+	item, err := m.apiTokens.Authenticate(token)
+	if err != nil {
+		handler.Unauthorized(w)
+		return
+	}
+	user, ok := m.usersService.Get(item.UserID)
+	if !ok {
+		handler.Unauthorized(w)
+		return
+	}
+	if m.isSuspended(user.ID) {
+		handler.Forbidden(w, "account_suspended")
+		return
+	}
+	if !apitokens.Allows(item.Scope, r.Method) {
+		handler.Forbidden(w, "insufficient_scope")
+		return
+	}
+	info := authctx.AuthInfo{
+		UserID: user.ID,
+		Email:  user.Email,
+		Name:   user.UserID,
+		// トークンはメールアドレス確認済みのセッションからのみ発行できる
+		EmailVerified: true,
+		APITokenScope: item.Scope,
+	}
+	ctx := authctx.WithAuthInfo(r.Context(), info)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (m *AuthMiddleware) isSuspended(userID string) bool {
+	return m.suspensions != nil && m.suspensions.IsSuspended(userID)
+}
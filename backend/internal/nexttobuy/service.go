@@ -1,6 +1,8 @@
 package nexttobuy
 
 import (
+	"sort"
+
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/idgen"
 	"book_manager/backend/internal/repository"
@@ -10,11 +12,32 @@ type Service struct {
 	repo repository.NextToBuyRepository
 }
 
+const MaxPriority = 5
+
+type CreateInput struct {
+	Title         string
+	SeriesName    string
+	VolumeNumber  int
+	Note          string
+	TargetDate    string
+	Priority      int
+	ExpectedPrice int
+	Currency      string
+	Store         string
+	StoreURL      string
+}
+
 type UpdateInput struct {
-	Title        *string
-	SeriesName   *string
-	VolumeNumber *int
-	Note         *string
+	Title         *string
+	SeriesName    *string
+	VolumeNumber  *int
+	Note          *string
+	TargetDate    *string
+	Priority      *int
+	ExpectedPrice *int
+	Currency      *string
+	Store         *string
+	StoreURL      *string
 }
 
 func NewService(repo repository.NextToBuyRepository) *Service {
@@ -23,23 +46,39 @@ func NewService(repo repository.NextToBuyRepository) *Service {
 	}
 }
 
-func (s *Service) Create(userID, title, seriesName string, volumeNumber int, note string) (domain.NextToBuyManual, error) {
+func (s *Service) Create(userID string, input CreateInput) (domain.NextToBuyManual, error) {
 	item := domain.NextToBuyManual{
-		ID:           idgen.NewNextToBuy(),
-		UserID:       userID,
-		Title:        title,
-		SeriesName:   seriesName,
-		VolumeNumber: volumeNumber,
-		Note:         note,
+		ID:            idgen.NewNextToBuy(),
+		UserID:        userID,
+		Title:         input.Title,
+		SeriesName:    input.SeriesName,
+		VolumeNumber:  input.VolumeNumber,
+		Note:          input.Note,
+		TargetDate:    input.TargetDate,
+		Priority:      input.Priority,
+		ExpectedPrice: input.ExpectedPrice,
+		Currency:      input.Currency,
+		Store:         input.Store,
+		StoreURL:      input.StoreURL,
 	}
+	applyDefaultCurrency(&item)
 	if err := s.repo.Create(item); err != nil {
 		return domain.NextToBuyManual{}, err
 	}
 	return item, nil
 }
 
+// ListByUser は優先度の高い順（同じ優先度なら登録順）に返します。
 func (s *Service) ListByUser(userID string) []domain.NextToBuyManual {
-	return s.repo.ListByUser(userID)
+	items := s.repo.ListByUser(userID)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Priority > items[j].Priority
+	})
+	return items
+}
+
+func (s *Service) Get(id string) (domain.NextToBuyManual, bool) {
+	return s.repo.FindByID(id)
 }
 
 func (s *Service) Update(id string, input UpdateInput) (domain.NextToBuyManual, bool) {
@@ -59,6 +98,25 @@ func (s *Service) Update(id string, input UpdateInput) (domain.NextToBuyManual,
 	if input.Note != nil {
 		item.Note = *input.Note
 	}
+	if input.TargetDate != nil {
+		item.TargetDate = *input.TargetDate
+	}
+	if input.Priority != nil {
+		item.Priority = *input.Priority
+	}
+	if input.ExpectedPrice != nil {
+		item.ExpectedPrice = *input.ExpectedPrice
+	}
+	if input.Currency != nil {
+		item.Currency = *input.Currency
+	}
+	if input.Store != nil {
+		item.Store = *input.Store
+	}
+	if input.StoreURL != nil {
+		item.StoreURL = *input.StoreURL
+	}
+	applyDefaultCurrency(&item)
 	if !s.repo.Update(item) {
 		return domain.NextToBuyManual{}, false
 	}
@@ -68,3 +126,10 @@ func (s *Service) Update(id string, input UpdateInput) (domain.NextToBuyManual,
 func (s *Service) Delete(id string) bool {
 	return s.repo.Delete(id)
 }
+
+// applyDefaultCurrency は予定価格があり通貨が未指定の場合に既定の通貨を設定します。
+func applyDefaultCurrency(item *domain.NextToBuyManual) {
+	if item.ExpectedPrice > 0 && item.Currency == "" {
+		item.Currency = domain.DefaultCurrency
+	}
+}
@@ -3,19 +3,63 @@ package openaikeys
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/repository"
 )
 
+const (
+	StrategyRoundRobin = "round-robin"
+	StrategyLeastUsed  = "least-used"
+)
+
+// DefaultCooldown はキーを異常とみなしてから再び使うまでの既定の待ち時間です。
+const DefaultCooldown = 5 * time.Minute
+
+// Health はキーの稼働状況です。プロセス内でのみ保持し、再起動するとリセットされます。
+type Health struct {
+	Healthy        bool       `json:"healthy"`
+	UnhealthyUntil *time.Time `json:"unhealthyUntil,omitempty"`
+	LastStatus     int        `json:"lastStatus,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	Failures       int        `json:"failures"`
+}
+
 type Service struct {
-	repo repository.OpenAIKeyRepository
+	repo     repository.OpenAIKeyRepository
+	strategy string
+	cooldown time.Duration
+
+	mu     sync.Mutex
+	cursor int
+	// health はプロセス内だけで持つため、再起動するとすべてのキーが正常に戻り、複数のインスタンス間でも共有されない
+	health map[string]*Health
+}
+
+// NewService は未知の strategy を round-robin として扱います。cooldown が 0 以下の場合は DefaultCooldown を使います。
+func NewService(repo repository.OpenAIKeyRepository, strategy string, cooldown time.Duration) *Service {
+	strategy = strings.TrimSpace(strategy)
+	if strategy != StrategyLeastUsed {
+		strategy = StrategyRoundRobin
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Service{
+		repo:     repo,
+		strategy: strategy,
+		cooldown: cooldown,
+		health:   make(map[string]*Health),
+	}
 }
 
-func NewService(repo repository.OpenAIKeyRepository) *Service {
-	return &Service{repo: repo}
+func (s *Service) Strategy() string {
+	return s.strategy
 }
 
 func (s *Service) Create(name, apiKey string) domain.OpenAIKey {
@@ -34,15 +78,120 @@ func (s *Service) List() []domain.OpenAIKey {
 }
 
 func (s *Service) Delete(id string) bool {
-	return s.repo.Delete(id)
+	if !s.repo.Delete(id) {
+		return false
+	}
+	s.mu.Lock()
+	delete(s.health, id)
+	s.mu.Unlock()
+	return true
 }
 
-func (s *Service) First() (domain.OpenAIKey, bool) {
-	items := s.repo.List()
-	if len(items) == 0 {
+func (s *Service) Get(id string) (domain.OpenAIKey, bool) {
+	for _, item := range s.repo.List() {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return domain.OpenAIKey{}, false
+}
+
+// SetMonthlyBudget はキーの月の利用上限を設定します。0 を指定すると上限なしになります。
+func (s *Service) SetMonthlyBudget(id string, budgetMicroUSD int64) (domain.OpenAIKey, bool) {
+	item, ok := s.Get(id)
+	if !ok {
+		return domain.OpenAIKey{}, false
+	}
+	item.MonthlyBudgetMicroUSD = budgetMicroUSD
+	if !s.repo.Update(item) {
 		return domain.OpenAIKey{}, false
 	}
-	return items[0], true
+	return item, true
+}
+
+// Candidates は正常なキーを選択戦略の順に並べて返します。呼び出し側は先頭から順に試し、失敗したら次へ切り替えます。
+// round-robin は呼び出しごとに先頭を 1 つずつずらし、least-used は usage の小さい順に並べます。
+func (s *Service) Candidates(usage func(keyID string) int) []domain.OpenAIKey {
+	now := time.Now()
+	items := make([]domain.OpenAIKey, 0)
+	for _, item := range s.repo.List() {
+		if strings.TrimSpace(item.APIKey) == "" || !s.Health(item.ID, now).Healthy {
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return items
+	}
+	if s.strategy == StrategyLeastUsed && usage != nil {
+		used := make(map[string]int, len(items))
+		for _, item := range items {
+			used[item.ID] = usage(item.ID)
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return used[items[i].ID] < used[items[j].ID]
+		})
+		return items
+	}
+	s.mu.Lock()
+	start := s.cursor % len(items)
+	s.cursor++
+	s.mu.Unlock()
+	return append(items[start:], items[:start]...)
+}
+
+// Health はキーの稼働状況を返します。クールダウンが明けたキーは正常として扱います。
+func (s *Service) Health(id string, now time.Time) Health {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.health[id]
+	if !ok {
+		return Health{Healthy: true}
+	}
+	health := *state
+	if health.UnhealthyUntil != nil && !now.Before(*health.UnhealthyUntil) {
+		health.UnhealthyUntil = nil
+	}
+	health.Healthy = health.UnhealthyUntil == nil
+	return health
+}
+
+// MarkHealthy は呼び出しに成功したキーを記録し、異常状態を解除します。
+func (s *Service) MarkHealthy(id string) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.stateLocked(id)
+	state.UnhealthyUntil = nil
+	state.LastUsedAt = &now
+	state.Failures = 0
+}
+
+// MarkUnhealthy はキーを一定時間使わないようにします。retryAfter が既定のクールダウンより長い場合はそちらを使います。
+func (s *Service) MarkUnhealthy(id string, status int, message string, retryAfter time.Duration) {
+	now := time.Now()
+	cooldown := s.cooldown
+	if retryAfter > cooldown {
+		cooldown = retryAfter
+	}
+	until := now.Add(cooldown)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.stateLocked(id)
+	state.UnhealthyUntil = &until
+	state.LastStatus = status
+	state.LastError = message
+	state.LastUsedAt = &now
+	state.Failures++
+}
+
+func (s *Service) stateLocked(id string) *Health {
+	state, ok := s.health[id]
+	if !ok {
+		state = &Health{}
+		s.health[id] = state
+	}
+	return state
 }
 
 func MaskKey(value string) string {
@@ -0,0 +1,120 @@
+package prompts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/isbn"
+)
+
+// Fixture は評価用にシリーズ名と巻数の正解を付けた書誌です。
+type Fixture struct {
+	Title         string   `json:"title"`
+	RawTitle      string   `json:"rawTitle"`
+	Authors       []string `json:"authors"`
+	Publisher     string   `json:"publisher"`
+	PublishedDate string   `json:"publishedDate"`
+	ISBN13        string   `json:"isbn13"`
+	SeriesName    string   `json:"seriesName"`
+	Expected      struct {
+		IsSeries     bool   `json:"isSeries"`
+		SeriesName   string `json:"seriesName"`
+		VolumeNumber int    `json:"volumeNumber"`
+	} `json:"expected"`
+}
+
+// Score はプロンプトとモデルの組み合わせごとの正解数です。
+// シリーズ名はどちらも NormalizeSeriesName で正規化してから比較します。
+type Score struct {
+	Total         int      `json:"total"`
+	Errors        int      `json:"errors"`
+	SeriesCorrect int      `json:"seriesCorrect"`
+	VolumeCorrect int      `json:"volumeCorrect"`
+	BothCorrect   int      `json:"bothCorrect"`
+	Mismatches    []string `json:"mismatches,omitempty"`
+}
+
+func (s Score) SeriesAccuracy() float64 {
+	return ratio(s.SeriesCorrect, s.Total)
+}
+
+func (s Score) VolumeAccuracy() float64 {
+	return ratio(s.VolumeCorrect, s.Total)
+}
+
+func (s Score) Accuracy() float64 {
+	return ratio(s.BothCorrect, s.Total)
+}
+
+// LoadFixtures は JSON 配列形式のフィクスチャを読み込みます。
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("parse fixtures: %w", err)
+	}
+	return fixtures, nil
+}
+
+// Evaluate はフィクスチャを分類器に通し、シリーズ名と巻数の正解数を数えます。
+// 推定に失敗したものは不正解として Errors にも数えます。
+func Evaluate(ctx context.Context, classifier ai.SeriesClassifier, fixtures []Fixture) Score {
+	score := Score{Total: len(fixtures)}
+	for _, fixture := range fixtures {
+		rawTitle := fixture.RawTitle
+		if rawTitle == "" {
+			rawTitle = fixture.Title
+		}
+		guess, err := classifier.GuessSeries(ctx, ai.SeriesInput{
+			Title:         fixture.Title,
+			RawTitle:      rawTitle,
+			Authors:       fixture.Authors,
+			Publisher:     fixture.Publisher,
+			PublishedDate: fixture.PublishedDate,
+			ISBN13:        fixture.ISBN13,
+			SeriesName:    fixture.SeriesName,
+		})
+		if err != nil {
+			score.Errors++
+			score.Mismatches = append(score.Mismatches, fmt.Sprintf("%s: error: %v", fixture.Title, err))
+			continue
+		}
+		expectedName := ""
+		if fixture.Expected.IsSeries {
+			expectedName = isbn.NormalizeSeriesName(fixture.Expected.SeriesName)
+		}
+		gotName := ""
+		if guess.IsSeries {
+			gotName = isbn.NormalizeSeriesName(guess.Name)
+		}
+		seriesOK := strings.EqualFold(expectedName, gotName)
+		volumeOK := fixture.Expected.VolumeNumber == guess.VolumeNumber
+		if seriesOK {
+			score.SeriesCorrect++
+		}
+		if volumeOK {
+			score.VolumeCorrect++
+		}
+		if seriesOK && volumeOK {
+			score.BothCorrect++
+			continue
+		}
+		score.Mismatches = append(score.Mismatches, fmt.Sprintf("%s: expected %q #%d, got %q #%d",
+			fixture.Title, expectedName, fixture.Expected.VolumeNumber, gotName, guess.VolumeNumber))
+	}
+	return score
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -0,0 +1,125 @@
+package prompts
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/repository"
+)
+
+var ErrEmptyPrompt = errors.New("prompt content is required")
+
+// createAttempts は同時に保存されてバージョンが重複したときに採番し直す回数です。
+const createAttempts = 5
+
+// cacheTTL は有効なプロンプトを DB から読み直す間隔です。複数台構成でも切り替えがこの時間内に反映されます。
+const cacheTTL = 30 * time.Second
+
+// Service はシリーズ推定のシステムプロンプトをバージョン管理します。
+// 有効なバージョンがなければ起動時に読み込んだ prompt.md の内容を使います。
+type Service struct {
+	repo     repository.PromptRepository
+	fallback string
+
+	mu       sync.Mutex
+	current  string
+	loadedAt time.Time
+}
+
+func NewService(repo repository.PromptRepository, fallback string) *Service {
+	return &Service{
+		repo:     repo,
+		fallback: fallback,
+	}
+}
+
+// Current は推定に使うプロンプトを返します。
+func (s *Service) Current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < cacheTTL {
+		return s.current
+	}
+	s.current = s.fallback
+	if active, ok := s.repo.FindActive(); ok {
+		s.current = active.Content
+	}
+	s.loadedAt = time.Now()
+	return s.current
+}
+
+// Fallback は prompt.md から読み込んだプロンプトを返します。
+func (s *Service) Fallback() string {
+	return s.fallback
+}
+
+// Create は新しいバージョンを保存します。activate が true の場合はすぐに有効にします。
+func (s *Service) Create(content, note, createdBy string, activate bool) (domain.Prompt, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return domain.Prompt{}, ErrEmptyPrompt
+	}
+	item := domain.Prompt{
+		ID:        idgen.NewPrompt(),
+		Content:   content,
+		Note:      strings.TrimSpace(note),
+		CreatedBy: createdBy,
+	}
+	// バージョンは一意制約で守り、ほかの保存と重なった場合は最新のバージョンから採番し直す
+	var err error
+	for attempt := 0; attempt < createAttempts; attempt++ {
+		item.Version = s.nextVersion()
+		item.CreatedAt = time.Now()
+		err = s.repo.Create(item)
+		if !errors.Is(err, repository.ErrPromptVersionExists) {
+			break
+		}
+	}
+	if err != nil {
+		return domain.Prompt{}, err
+	}
+	if activate {
+		if activated, ok := s.Activate(item.ID); ok {
+			item = activated
+		}
+	}
+	return item, nil
+}
+
+func (s *Service) nextVersion() int {
+	version := 1
+	for _, item := range s.repo.List() {
+		if item.Version >= version {
+			version = item.Version + 1
+		}
+	}
+	return version
+}
+
+func (s *Service) List() []domain.Prompt {
+	return s.repo.List()
+}
+
+func (s *Service) Get(id string) (domain.Prompt, bool) {
+	return s.repo.FindByID(id)
+}
+
+// Activate は指定したバージョンに切り替えます。このインスタンスには即時に反映されます。
+func (s *Service) Activate(id string) (domain.Prompt, bool) {
+	if !s.repo.Activate(id) {
+		return domain.Prompt{}, false
+	}
+	item, ok := s.repo.FindByID(id)
+	if !ok {
+		return domain.Prompt{}, false
+	}
+	s.mu.Lock()
+	s.current = item.Content
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return item, true
+}
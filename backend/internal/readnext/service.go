@@ -0,0 +1,258 @@
+package readnext
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"book_manager/backend/internal/ai"
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/isbn"
+	"book_manager/backend/internal/repository"
+)
+
+const (
+	maxSummarySeries  = 30
+	maxSummaryAuthors = 10
+	maxSummaryTitles  = 20
+)
+
+// BookSearcher はタイトルと著者から実在する書籍を検索します。
+type BookSearcher interface {
+	SearchTitle(title, author string) ([]domain.Book, error)
+}
+
+// Suggestion は書誌検索で実在を確認した「次に読む本」です。
+type Suggestion struct {
+	Book   domain.Book `json:"book"`
+	Reason string      `json:"reason"`
+}
+
+// Result は提案の結果です。Unresolved は書誌が見つからなかった、または所蔵済みだった候補の数です。
+type Result struct {
+	Items      []Suggestion `json:"items"`
+	Unresolved int          `json:"unresolved"`
+}
+
+type Service struct {
+	userBooks       repository.UserBookRepository
+	books           repository.BookRepository
+	series          repository.SeriesRepository
+	favorites       repository.FavoriteRepository
+	recommendations repository.RecommendationRepository
+	searcher        BookSearcher
+}
+
+func NewService(
+	userBooks repository.UserBookRepository,
+	books repository.BookRepository,
+	series repository.SeriesRepository,
+	favorites repository.FavoriteRepository,
+	recommendations repository.RecommendationRepository,
+	searcher BookSearcher,
+) *Service {
+	return &Service{
+		userBooks:       userBooks,
+		books:           books,
+		series:          series,
+		favorites:       favorites,
+		recommendations: recommendations,
+		searcher:        searcher,
+	}
+}
+
+// Suggest は蔵書の要約を suggester に渡して候補を受け取り、書誌検索で実在する書籍に解決します。
+// 所蔵済みの ISBN・タイトルと重複する候補は除外し、最大 limit 件を返します。
+func (s *Service) Suggest(ctx context.Context, userID string, suggester ai.ReadingSuggester, limit int) (Result, error) {
+	library, owned := s.summarize(userID)
+	// 解決できない候補や所蔵済みの候補を見込んで多めに依頼する
+	candidates, err := suggester.SuggestBooks(ctx, library, limit*2)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Items: make([]Suggestion, 0, limit)}
+	for _, candidate := range candidates {
+		if len(result.Items) >= limit || ctx.Err() != nil {
+			break
+		}
+		if owned.hasTitle(candidate.Title) {
+			result.Unresolved++
+			continue
+		}
+		book, ok := s.resolve(candidate, owned)
+		if !ok {
+			result.Unresolved++
+			continue
+		}
+		owned.add(book)
+		result.Items = append(result.Items, Suggestion{Book: book, Reason: candidate.Reason})
+	}
+	return result, nil
+}
+
+// summarize は所蔵・お気に入り・おすすめ投稿から蔵書の要約と所蔵済みの書籍を集めます。
+func (s *Service) summarize(userID string) (ai.LibrarySummary, *ownedBooks) {
+	userItems := s.userBooks.ListByUser(userID)
+	bookIDs := make([]string, 0, len(userItems))
+	for _, item := range userItems {
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	owned := newOwnedBooks()
+	booksByID := make(map[string]domain.Book)
+	for _, book := range s.books.ListByIDs(bookIDs) {
+		booksByID[book.ID] = book
+		owned.add(book)
+	}
+	seriesNames := make(map[string]string)
+	for _, series := range s.series.List() {
+		seriesNames[series.ID] = series.Name
+	}
+
+	library := ai.LibrarySummary{BookCount: len(userItems)}
+	seriesIndex := make(map[string]int)
+	authorCounts := make(map[string]int)
+	for _, item := range userItems {
+		book, ok := booksByID[item.BookID]
+		if !ok {
+			continue
+		}
+		for _, author := range book.Authors {
+			if author = strings.TrimSpace(author); author != "" {
+				authorCounts[author]++
+			}
+		}
+		name := seriesNames[item.SeriesID]
+		if name == "" {
+			continue
+		}
+		index, ok := seriesIndex[name]
+		if !ok {
+			index = len(library.Series)
+			seriesIndex[name] = index
+			library.Series = append(library.Series, ai.SeriesSummary{Name: name, Authors: book.Authors})
+		}
+		library.Series[index].Volumes++
+	}
+	sort.SliceStable(library.Series, func(i, j int) bool {
+		return library.Series[i].Volumes > library.Series[j].Volumes
+	})
+	if len(library.Series) > maxSummarySeries {
+		library.Series = library.Series[:maxSummarySeries]
+	}
+	library.Authors = topAuthors(authorCounts, maxSummaryAuthors)
+
+	favoriteBookIDs := make([]string, 0)
+	for _, favorite := range s.favorites.ListByUser(userID) {
+		if name := seriesNames[favorite.SeriesID]; name != "" {
+			library.Favorites = append(library.Favorites, name)
+		} else if favorite.BookID != "" {
+			favoriteBookIDs = append(favoriteBookIDs, favorite.BookID)
+		}
+	}
+	for _, book := range s.books.ListByIDs(favoriteBookIDs) {
+		library.Favorites = append(library.Favorites, book.Title)
+	}
+	likedBookIDs := make([]string, 0)
+	for _, item := range s.recommendations.ListByUser(userID) {
+		likedBookIDs = append(likedBookIDs, item.BookID)
+	}
+	for _, book := range s.books.ListByIDs(likedBookIDs) {
+		if len(library.Liked) >= maxSummaryTitles {
+			break
+		}
+		library.Liked = append(library.Liked, book.Title)
+	}
+	if len(library.Favorites) > maxSummaryTitles {
+		library.Favorites = library.Favorites[:maxSummaryTitles]
+	}
+	return library, owned
+}
+
+// resolve は候補を書誌検索し、ISBN があり未所蔵の最初の書籍を返します。
+// 著者付きで見つからない場合はタイトルのみで検索し直します。
+func (s *Service) resolve(candidate ai.BookSuggestion, owned *ownedBooks) (domain.Book, bool) {
+	queries := [][2]string{{candidate.Title, candidate.Author}}
+	if candidate.Author != "" {
+		queries = append(queries, [2]string{candidate.Title, ""})
+	}
+	for _, query := range queries {
+		found, err := s.searcher.SearchTitle(query[0], query[1])
+		if err != nil {
+			return domain.Book{}, false
+		}
+		for _, book := range found {
+			if book.ISBN13 == "" {
+				continue
+			}
+			if owned.has(book) {
+				return domain.Book{}, false
+			}
+			return book, true
+		}
+	}
+	return domain.Book{}, false
+}
+
+func topAuthors(counts map[string]int, limit int) []string {
+	authors := make([]string, 0, len(counts))
+	for author := range counts {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if counts[authors[i]] != counts[authors[j]] {
+			return counts[authors[i]] > counts[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+	if len(authors) > limit {
+		authors = authors[:limit]
+	}
+	return authors
+}
+
+// ownedBooks は所蔵済み（または提案済み）の書籍を ISBN と正規化したタイトルで判定します。
+// タイトルは巻数を除いて比較するため、所蔵シリーズの続刊も除外されます（続刊は「次に買う本」で扱います）。
+type ownedBooks struct {
+	isbns  map[string]struct{}
+	titles map[string]struct{}
+}
+
+func newOwnedBooks() *ownedBooks {
+	return &ownedBooks{
+		isbns:  make(map[string]struct{}),
+		titles: make(map[string]struct{}),
+	}
+}
+
+func (o *ownedBooks) add(book domain.Book) {
+	if book.ISBN13 != "" {
+		o.isbns[book.ISBN13] = struct{}{}
+	}
+	if key := titleKey(book.Title); key != "" {
+		o.titles[key] = struct{}{}
+	}
+}
+
+func (o *ownedBooks) has(book domain.Book) bool {
+	if _, ok := o.isbns[book.ISBN13]; ok && book.ISBN13 != "" {
+		return true
+	}
+	return o.hasTitle(book.Title)
+}
+
+func (o *ownedBooks) hasTitle(title string) bool {
+	key := titleKey(title)
+	if key == "" {
+		return false
+	}
+	_, ok := o.titles[key]
+	return ok
+}
+
+func titleKey(title string) string {
+	cleaned := isbn.NormalizeTitle(title)
+	if cleaned == "" {
+		cleaned = title
+	}
+	return strings.ToLower(strings.Join(strings.Fields(cleaned), ""))
+}
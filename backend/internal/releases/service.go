@@ -0,0 +1,209 @@
+package releases
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/isbn"
+	"book_manager/backend/internal/repository"
+)
+
+// Provider は書誌メタデータの提供元です。シリーズ名と著者で新しい巻を検索します。
+type Provider interface {
+	SearchSeries(seriesName, author string) ([]domain.Book, error)
+}
+
+type Service struct {
+	repo      repository.ReleaseRepository
+	provider  Provider
+	favorites repository.FavoriteRepository
+	series    repository.SeriesRepository
+	userBooks repository.UserBookRepository
+	books     repository.BookRepository
+}
+
+func NewService(
+	repo repository.ReleaseRepository,
+	provider Provider,
+	favorites repository.FavoriteRepository,
+	series repository.SeriesRepository,
+	userBooks repository.UserBookRepository,
+	books repository.BookRepository,
+) *Service {
+	return &Service{
+		repo:      repo,
+		provider:  provider,
+		favorites: favorites,
+		series:    series,
+		userBooks: userBooks,
+		books:     books,
+	}
+}
+
+// Refresh はお気に入り登録されている全シリーズについて発売予定を問い合わせ、保存します。
+// 保存（更新）した件数を返します。
+func (s *Service) Refresh() int {
+	today := time.Now().Format("2006-01-02")
+	if err := s.repo.DeleteBefore(today); err != nil {
+		log.Printf("release cleanup error: %v", err)
+	}
+	if s.provider == nil {
+		return 0
+	}
+	seriesByID := make(map[string]domain.Series)
+	for _, item := range s.series.List() {
+		seriesByID[item.ID] = item
+	}
+	seen := make(map[string]struct{})
+	stored := 0
+	for _, fav := range s.favorites.ListByType("series") {
+		if _, ok := seen[fav.SeriesID]; ok {
+			continue
+		}
+		seen[fav.SeriesID] = struct{}{}
+		item, ok := seriesByID[fav.SeriesID]
+		if !ok {
+			continue
+		}
+		stored += s.refreshSeries(item, today)
+	}
+	return stored
+}
+
+func (s *Service) refreshSeries(item domain.Series, today string) int {
+	owned := s.seriesBooks(item.ID)
+	ownedISBNs := make(map[string]struct{}, len(owned))
+	for _, book := range owned {
+		if book.ISBN13 != "" {
+			ownedISBNs[book.ISBN13] = struct{}{}
+		}
+	}
+	candidates, err := s.provider.SearchSeries(item.Name, primaryAuthor(owned))
+	if err != nil {
+		log.Printf("release search error: series=%s err=%v", item.ID, err)
+		return 0
+	}
+	stored := 0
+	for _, book := range candidates {
+		if !isISODate(book.PublishedDate) || book.PublishedDate < today {
+			continue
+		}
+		if _, ok := ownedISBNs[book.ISBN13]; ok && book.ISBN13 != "" {
+			continue
+		}
+		if !sameSeries(book, item) {
+			continue
+		}
+		volume := isbn.ExtractVolume(book.Title)
+		release := domain.Release{
+			ID:           idgen.NewRelease(),
+			SeriesID:     item.ID,
+			ISBN13:       book.ISBN13,
+			Title:        book.Title,
+			Authors:      book.Authors,
+			Publisher:    book.Publisher,
+			VolumeLabel:  volume.Label,
+			VolumeNumber: isbn.VolumeNumberOf(volume),
+			ReleaseDate:  book.PublishedDate,
+			ThumbnailURL: book.ThumbnailURL,
+			Source:       book.Source,
+			DiscoveredAt: time.Now(),
+		}
+		if _, err := s.repo.Upsert(release); err != nil {
+			log.Printf("release store error: series=%s err=%v", item.ID, err)
+			continue
+		}
+		stored++
+	}
+	return stored
+}
+
+// ListUpcoming はユーザーがお気に入り登録したシリーズの発売予定を日付順に返します。
+// すでに所蔵している ISBN は除外します。
+func (s *Service) ListUpcoming(userID string) []domain.Release {
+	seriesIDs := make([]string, 0)
+	for _, fav := range s.favorites.ListByUser(userID) {
+		if fav.Type == "series" && fav.SeriesID != "" {
+			seriesIDs = append(seriesIDs, fav.SeriesID)
+		}
+	}
+	if len(seriesIDs) == 0 {
+		return []domain.Release{}
+	}
+	userItems := s.userBooks.ListByUser(userID)
+	bookIDs := make([]string, 0, len(userItems))
+	for _, item := range userItems {
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	ownedISBNs := make(map[string]struct{})
+	for _, book := range s.books.ListByIDs(bookIDs) {
+		if book.ISBN13 != "" {
+			ownedISBNs[book.ISBN13] = struct{}{}
+		}
+	}
+	today := time.Now().Format("2006-01-02")
+	items := make([]domain.Release, 0)
+	for _, release := range s.repo.ListBySeriesIDs(seriesIDs, today) {
+		if _, ok := ownedISBNs[release.ISBN13]; ok && release.ISBN13 != "" {
+			continue
+		}
+		items = append(items, release)
+	}
+	return items
+}
+
+func (s *Service) seriesBooks(seriesID string) []domain.Book {
+	items := s.userBooks.ListBySeriesID(seriesID)
+	bookIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	return s.books.ListByIDs(bookIDs)
+}
+
+// primaryAuthor は所蔵書籍で最も多く登場する著者を返します。
+func primaryAuthor(books []domain.Book) string {
+	counts := make(map[string]int)
+	for _, book := range books {
+		for _, author := range book.Authors {
+			author = strings.TrimSpace(author)
+			if author != "" {
+				counts[author]++
+			}
+		}
+	}
+	authors := make([]string, 0, len(counts))
+	for author := range counts {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if counts[authors[i]] != counts[authors[j]] {
+			return counts[authors[i]] > counts[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+	if len(authors) == 0 {
+		return ""
+	}
+	return authors[0]
+}
+
+func sameSeries(book domain.Book, item domain.Series) bool {
+	name := isbn.NormalizeSeriesName(book.SeriesName)
+	if name == "" {
+		name = isbn.NormalizeSeriesName(book.Title)
+	}
+	return strings.EqualFold(name, item.Name)
+}
+
+func isISODate(value string) bool {
+	if len(value) != 10 {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
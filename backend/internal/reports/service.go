@@ -174,6 +174,172 @@ func (s *Service) SendAdminInvitation(invitation AdminInvitationEmail) {
 	s.sendMail(invitation.To, subject, body)
 }
 
+type EmailVerificationEmail struct {
+	To        string
+	UserID    string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// SendEmailVerification はローカル認証モードのメールアドレス確認メールを送信します。
+func (s *Service) SendEmailVerification(verification EmailVerificationEmail) {
+	if strings.TrimSpace(verification.To) == "" {
+		return
+	}
+
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s",
+		strings.TrimSuffix(s.frontendURL, "/"),
+		url.QueryEscape(verification.Token),
+	)
+
+	data := map[string]interface{}{
+		"UserID":    verification.UserID,
+		"Email":     verification.To,
+		"ExpiresAt": verification.ExpiresAt.Format("2006-01-02 15:04:05"),
+		"VerifyURL": verifyURL,
+	}
+
+	subject, err := s.loadTemplate("email_verification_subject.txt", data)
+	if err != nil {
+		log.Printf("failed to load email_verification_subject template: %v", err)
+		subject = "[BookManager] メールアドレスの確認"
+	}
+
+	body, err := s.loadTemplate("email_verification_body.txt", data)
+	if err != nil {
+		log.Printf("failed to load email_verification_body template: %v", err)
+		body = fmt.Sprintf(
+			"以下のリンクからメールアドレスを確認してください。\n\n%s\n\n有効期限: %s\n",
+			verifyURL,
+			verification.ExpiresAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	s.sendMail(verification.To, subject, body)
+}
+
+type EmailChangeConfirmationEmail struct {
+	To        string
+	UserID    string
+	OldEmail  string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// SendEmailChangeConfirmation はメールアドレス変更の確認リンクを新しいアドレスに送信します。
+func (s *Service) SendEmailChangeConfirmation(confirmation EmailChangeConfirmationEmail) {
+	if strings.TrimSpace(confirmation.To) == "" {
+		return
+	}
+
+	confirmURL := fmt.Sprintf("%s/confirm-email-change?token=%s",
+		strings.TrimSuffix(s.frontendURL, "/"),
+		url.QueryEscape(confirmation.Token),
+	)
+
+	data := map[string]interface{}{
+		"UserID":     confirmation.UserID,
+		"Email":      confirmation.To,
+		"OldEmail":   confirmation.OldEmail,
+		"ExpiresAt":  confirmation.ExpiresAt.Format("2006-01-02 15:04:05"),
+		"ConfirmURL": confirmURL,
+	}
+
+	subject, err := s.loadTemplate("email_change_confirmation_subject.txt", data)
+	if err != nil {
+		log.Printf("failed to load email_change_confirmation_subject template: %v", err)
+		subject = "[BookManager] メールアドレス変更の確認"
+	}
+
+	body, err := s.loadTemplate("email_change_confirmation_body.txt", data)
+	if err != nil {
+		log.Printf("failed to load email_change_confirmation_body template: %v", err)
+		body = fmt.Sprintf(
+			"以下のリンクからメールアドレスの変更を完了してください。\n\n%s\n\n有効期限: %s\n",
+			confirmURL,
+			confirmation.ExpiresAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	s.sendMail(confirmation.To, subject, body)
+}
+
+// SecurityEvent はセキュリティ通知メールを送るアカウントの操作です。
+type SecurityEvent string
+
+const (
+	SecurityEventNewSession           SecurityEvent = "new_session"
+	SecurityEventPasswordChanged      SecurityEvent = "password_changed"
+	SecurityEventEmailChangeRequested SecurityEvent = "email_change_requested"
+	SecurityEventEmailChanged         SecurityEvent = "email_changed"
+	SecurityEventRoleGranted          SecurityEvent = "role_granted"
+)
+
+// securityEventFallbacks はテンプレートを読み込めない場合の件名と本文の 1 行目です。
+var securityEventFallbacks = map[SecurityEvent][2]string{
+	SecurityEventNewSession:           {"[BookManager] 新しいログイン", "アカウントに新しい端末からログインがありました。"},
+	SecurityEventPasswordChanged:      {"[BookManager] パスワードの変更", "アカウントのパスワードが変更されました。"},
+	SecurityEventEmailChangeRequested: {"[BookManager] メールアドレス変更の申請", "アカウントのメールアドレス変更が申請されました。"},
+	SecurityEventEmailChanged:         {"[BookManager] メールアドレスの変更", "アカウントのメールアドレスが変更されました。"},
+	SecurityEventRoleGranted:          {"[BookManager] 管理権限の付与", "アカウントに管理機能のロールが付与されました。"},
+}
+
+type SecurityNotice struct {
+	To         string
+	UserID     string
+	Event      SecurityEvent
+	OccurredAt time.Time
+	DeviceName string
+	IP         string
+	UserAgent  string
+	NewEmail   string
+	Role       string
+}
+
+// SendSecurityNotice はアカウントのセキュリティに関わる操作をユーザーに通知します。
+// テンプレートは security_<event>_subject.txt / security_<event>_body.txt です。
+func (s *Service) SendSecurityNotice(notice SecurityNotice) {
+	if strings.TrimSpace(notice.To) == "" {
+		return
+	}
+	fallback, ok := securityEventFallbacks[notice.Event]
+	if !ok {
+		log.Printf("unknown security event: %s", notice.Event)
+		return
+	}
+
+	data := map[string]interface{}{
+		"UserID":     notice.UserID,
+		"Email":      notice.To,
+		"OccurredAt": notice.OccurredAt.Format("2006-01-02 15:04:05"),
+		"DeviceName": notice.DeviceName,
+		"IP":         notice.IP,
+		"UserAgent":  notice.UserAgent,
+		"NewEmail":   notice.NewEmail,
+		"Role":       notice.Role,
+	}
+	name := "security_" + string(notice.Event)
+
+	subject, err := s.loadTemplate(name+"_subject.txt", data)
+	if err != nil {
+		log.Printf("failed to load %s_subject template: %v", name, err)
+		subject = fallback[0]
+	}
+
+	body, err := s.loadTemplate(name+"_body.txt", data)
+	if err != nil {
+		log.Printf("failed to load %s_body template: %v", name, err)
+		body = fmt.Sprintf(
+			"%s\n\nUserID: %s\n日時: %s\n\n心当たりがない場合は、すぐにパスワードを変更してください。\n",
+			fallback[1],
+			notice.UserID,
+			notice.OccurredAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	s.sendMail(notice.To, subject, body)
+}
+
 func (s *Service) sendMail(to, subject, body string) {
 	if strings.TrimSpace(to) == "" {
 		return
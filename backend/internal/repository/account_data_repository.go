@@ -0,0 +1,8 @@
+package repository
+
+// AccountDataRepository はアカウント削除時にユーザーに紐づくデータをまとめて削除します。
+type AccountDataRepository interface {
+	// DeleteAllForUser はユーザーのデータを削除し、テーブルごとの削除件数を返します。
+	// beforeCommit がエラーを返した場合は削除を取り消します（DB 実装のみ。メモリ実装では削除前に呼び出します）。
+	DeleteAllForUser(userID string, beforeCommit func() error) (map[string]int, error)
+}
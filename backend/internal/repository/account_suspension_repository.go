@@ -0,0 +1,10 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type AccountSuspensionRepository interface {
+	Get(userID string) (domain.AccountSuspension, bool)
+	Save(suspension domain.AccountSuspension) error
+	Delete(userID string) bool
+	List() []domain.AccountSuspension
+}
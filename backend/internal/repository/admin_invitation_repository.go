@@ -1,6 +1,10 @@
 package repository
 
-import "book_manager/backend/internal/domain"
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
 
 type AdminInvitationRepository interface {
 	Create(invitation domain.AdminInvitation) error
@@ -10,4 +14,5 @@ type AdminInvitationRepository interface {
 	List() []domain.AdminInvitation
 	Update(invitation domain.AdminInvitation) bool
 	Delete(id string) bool
+	DeleteExpiredBefore(cutoff time.Time) int
 }
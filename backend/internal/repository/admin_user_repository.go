@@ -6,5 +6,6 @@ type AdminUserRepository interface {
 	Create(adminUser domain.AdminUser) error
 	FindByUserID(userID string) (domain.AdminUser, bool)
 	List() []domain.AdminUser
+	UpdateRole(userID, role string) bool
 	Delete(userID string) bool
 }
@@ -0,0 +1,11 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type APITokenRepository interface {
+	Create(token domain.APIToken) error
+	FindByID(id string) (domain.APIToken, bool)
+	FindByHash(tokenHash string) (domain.APIToken, bool)
+	ListByUser(userID string) []domain.APIToken
+	Update(token domain.APIToken) bool
+}
@@ -6,7 +6,39 @@ import (
 	"book_manager/backend/internal/domain"
 )
 
+// AuditLogQuery は監査ログの検索条件です。空の項目・ゼロ値の日時では絞り込みません。
+type AuditLogQuery struct {
+	UserID   string
+	Entity   string
+	EntityID string
+	Action   string
+	From     time.Time // 以上
+	To       time.Time // 未満
+	// BeforeAt / BeforeID は前のページの最後の記録で、指定時はそれより後ろ（古い側）の記録を返します。
+	BeforeAt time.Time
+	BeforeID string
+	Limit    int
+}
+
 type AuditLogRepository interface {
 	Create(log domain.AuditLog) error
 	DeleteBefore(t time.Time) error
+	ListByUser(userID string) []domain.AuditLog
+	// LastActivityByUser はユーザーごとの最新の監査ログの日時を返します。
+	LastActivityByUser() map[string]time.Time
+	// Query は条件に一致する記録を新しい順（作成日時、同時刻は ID の降順）に最大 Limit 件返します。
+	Query(query AuditLogQuery) []domain.AuditLog
+	// Count は条件に一致する記録の件数を返します（カーソルと Limit は無視します）。
+	Count(query AuditLogQuery) int
+}
+
+// After はカーソル（BeforeAt / BeforeID）より後ろに並ぶ記録かどうかを返します。
+func (q AuditLogQuery) After(log domain.AuditLog) bool {
+	if q.BeforeAt.IsZero() {
+		return true
+	}
+	if log.CreatedAt.Equal(q.BeforeAt) {
+		return log.ID < q.BeforeID
+	}
+	return log.CreatedAt.Before(q.BeforeAt)
 }
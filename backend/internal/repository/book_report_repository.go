@@ -0,0 +1,12 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type BookReportRepository interface {
+	Create(report domain.BookReport) error
+	FindByID(id string) (domain.BookReport, bool)
+	// List は報告を新しい順に返します。status が空の場合はすべての状態を返します。
+	List(status string) []domain.BookReport
+	Update(report domain.BookReport) bool
+	DeleteByUser(userID string) int
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type CalendarTokenRepository interface {
+	Upsert(token domain.CalendarToken) error
+	FindByUserID(userID string) (domain.CalendarToken, bool)
+	FindByHash(tokenHash string) (domain.CalendarToken, bool)
+	Delete(userID string) bool
+}
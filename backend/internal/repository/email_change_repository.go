@@ -0,0 +1,11 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type EmailChangeRepository interface {
+	Create(change domain.EmailChange) error
+	FindByHash(tokenHash string) (domain.EmailChange, bool)
+	ListByUser(userID string) []domain.EmailChange
+	Update(change domain.EmailChange) bool
+	DeleteForUser(userID string) int
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type EmailVerificationRepository interface {
+	Create(verification domain.EmailVerification) error
+	FindByHash(tokenHash string) (domain.EmailVerification, bool)
+	MarkUsed(id string, at time.Time) bool
+	DeleteForUser(userID string) int
+}
@@ -6,5 +6,6 @@ type FavoriteRepository interface {
 	Create(favorite domain.Favorite) error
 	ListByUser(userID string) []domain.Favorite
 	ListBySeriesID(seriesID string) []domain.Favorite
+	ListByType(favoriteType string) []domain.Favorite
 	Delete(id string) bool
 }
@@ -0,0 +1,77 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type AccountDataRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountDataRepository(db *gorm.DB) *AccountDataRepository {
+	return &AccountDataRepository{db: db}
+}
+
+// accountDataTables はアカウント削除でユーザーの行を削除するテーブルです（admin_users と users は最後に削除します）。
+// books（書誌マスタ）と open_ai_usages（キーの予算計算に使用）は残します。
+var accountDataTables = []struct {
+	name  string
+	model any
+}{
+	{name: "user_books", model: &UserBook{}},
+	{name: "favorites", model: &Favorite{}},
+	{name: "next_to_buy_manuals", model: &NextToBuyManual{}},
+	{name: "recommendations", model: &Recommendation{}},
+	{name: "profile_settings", model: &ProfileSettings{}},
+	{name: "calendar_tokens", model: &CalendarToken{}},
+	{name: "email_verifications", model: &EmailVerification{}},
+	{name: "audit_logs", model: &AuditLog{}},
+	{name: "refresh_tokens", model: &RefreshToken{}},
+	{name: "sessions", model: &Session{}},
+	{name: "api_tokens", model: &APIToken{}},
+	{name: "email_changes", model: &EmailChange{}},
+	{name: "account_suspensions", model: &AccountSuspension{}},
+	{name: "book_reports", model: &BookReport{}},
+}
+
+func (r *AccountDataRepository) DeleteAllForUser(userID string, beforeCommit func() error) (map[string]int, error) {
+	counts := make(map[string]int, len(accountDataTables)+1)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range accountDataTables {
+			result := tx.Where("user_id = ?", userID).Delete(table.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			counts[table.name] = int(result.RowsAffected)
+		}
+		// ロールは公開ユーザー ID で保存しているため、同じ ID で再登録したユーザーに引き継がれないよう削除する
+		var publicIDs []string
+		if err := tx.Model(&User{}).Where("id = ?", userID).Pluck("user_id", &publicIDs).Error; err != nil {
+			return err
+		}
+		counts["admin_users"] = 0
+		if len(publicIDs) > 0 && publicIDs[0] != "" {
+			result := tx.Where("user_id = ?", publicIDs[0]).Delete(&AdminUser{})
+			if result.Error != nil {
+				return result.Error
+			}
+			counts["admin_users"] = int(result.RowsAffected)
+		}
+		result := tx.Delete(&User{}, "id = ?", userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		counts["users"] = int(result.RowsAffected)
+		if beforeCommit != nil {
+			return beforeCommit()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+var _ repository.AccountDataRepository = (*AccountDataRepository)(nil)
@@ -0,0 +1,61 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type AccountSuspensionRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountSuspensionRepository(db *gorm.DB) *AccountSuspensionRepository {
+	return &AccountSuspensionRepository{db: db}
+}
+
+func (r *AccountSuspensionRepository) Get(userID string) (domain.AccountSuspension, bool) {
+	var model AccountSuspension
+	if err := r.db.First(&model, "user_id = ?", userID).Error; err != nil {
+		return domain.AccountSuspension{}, false
+	}
+	return toDomainAccountSuspension(model), true
+}
+
+func (r *AccountSuspensionRepository) Save(suspension domain.AccountSuspension) error {
+	model := AccountSuspension{
+		UserID:      suspension.UserID,
+		Reason:      suspension.Reason,
+		SuspendedBy: suspension.SuspendedBy,
+		SuspendedAt: suspension.SuspendedAt,
+	}
+	return r.db.Save(&model).Error
+}
+
+func (r *AccountSuspensionRepository) Delete(userID string) bool {
+	result := r.db.Delete(&AccountSuspension{}, "user_id = ?", userID)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (r *AccountSuspensionRepository) List() []domain.AccountSuspension {
+	var models []AccountSuspension
+	if err := r.db.Find(&models).Error; err != nil {
+		return []domain.AccountSuspension{}
+	}
+	items := make([]domain.AccountSuspension, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainAccountSuspension(model))
+	}
+	return items
+}
+
+func toDomainAccountSuspension(model AccountSuspension) domain.AccountSuspension {
+	return domain.AccountSuspension{
+		UserID:      model.UserID,
+		Reason:      model.Reason,
+		SuspendedBy: model.SuspendedBy,
+		SuspendedAt: model.SuspendedAt,
+	}
+}
+
+var _ repository.AccountSuspensionRepository = (*AccountSuspensionRepository)(nil)
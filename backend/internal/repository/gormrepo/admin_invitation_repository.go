@@ -1,6 +1,8 @@
 package gormrepo
 
 import (
+	"time"
+
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/repository"
 	"gorm.io/gorm"
@@ -83,6 +85,14 @@ func (r *AdminInvitationRepository) Delete(id string) bool {
 	return result.Error == nil && result.RowsAffected > 0
 }
 
+func (r *AdminInvitationRepository) DeleteExpiredBefore(cutoff time.Time) int {
+	result := r.db.Delete(&AdminInvitation{}, "used_at IS NULL AND expires_at < ?", cutoff)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
 func toDomainInvitation(model AdminInvitation) domain.AdminInvitation {
 	return domain.AdminInvitation{
 		ID:        model.ID,
@@ -18,6 +18,7 @@ func (r *AdminUserRepository) Create(adminUser domain.AdminUser) error {
 	model := AdminUser{
 		ID:        adminUser.ID,
 		UserID:    adminUser.UserID,
+		Role:      adminUser.Role,
 		CreatedBy: adminUser.CreatedBy,
 		CreatedAt: adminUser.CreatedAt,
 	}
@@ -44,6 +45,11 @@ func (r *AdminUserRepository) List() []domain.AdminUser {
 	return items
 }
 
+func (r *AdminUserRepository) UpdateRole(userID, role string) bool {
+	result := r.db.Model(&AdminUser{}).Where("user_id = ?", userID).Update("role", role)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
 func (r *AdminUserRepository) Delete(userID string) bool {
 	result := r.db.Delete(&AdminUser{}, "user_id = ?", userID)
 	return result.Error == nil && result.RowsAffected > 0
@@ -53,6 +59,7 @@ func toDomainAdminUser(model AdminUser) domain.AdminUser {
 	return domain.AdminUser{
 		ID:        model.ID,
 		UserID:    model.UserID,
+		Role:      model.Role,
 		CreatedBy: model.CreatedBy,
 		CreatedAt: model.CreatedAt,
 	}
@@ -0,0 +1,88 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) *APITokenRepository {
+	return &APITokenRepository{db: db}
+}
+
+func (r *APITokenRepository) Create(token domain.APIToken) error {
+	model := toModelAPIToken(token)
+	return r.db.Create(&model).Error
+}
+
+func (r *APITokenRepository) FindByID(id string) (domain.APIToken, bool) {
+	var model APIToken
+	if err := r.db.First(&model, "id = ?", id).Error; err != nil {
+		return domain.APIToken{}, false
+	}
+	return toDomainAPIToken(model), true
+}
+
+func (r *APITokenRepository) FindByHash(tokenHash string) (domain.APIToken, bool) {
+	var model APIToken
+	if err := r.db.First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		return domain.APIToken{}, false
+	}
+	return toDomainAPIToken(model), true
+}
+
+func (r *APITokenRepository) ListByUser(userID string) []domain.APIToken {
+	var models []APIToken
+	if err := r.db.Where("user_id = ?", userID).Order("created_at desc").Find(&models).Error; err != nil {
+		return nil
+	}
+	items := make([]domain.APIToken, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainAPIToken(model))
+	}
+	return items
+}
+
+func (r *APITokenRepository) Update(token domain.APIToken) bool {
+	model := toModelAPIToken(token)
+	if err := r.db.Save(&model).Error; err != nil {
+		return false
+	}
+	return true
+}
+
+func toModelAPIToken(token domain.APIToken) APIToken {
+	return APIToken{
+		ID:         token.ID,
+		UserID:     token.UserID,
+		Name:       token.Name,
+		Scope:      token.Scope,
+		TokenHash:  token.TokenHash,
+		Prefix:     token.Prefix,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+	}
+}
+
+func toDomainAPIToken(model APIToken) domain.APIToken {
+	return domain.APIToken{
+		ID:         model.ID,
+		UserID:     model.UserID,
+		Name:       model.Name,
+		Scope:      model.Scope,
+		TokenHash:  model.TokenHash,
+		Prefix:     model.Prefix,
+		CreatedAt:  model.CreatedAt,
+		ExpiresAt:  model.ExpiresAt,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+	}
+}
+
+var _ repository.APITokenRepository = (*APITokenRepository)(nil)
@@ -41,4 +41,99 @@ func (r *AuditLogRepository) DeleteBefore(t time.Time) error {
 	return r.db.Delete(&AuditLog{}, "created_at < ?", t).Error
 }
 
+func (r *AuditLogRepository) ListByUser(userID string) []domain.AuditLog {
+	var models []AuditLog
+	if err := r.db.Where("user_id = ?", userID).Order("created_at asc").Find(&models).Error; err != nil {
+		return nil
+	}
+	items := make([]domain.AuditLog, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainAuditLog(model))
+	}
+	return items
+}
+
+func (r *AuditLogRepository) LastActivityByUser() map[string]time.Time {
+	var rows []struct {
+		UserID string
+		Latest time.Time
+	}
+	latest := make(map[string]time.Time)
+	if err := r.db.Model(&AuditLog{}).Select("user_id, max(created_at) as latest").Where("user_id <> ''").Group("user_id").Scan(&rows).Error; err != nil {
+		return latest
+	}
+	for _, row := range rows {
+		latest[row.UserID] = row.Latest
+	}
+	return latest
+}
+
+func (r *AuditLogRepository) Query(query repository.AuditLogQuery) []domain.AuditLog {
+	tx := r.filtered(query)
+	if !query.BeforeAt.IsZero() {
+		tx = tx.Where("(created_at < ? OR (created_at = ? AND id < ?))", query.BeforeAt, query.BeforeAt, query.BeforeID)
+	}
+	tx = tx.Order("created_at desc").Order("id desc")
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+	var models []AuditLog
+	if err := tx.Find(&models).Error; err != nil {
+		return nil
+	}
+	items := make([]domain.AuditLog, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainAuditLog(model))
+	}
+	return items
+}
+
+func (r *AuditLogRepository) Count(query repository.AuditLogQuery) int {
+	var count int64
+	if err := r.filtered(query).Count(&count).Error; err != nil {
+		return 0
+	}
+	return int(count)
+}
+
+// filtered はカーソル以外の検索条件を適用したクエリを返します。
+func (r *AuditLogRepository) filtered(query repository.AuditLogQuery) *gorm.DB {
+	tx := r.db.Model(&AuditLog{})
+	if query.UserID != "" {
+		tx = tx.Where("user_id = ?", query.UserID)
+	}
+	if query.Entity != "" {
+		tx = tx.Where("entity = ?", query.Entity)
+	}
+	if query.EntityID != "" {
+		tx = tx.Where("entity_id = ?", query.EntityID)
+	}
+	if query.Action != "" {
+		tx = tx.Where("action = ?", query.Action)
+	}
+	if !query.From.IsZero() {
+		tx = tx.Where("created_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		tx = tx.Where("created_at < ?", query.To)
+	}
+	return tx
+}
+
+func toDomainAuditLog(model AuditLog) domain.AuditLog {
+	payload := map[string]any{}
+	_ = json.Unmarshal(model.Payload, &payload)
+	return domain.AuditLog{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Action:    model.Action,
+		Entity:    model.Entity,
+		EntityID:  model.EntityID,
+		Payload:   payload,
+		IP:        model.IP,
+		UserAgent: model.UserAgent,
+		CreatedAt: model.CreatedAt,
+	}
+}
+
 var _ repository.AuditLogRepository = (*AuditLogRepository)(nil)
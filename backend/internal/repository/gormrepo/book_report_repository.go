@@ -0,0 +1,91 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BookReportRepository struct {
+	db *gorm.DB
+}
+
+func NewBookReportRepository(db *gorm.DB) *BookReportRepository {
+	return &BookReportRepository{db: db}
+}
+
+func (r *BookReportRepository) Create(report domain.BookReport) error {
+	model := toBookReportModel(report)
+	return r.db.Create(&model).Error
+}
+
+func (r *BookReportRepository) FindByID(id string) (domain.BookReport, bool) {
+	var model BookReport
+	if err := r.db.First(&model, "id = ?", id).Error; err != nil {
+		return domain.BookReport{}, false
+	}
+	return toDomainBookReport(model), true
+}
+
+func (r *BookReportRepository) List(status string) []domain.BookReport {
+	query := r.db.Order("created_at desc")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var models []BookReport
+	if err := query.Find(&models).Error; err != nil {
+		return []domain.BookReport{}
+	}
+	items := make([]domain.BookReport, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainBookReport(model))
+	}
+	return items
+}
+
+func (r *BookReportRepository) Update(report domain.BookReport) bool {
+	result := r.db.Model(&BookReport{}).Where("id = ?", report.ID).Updates(map[string]interface{}{
+		"status":     report.Status,
+		"handled_by": report.HandledBy,
+		"handled_at": report.HandledAt,
+	})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (r *BookReportRepository) DeleteByUser(userID string) int {
+	result := r.db.Where("user_id = ?", userID).Delete(&BookReport{})
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+func toBookReportModel(report domain.BookReport) BookReport {
+	return BookReport{
+		ID:         report.ID,
+		UserID:     report.UserID,
+		BookID:     report.BookID,
+		Suggestion: report.Suggestion,
+		Note:       report.Note,
+		Status:     report.Status,
+		HandledBy:  report.HandledBy,
+		HandledAt:  report.HandledAt,
+		CreatedAt:  report.CreatedAt,
+	}
+}
+
+func toDomainBookReport(model BookReport) domain.BookReport {
+	return domain.BookReport{
+		ID:         model.ID,
+		UserID:     model.UserID,
+		BookID:     model.BookID,
+		Suggestion: model.Suggestion,
+		Note:       model.Note,
+		Status:     model.Status,
+		HandledBy:  model.HandledBy,
+		HandledAt:  model.HandledAt,
+		CreatedAt:  model.CreatedAt,
+	}
+}
+
+var _ repository.BookReportRepository = (*BookReportRepository)(nil)
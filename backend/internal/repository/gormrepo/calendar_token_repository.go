@@ -0,0 +1,78 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type CalendarTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarTokenRepository(db *gorm.DB) *CalendarTokenRepository {
+	return &CalendarTokenRepository{db: db}
+}
+
+func (r *CalendarTokenRepository) Upsert(token domain.CalendarToken) error {
+	model := CalendarToken{
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		CreatedAt: token.CreatedAt,
+	}
+	return r.db.Save(&model).Error
+}
+
+func (r *CalendarTokenRepository) FindByUserID(userID string) (domain.CalendarToken, bool) {
+	var model CalendarToken
+	if err := r.db.First(&model, "user_id = ?", userID).Error; err != nil {
+		return domain.CalendarToken{}, false
+	}
+	return toDomainCalendarToken(model), true
+}
+
+func (r *CalendarTokenRepository) FindByHash(tokenHash string) (domain.CalendarToken, bool) {
+	var model CalendarToken
+	if err := r.db.First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		return domain.CalendarToken{}, false
+	}
+	return toDomainCalendarToken(model), true
+}
+
+func (r *CalendarTokenRepository) Delete(userID string) bool {
+	result := r.db.Delete(&CalendarToken{}, "user_id = ?", userID)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func toDomainCalendarToken(model CalendarToken) domain.CalendarToken {
+	return domain.CalendarToken{
+		UserID:    model.UserID,
+		TokenHash: model.TokenHash,
+		CreatedAt: model.CreatedAt,
+	}
+}
+
+// MigrateCalendarTokenHashes は平文で保存していた旧形式のトークンをハッシュに置き換え、token 列を削除します。
+// 発行済みの購読 URL はそのまま使えます。
+func MigrateCalendarTokenHashes(db *gorm.DB, hash func(string) string) error {
+	if !db.Migrator().HasColumn(&CalendarToken{}, "token") {
+		return nil
+	}
+	var rows []struct {
+		UserID string
+		Token  string
+	}
+	if err := db.Table("calendar_tokens").Select("user_id, token").Where("token IS NOT NULL AND token <> ''").Scan(&rows).Error; err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := tx.Model(&CalendarToken{}).Where("user_id = ?", row.UserID).Update("token_hash", hash(row.Token)).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&CalendarToken{}, "token")
+	})
+}
+
+var _ repository.CalendarTokenRepository = (*CalendarTokenRepository)(nil)
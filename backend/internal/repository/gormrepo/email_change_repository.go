@@ -0,0 +1,81 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type EmailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+func (r *EmailChangeRepository) Create(change domain.EmailChange) error {
+	model := toModelEmailChange(change)
+	return r.db.Create(&model).Error
+}
+
+func (r *EmailChangeRepository) FindByHash(tokenHash string) (domain.EmailChange, bool) {
+	var model EmailChange
+	if err := r.db.First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		return domain.EmailChange{}, false
+	}
+	return toDomainEmailChange(model), true
+}
+
+func (r *EmailChangeRepository) ListByUser(userID string) []domain.EmailChange {
+	var models []EmailChange
+	if err := r.db.Where("user_id = ?", userID).Order("created_at desc").Find(&models).Error; err != nil {
+		return []domain.EmailChange{}
+	}
+	items := make([]domain.EmailChange, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainEmailChange(model))
+	}
+	return items
+}
+
+func (r *EmailChangeRepository) Update(change domain.EmailChange) bool {
+	model := toModelEmailChange(change)
+	return r.db.Save(&model).Error == nil
+}
+
+func (r *EmailChangeRepository) DeleteForUser(userID string) int {
+	result := r.db.Delete(&EmailChange{}, "user_id = ?", userID)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+func toModelEmailChange(change domain.EmailChange) EmailChange {
+	return EmailChange{
+		ID:          change.ID,
+		UserID:      change.UserID,
+		OldEmail:    change.OldEmail,
+		NewEmail:    change.NewEmail,
+		TokenHash:   change.TokenHash,
+		ExpiresAt:   change.ExpiresAt,
+		ConfirmedAt: change.ConfirmedAt,
+		CreatedAt:   change.CreatedAt,
+	}
+}
+
+func toDomainEmailChange(model EmailChange) domain.EmailChange {
+	return domain.EmailChange{
+		ID:          model.ID,
+		UserID:      model.UserID,
+		OldEmail:    model.OldEmail,
+		NewEmail:    model.NewEmail,
+		TokenHash:   model.TokenHash,
+		ExpiresAt:   model.ExpiresAt,
+		ConfirmedAt: model.ConfirmedAt,
+		CreatedAt:   model.CreatedAt,
+	}
+}
+
+var _ repository.EmailChangeRepository = (*EmailChangeRepository)(nil)
@@ -0,0 +1,63 @@
+package gormrepo
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type EmailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+func (r *EmailVerificationRepository) Create(verification domain.EmailVerification) error {
+	model := EmailVerification{
+		ID:        verification.ID,
+		UserID:    verification.UserID,
+		Email:     verification.Email,
+		TokenHash: verification.TokenHash,
+		ExpiresAt: verification.ExpiresAt,
+		UsedAt:    verification.UsedAt,
+		CreatedAt: verification.CreatedAt,
+	}
+	return r.db.Create(&model).Error
+}
+
+func (r *EmailVerificationRepository) FindByHash(tokenHash string) (domain.EmailVerification, bool) {
+	var model EmailVerification
+	if err := r.db.First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		return domain.EmailVerification{}, false
+	}
+	return domain.EmailVerification{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Email:     model.Email,
+		TokenHash: model.TokenHash,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, true
+}
+
+func (r *EmailVerificationRepository) MarkUsed(id string, at time.Time) bool {
+	result := r.db.Model(&EmailVerification{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (r *EmailVerificationRepository) DeleteForUser(userID string) int {
+	result := r.db.Delete(&EmailVerification{}, "user_id = ?", userID)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+var _ repository.EmailVerificationRepository = (*EmailVerificationRepository)(nil)
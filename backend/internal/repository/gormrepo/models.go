@@ -7,19 +7,22 @@ import (
 )
 
 type User struct {
-	ID           string `gorm:"primaryKey"`
-	Email        string `gorm:"uniqueIndex"`
-	UserID       string `gorm:"column:user_id;uniqueIndex"`
-	DisplayName  string
-	PasswordHash string
+	ID            string `gorm:"primaryKey"`
+	Email         string `gorm:"uniqueIndex"`
+	UserID        string `gorm:"column:user_id;uniqueIndex"`
+	DisplayName   string
+	PasswordHash  string
+	EmailVerified bool
 }
 
 type ProfileSettings struct {
-	UserID        string `gorm:"primaryKey"`
-	Visibility    string
-	OpenAIEnabled bool
-	OpenAIModel   string
-	OpenAIAPIKey  string
+	UserID         string `gorm:"primaryKey"`
+	Visibility     string
+	OpenAIEnabled  bool
+	OpenAIModel    string
+	OpenAIAPIKey   string
+	MonthlyBudget  int
+	BudgetCurrency string
 }
 
 type Book struct {
@@ -37,14 +40,18 @@ type Book struct {
 }
 
 type UserBook struct {
-	ID           string `gorm:"primaryKey"`
-	UserID       string `gorm:"uniqueIndex:idx_user_book"`
-	BookID       string `gorm:"uniqueIndex:idx_user_book"`
-	Note         string
-	AcquiredAt   string
-	SeriesID     string
-	VolumeNumber *int
-	SeriesSource string
+	ID            string `gorm:"primaryKey"`
+	UserID        string `gorm:"uniqueIndex:idx_user_book"`
+	BookID        string `gorm:"uniqueIndex:idx_user_book"`
+	Note          string
+	AcquiredAt    string
+	SeriesID      string
+	VolumeNumber  *int
+	VolumeLabel   string
+	SeriesSource  string
+	PurchasePrice *int
+	Currency      string
+	Store         string
 }
 
 type Favorite struct {
@@ -56,12 +63,18 @@ type Favorite struct {
 }
 
 type NextToBuyManual struct {
-	ID           string `gorm:"primaryKey"`
-	UserID       string `gorm:"index"`
-	Title        string
-	SeriesName   string
-	VolumeNumber *int
-	Note         string
+	ID            string `gorm:"primaryKey"`
+	UserID        string `gorm:"index"`
+	Title         string
+	SeriesName    string
+	VolumeNumber  *int
+	Note          string
+	TargetDate    string
+	Priority      int
+	ExpectedPrice *int
+	Currency      string
+	Store         string
+	StoreURL      string
 }
 
 type Series struct {
@@ -71,10 +84,33 @@ type Series struct {
 }
 
 type OpenAIKey struct {
+	ID                    string `gorm:"primaryKey"`
+	Name                  string
+	APIKey                string
+	MonthlyBudgetMicroUSD int64
+	CreatedAt             time.Time `gorm:"index"`
+}
+
+type OpenAIUsage struct {
+	ID               string `gorm:"primaryKey"`
+	KeyID            string `gorm:"index"`
+	UserID           string `gorm:"index"`
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostMicroUSD     int64
+	CreatedAt        time.Time `gorm:"index"`
+}
+
+type Prompt struct {
 	ID        string `gorm:"primaryKey"`
-	Name      string
-	APIKey    string
-	CreatedAt time.Time `gorm:"index"`
+	Version   int    `gorm:"uniqueIndex"`
+	Content   string
+	Note      string
+	Active    bool `gorm:"index"`
+	CreatedBy string
+	CreatedAt time.Time
 }
 
 type Recommendation struct {
@@ -91,11 +127,22 @@ type IsbnCache struct {
 	FetchedAt time.Time      `gorm:"index"`
 }
 
+type SeriesGuessCache struct {
+	ISBN13       string `gorm:"primaryKey"`
+	IsSeries     bool
+	SeriesName   string
+	VolumeNumber int
+	Confidence   int
+	Model        string
+	PromptHash   string
+	CreatedAt    time.Time
+}
+
 type AuditLog struct {
 	ID        string `gorm:"primaryKey"`
 	UserID    string `gorm:"index"`
 	Action    string
-	Entity    string
+	Entity    string `gorm:"index"`
 	EntityID  string
 	Payload   datatypes.JSON `gorm:"type:jsonb"`
 	IP        string
@@ -118,6 +165,105 @@ type AdminInvitation struct {
 type AdminUser struct {
 	ID        string    `gorm:"primaryKey"`
 	UserID    string    `gorm:"uniqueIndex"`
+	Role      string    `gorm:"not null;default:admin"`
 	CreatedBy string    `gorm:"index"`
 	CreatedAt time.Time `gorm:"index"`
 }
+
+type Release struct {
+	ID           string `gorm:"primaryKey"`
+	SeriesID     string `gorm:"uniqueIndex:idx_release_series_key"`
+	DedupKey     string `gorm:"uniqueIndex:idx_release_series_key"`
+	ISBN13       string
+	Title        string
+	Authors      datatypes.JSON `gorm:"type:jsonb"`
+	Publisher    string
+	VolumeLabel  string
+	VolumeNumber *int
+	ReleaseDate  string `gorm:"index"`
+	ThumbnailURL string
+	Source       string
+	DiscoveredAt time.Time
+}
+
+type CalendarToken struct {
+	UserID    string    `gorm:"primaryKey"`
+	TokenHash string    `gorm:"uniqueIndex"`
+	CreatedAt time.Time
+}
+
+type RefreshToken struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string `gorm:"index"`
+	FamilyID   string `gorm:"index"`
+	TokenHash  string `gorm:"uniqueIndex"`
+	ExpiresAt  time.Time `gorm:"index"`
+	RevokedAt  *time.Time
+	ReplacedBy string
+	CreatedAt  time.Time
+}
+
+type EmailVerification struct {
+	ID        string `gorm:"primaryKey"`
+	UserID    string `gorm:"index"`
+	Email     string
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type Session struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string `gorm:"index"`
+	DeviceName string
+	UserAgent  string
+	IP         string
+	TokenHash  string `gorm:"uniqueIndex"`
+	CreatedAt  time.Time
+	LastUsedAt time.Time `gorm:"index"`
+	RevokedAt  *time.Time
+}
+
+type APIToken struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string `gorm:"index"`
+	Name       string
+	Scope      string
+	TokenHash  string `gorm:"uniqueIndex"`
+	Prefix     string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+type EmailChange struct {
+	ID          string `gorm:"primaryKey"`
+	UserID      string `gorm:"index"`
+	OldEmail    string
+	NewEmail    string
+	TokenHash   string `gorm:"uniqueIndex"`
+	ExpiresAt   time.Time
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+}
+
+type AccountSuspension struct {
+	UserID      string `gorm:"primaryKey"`
+	Reason      string
+	SuspendedBy string
+	SuspendedAt time.Time
+}
+
+type BookReport struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string `gorm:"index"`
+	BookID     string `gorm:"index"`
+	Suggestion string
+	Note       string
+	Status     string `gorm:"index"`
+	HandledBy  string
+	HandledAt  *time.Time
+	CreatedAt  time.Time `gorm:"index"`
+}
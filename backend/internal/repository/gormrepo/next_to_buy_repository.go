@@ -16,12 +16,18 @@ func NewNextToBuyRepository(db *gorm.DB) *NextToBuyRepository {
 
 func (r *NextToBuyRepository) Create(item domain.NextToBuyManual) error {
 	model := NextToBuyManual{
-		ID:           item.ID,
-		UserID:       item.UserID,
-		Title:        item.Title,
-		SeriesName:   item.SeriesName,
-		VolumeNumber: valueOrNilInt(item.VolumeNumber),
-		Note:         item.Note,
+		ID:            item.ID,
+		UserID:        item.UserID,
+		Title:         item.Title,
+		SeriesName:    item.SeriesName,
+		VolumeNumber:  valueOrNilInt(item.VolumeNumber),
+		Note:          item.Note,
+		TargetDate:    item.TargetDate,
+		Priority:      item.Priority,
+		ExpectedPrice: valueOrNilInt(item.ExpectedPrice),
+		Currency:      item.Currency,
+		Store:         item.Store,
+		StoreURL:      item.StoreURL,
 	}
 	if err := r.db.Create(&model).Error; err != nil {
 		return err
@@ -37,12 +43,18 @@ func (r *NextToBuyRepository) ListByUser(userID string) []domain.NextToBuyManual
 	items := make([]domain.NextToBuyManual, 0, len(models))
 	for _, model := range models {
 		items = append(items, domain.NextToBuyManual{
-			ID:           model.ID,
-			UserID:       model.UserID,
-			Title:        model.Title,
-			SeriesName:   model.SeriesName,
-			VolumeNumber: valueOrZeroInt(model.VolumeNumber),
-			Note:         model.Note,
+			ID:            model.ID,
+			UserID:        model.UserID,
+			Title:         model.Title,
+			SeriesName:    model.SeriesName,
+			VolumeNumber:  valueOrZeroInt(model.VolumeNumber),
+			Note:          model.Note,
+			TargetDate:    model.TargetDate,
+			Priority:      model.Priority,
+			ExpectedPrice: valueOrZeroInt(model.ExpectedPrice),
+			Currency:      model.Currency,
+			Store:         model.Store,
+			StoreURL:      model.StoreURL,
 		})
 	}
 	return items
@@ -54,23 +66,35 @@ func (r *NextToBuyRepository) FindByID(id string) (domain.NextToBuyManual, bool)
 		return domain.NextToBuyManual{}, false
 	}
 	return domain.NextToBuyManual{
-		ID:           model.ID,
-		UserID:       model.UserID,
-		Title:        model.Title,
-		SeriesName:   model.SeriesName,
-		VolumeNumber: valueOrZeroInt(model.VolumeNumber),
-		Note:         model.Note,
+		ID:            model.ID,
+		UserID:        model.UserID,
+		Title:         model.Title,
+		SeriesName:    model.SeriesName,
+		VolumeNumber:  valueOrZeroInt(model.VolumeNumber),
+		Note:          model.Note,
+		TargetDate:    model.TargetDate,
+		Priority:      model.Priority,
+		ExpectedPrice: valueOrZeroInt(model.ExpectedPrice),
+		Currency:      model.Currency,
+		Store:         model.Store,
+		StoreURL:      model.StoreURL,
 	}, true
 }
 
 func (r *NextToBuyRepository) Update(item domain.NextToBuyManual) bool {
 	model := NextToBuyManual{
-		ID:           item.ID,
-		UserID:       item.UserID,
-		Title:        item.Title,
-		SeriesName:   item.SeriesName,
-		VolumeNumber: valueOrNilInt(item.VolumeNumber),
-		Note:         item.Note,
+		ID:            item.ID,
+		UserID:        item.UserID,
+		Title:         item.Title,
+		SeriesName:    item.SeriesName,
+		VolumeNumber:  valueOrNilInt(item.VolumeNumber),
+		Note:          item.Note,
+		TargetDate:    item.TargetDate,
+		Priority:      item.Priority,
+		ExpectedPrice: valueOrNilInt(item.ExpectedPrice),
+		Currency:      item.Currency,
+		Store:         item.Store,
+		StoreURL:      item.StoreURL,
 	}
 	if err := r.db.Save(&model).Error; err != nil {
 		return false
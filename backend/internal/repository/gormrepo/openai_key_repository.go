@@ -1,25 +1,28 @@
 package gormrepo
 
 import (
+	"log"
+
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/repository"
+	"book_manager/backend/internal/secrets"
 	"gorm.io/gorm"
 )
 
+// OpenAIKeyRepository は API キーを cipher で暗号化して保存します。cipher が nil の場合は平文のままです。
 type OpenAIKeyRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cipher *secrets.Cipher
 }
 
-func NewOpenAIKeyRepository(db *gorm.DB) *OpenAIKeyRepository {
-	return &OpenAIKeyRepository{db: db}
+func NewOpenAIKeyRepository(db *gorm.DB, cipher *secrets.Cipher) *OpenAIKeyRepository {
+	return &OpenAIKeyRepository{db: db, cipher: cipher}
 }
 
 func (r *OpenAIKeyRepository) Create(key domain.OpenAIKey) error {
-	model := OpenAIKey{
-		ID:        key.ID,
-		Name:      key.Name,
-		APIKey:    key.APIKey,
-		CreatedAt: key.CreatedAt,
+	model, err := r.toModel(key)
+	if err != nil {
+		return err
 	}
 	return r.db.Create(&model).Error
 }
@@ -31,16 +34,46 @@ func (r *OpenAIKeyRepository) List() []domain.OpenAIKey {
 	}
 	items := make([]domain.OpenAIKey, 0, len(models))
 	for _, model := range models {
+		apiKey, err := r.cipher.Decrypt(model.APIKey)
+		if err != nil {
+			// 復号できないキーは空として返し、キーの選択対象から外す（保存値は Update で上書きしない）
+			log.Printf("openai key decrypt error: id=%s err=%v", model.ID, err)
+			apiKey = ""
+		}
 		items = append(items, domain.OpenAIKey{
-			ID:        model.ID,
-			Name:      model.Name,
-			APIKey:    model.APIKey,
-			CreatedAt: model.CreatedAt,
+			ID:                    model.ID,
+			Name:                  model.Name,
+			APIKey:                apiKey,
+			MonthlyBudgetMicroUSD: model.MonthlyBudgetMicroUSD,
+			CreatedAt:             model.CreatedAt,
 		})
 	}
 	return items
 }
 
+func (r *OpenAIKeyRepository) Update(key domain.OpenAIKey) bool {
+	var stored OpenAIKey
+	if err := r.db.First(&stored, "id = ?", key.ID).Error; err != nil {
+		return false
+	}
+	apiKey, err := sealSecret(r.cipher, stored.APIKey, key.APIKey)
+	if err != nil {
+		log.Printf("openai key encrypt error: id=%s err=%v", key.ID, err)
+		return false
+	}
+	model := OpenAIKey{
+		ID:                    key.ID,
+		Name:                  key.Name,
+		APIKey:                apiKey,
+		MonthlyBudgetMicroUSD: key.MonthlyBudgetMicroUSD,
+		CreatedAt:             key.CreatedAt,
+	}
+	if err := r.db.Save(&model).Error; err != nil {
+		return false
+	}
+	return true
+}
+
 func (r *OpenAIKeyRepository) Delete(id string) bool {
 	if err := r.db.Delete(&OpenAIKey{}, "id = ?", id).Error; err != nil {
 		return false
@@ -48,4 +81,18 @@ func (r *OpenAIKeyRepository) Delete(id string) bool {
 	return true
 }
 
+func (r *OpenAIKeyRepository) toModel(key domain.OpenAIKey) (OpenAIKey, error) {
+	apiKey, err := r.cipher.Encrypt(key.APIKey)
+	if err != nil {
+		return OpenAIKey{}, err
+	}
+	return OpenAIKey{
+		ID:                    key.ID,
+		Name:                  key.Name,
+		APIKey:                apiKey,
+		MonthlyBudgetMicroUSD: key.MonthlyBudgetMicroUSD,
+		CreatedAt:             key.CreatedAt,
+	}, nil
+}
+
 var _ repository.OpenAIKeyRepository = (*OpenAIKeyRepository)(nil)
@@ -0,0 +1,95 @@
+package gormrepo
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type OpenAIUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewOpenAIUsageRepository(db *gorm.DB) *OpenAIUsageRepository {
+	return &OpenAIUsageRepository{db: db}
+}
+
+func (r *OpenAIUsageRepository) Create(usage domain.OpenAIUsage) error {
+	model := OpenAIUsage{
+		ID:               usage.ID,
+		KeyID:            usage.KeyID,
+		UserID:           usage.UserID,
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostMicroUSD:     usage.CostMicroUSD,
+		CreatedAt:        usage.CreatedAt,
+	}
+	return r.db.Create(&model).Error
+}
+
+// openAIUsageSums は合計を求める列です。記録がなくても 0 になるよう coalesce します。
+const openAIUsageSums = "count(*) as requests, coalesce(sum(prompt_tokens), 0) as prompt_tokens, coalesce(sum(completion_tokens), 0) as completion_tokens, coalesce(sum(total_tokens), 0) as total_tokens, coalesce(sum(cost_micro_usd), 0) as cost_micro_usd"
+
+type openAIUsageTotalsRow struct {
+	GroupKey         string
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostMicroUSD     int64
+}
+
+func (row openAIUsageTotalsRow) totals() repository.OpenAIUsageTotals {
+	return repository.OpenAIUsageTotals{
+		Requests:         row.Requests,
+		PromptTokens:     row.PromptTokens,
+		CompletionTokens: row.CompletionTokens,
+		TotalTokens:      row.TotalTokens,
+		CostMicroUSD:     row.CostMicroUSD,
+	}
+}
+
+func (r *OpenAIUsageRepository) TotalsBetween(from, to time.Time, group repository.OpenAIUsageGroup) map[string]repository.OpenAIUsageTotals {
+	totals := make(map[string]repository.OpenAIUsageTotals)
+	// 列名を SQL に埋め込むため、既知の集計単位だけを受け付ける
+	switch group {
+	case repository.OpenAIUsageByKey, repository.OpenAIUsageByUser, repository.OpenAIUsageByModel:
+	default:
+		return totals
+	}
+	column := string(group)
+	var rows []openAIUsageTotalsRow
+	if err := r.db.Model(&OpenAIUsage{}).
+		Select(column+" as group_key, "+openAIUsageSums).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group(column).
+		Scan(&rows).Error; err != nil {
+		return totals
+	}
+	for _, row := range rows {
+		totals[row.GroupKey] = row.totals()
+	}
+	return totals
+}
+
+func (r *OpenAIUsageRepository) TotalsByUserSince(userID string, since time.Time) repository.OpenAIUsageTotals {
+	return r.sum(r.db.Where("user_id = ? AND created_at >= ?", userID, since))
+}
+
+func (r *OpenAIUsageRepository) TotalsByKeySince(keyID string, since time.Time) repository.OpenAIUsageTotals {
+	return r.sum(r.db.Where("key_id = ? AND created_at >= ?", keyID, since))
+}
+
+func (r *OpenAIUsageRepository) sum(query *gorm.DB) repository.OpenAIUsageTotals {
+	var row openAIUsageTotalsRow
+	if err := query.Model(&OpenAIUsage{}).Select(openAIUsageSums).Scan(&row).Error; err != nil {
+		return repository.OpenAIUsageTotals{}
+	}
+	return row.totals()
+}
+
+var _ repository.OpenAIUsageRepository = (*OpenAIUsageRepository)(nil)
@@ -1,17 +1,23 @@
 package gormrepo
 
 import (
+	"errors"
+	"log"
+
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/repository"
+	"book_manager/backend/internal/secrets"
 	"gorm.io/gorm"
 )
 
+// ProfileSettingsRepository はユーザーの OpenAI API キーを cipher で暗号化して保存します。
 type ProfileSettingsRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cipher *secrets.Cipher
 }
 
-func NewProfileSettingsRepository(db *gorm.DB) *ProfileSettingsRepository {
-	return &ProfileSettingsRepository{db: db}
+func NewProfileSettingsRepository(db *gorm.DB, cipher *secrets.Cipher) *ProfileSettingsRepository {
+	return &ProfileSettingsRepository{db: db, cipher: cipher}
 }
 
 func (r *ProfileSettingsRepository) Get(userID string) (domain.ProfileSettings, bool) {
@@ -19,22 +25,42 @@ func (r *ProfileSettingsRepository) Get(userID string) (domain.ProfileSettings,
 	if err := r.db.First(&model, "user_id = ?", userID).Error; err != nil {
 		return domain.ProfileSettings{}, false
 	}
+	apiKey, err := r.cipher.Decrypt(model.OpenAIAPIKey)
+	if err != nil {
+		// 復号できないキーは空として返す（保存値は Upsert で上書きしない）
+		log.Printf("profile settings decrypt error: user=%s err=%v", model.UserID, err)
+		apiKey = ""
+	}
 	return domain.ProfileSettings{
-		UserID:        model.UserID,
-		Visibility:    model.Visibility,
-		OpenAIEnabled: model.OpenAIEnabled,
-		OpenAIModel:   model.OpenAIModel,
-		OpenAIAPIKey:  model.OpenAIAPIKey,
+		UserID:         model.UserID,
+		Visibility:     model.Visibility,
+		OpenAIEnabled:  model.OpenAIEnabled,
+		OpenAIModel:    model.OpenAIModel,
+		OpenAIAPIKey:   apiKey,
+		MonthlyBudget:  model.MonthlyBudget,
+		BudgetCurrency: model.BudgetCurrency,
 	}, true
 }
 
 func (r *ProfileSettingsRepository) Upsert(settings domain.ProfileSettings) {
+	var stored ProfileSettings
+	if err := r.db.First(&stored, "user_id = ?", settings.UserID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("profile settings load error: user=%s err=%v", settings.UserID, err)
+		return
+	}
+	apiKey, err := sealSecret(r.cipher, stored.OpenAIAPIKey, settings.OpenAIAPIKey)
+	if err != nil {
+		log.Printf("profile settings encrypt error: user=%s err=%v", settings.UserID, err)
+		return
+	}
 	model := ProfileSettings{
-		UserID:        settings.UserID,
-		Visibility:    settings.Visibility,
-		OpenAIEnabled: settings.OpenAIEnabled,
-		OpenAIModel:   settings.OpenAIModel,
-		OpenAIAPIKey:  settings.OpenAIAPIKey,
+		UserID:         settings.UserID,
+		Visibility:     settings.Visibility,
+		OpenAIEnabled:  settings.OpenAIEnabled,
+		OpenAIModel:    settings.OpenAIModel,
+		OpenAIAPIKey:   apiKey,
+		MonthlyBudget:  settings.MonthlyBudget,
+		BudgetCurrency: settings.BudgetCurrency,
 	}
 	r.db.Save(&model)
 }
@@ -0,0 +1,90 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type PromptRepository struct {
+	db *gorm.DB
+}
+
+func NewPromptRepository(db *gorm.DB) *PromptRepository {
+	return &PromptRepository{db: db}
+}
+
+func (r *PromptRepository) Create(prompt domain.Prompt) error {
+	model := Prompt{
+		ID:        prompt.ID,
+		Version:   prompt.Version,
+		Content:   prompt.Content,
+		Note:      prompt.Note,
+		Active:    prompt.Active,
+		CreatedBy: prompt.CreatedBy,
+		CreatedAt: prompt.CreatedAt,
+	}
+	if err := r.db.Create(&model).Error; err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrPromptVersionExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *PromptRepository) List() []domain.Prompt {
+	var models []Prompt
+	if err := r.db.Order("version desc").Find(&models).Error; err != nil {
+		return nil
+	}
+	items := make([]domain.Prompt, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainPrompt(model))
+	}
+	return items
+}
+
+func (r *PromptRepository) FindByID(id string) (domain.Prompt, bool) {
+	var model Prompt
+	if err := r.db.First(&model, "id = ?", id).Error; err != nil {
+		return domain.Prompt{}, false
+	}
+	return toDomainPrompt(model), true
+}
+
+func (r *PromptRepository) FindActive() (domain.Prompt, bool) {
+	var model Prompt
+	if err := r.db.Where("active = ?", true).Order("version desc").First(&model).Error; err != nil {
+		return domain.Prompt{}, false
+	}
+	return toDomainPrompt(model), true
+}
+
+func (r *PromptRepository) Activate(id string) bool {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Prompt{}).Where("id = ?", id).Update("active", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&Prompt{}).Where("id <> ? AND active = ?", id, true).Update("active", false).Error
+	})
+	return err == nil
+}
+
+func toDomainPrompt(model Prompt) domain.Prompt {
+	return domain.Prompt{
+		ID:        model.ID,
+		Version:   model.Version,
+		Content:   model.Content,
+		Note:      model.Note,
+		Active:    model.Active,
+		CreatedBy: model.CreatedBy,
+		CreatedAt: model.CreatedAt,
+	}
+}
+
+var _ repository.PromptRepository = (*PromptRepository)(nil)
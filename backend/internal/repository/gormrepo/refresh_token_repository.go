@@ -0,0 +1,90 @@
+package gormrepo
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(token domain.RefreshToken) error {
+	model := RefreshToken{
+		ID:         token.ID,
+		UserID:     token.UserID,
+		FamilyID:   token.FamilyID,
+		TokenHash:  token.TokenHash,
+		ExpiresAt:  token.ExpiresAt,
+		RevokedAt:  token.RevokedAt,
+		ReplacedBy: token.ReplacedBy,
+		CreatedAt:  token.CreatedAt,
+	}
+	return r.db.Create(&model).Error
+}
+
+func (r *RefreshTokenRepository) FindByHash(tokenHash string) (domain.RefreshToken, bool) {
+	var model RefreshToken
+	if err := r.db.First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		return domain.RefreshToken{}, false
+	}
+	return toDomainRefreshToken(model), true
+}
+
+func (r *RefreshTokenRepository) Rotate(id, replacedBy string, at time.Time) bool {
+	// revoked_at が NULL の行だけを更新し、同じトークンの同時ローテーションを一方だけ成功させる
+	result := r.db.Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": at, "replaced_by": replacedBy})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, at time.Time) int {
+	result := r.db.Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(userID string, at time.Time) int {
+	result := r.db.Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+func (r *RefreshTokenRepository) DeleteExpired(before time.Time) int {
+	result := r.db.Delete(&RefreshToken{}, "expires_at < ?", before)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+func toDomainRefreshToken(model RefreshToken) domain.RefreshToken {
+	return domain.RefreshToken{
+		ID:         model.ID,
+		UserID:     model.UserID,
+		FamilyID:   model.FamilyID,
+		TokenHash:  model.TokenHash,
+		ExpiresAt:  model.ExpiresAt,
+		RevokedAt:  model.RevokedAt,
+		ReplacedBy: model.ReplacedBy,
+		CreatedAt:  model.CreatedAt,
+	}
+}
+
+var _ repository.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
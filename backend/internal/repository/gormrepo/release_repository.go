@@ -0,0 +1,86 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type ReleaseRepository struct {
+	db *gorm.DB
+}
+
+func NewReleaseRepository(db *gorm.DB) *ReleaseRepository {
+	return &ReleaseRepository{db: db}
+}
+
+func (r *ReleaseRepository) Upsert(release domain.Release) (domain.Release, error) {
+	key := repository.ReleaseKey(release)
+	var existing Release
+	if err := r.db.Where("series_id = ? AND dedup_key = ?", release.SeriesID, key).First(&existing).Error; err == nil {
+		release.ID = existing.ID
+	}
+	authors, err := marshalAuthors(release.Authors)
+	if err != nil {
+		return domain.Release{}, err
+	}
+	model := Release{
+		ID:           release.ID,
+		SeriesID:     release.SeriesID,
+		DedupKey:     key,
+		ISBN13:       release.ISBN13,
+		Title:        release.Title,
+		Authors:      datatypes.JSON(authors),
+		Publisher:    release.Publisher,
+		VolumeLabel:  release.VolumeLabel,
+		VolumeNumber: valueOrNilInt(release.VolumeNumber),
+		ReleaseDate:  release.ReleaseDate,
+		ThumbnailURL: release.ThumbnailURL,
+		Source:       release.Source,
+		DiscoveredAt: release.DiscoveredAt,
+	}
+	if err := r.db.Save(&model).Error; err != nil {
+		return domain.Release{}, err
+	}
+	return release, nil
+}
+
+func (r *ReleaseRepository) ListBySeriesIDs(seriesIDs []string, fromDate string) []domain.Release {
+	if len(seriesIDs) == 0 {
+		return []domain.Release{}
+	}
+	var models []Release
+	if err := r.db.Where("series_id IN ? AND release_date >= ?", seriesIDs, fromDate).
+		Order("release_date asc, id asc").Find(&models).Error; err != nil {
+		return nil
+	}
+	items := make([]domain.Release, 0, len(models))
+	for _, model := range models {
+		items = append(items, modelToDomainRelease(model))
+	}
+	return items
+}
+
+func (r *ReleaseRepository) DeleteBefore(date string) error {
+	return r.db.Delete(&Release{}, "release_date < ?", date).Error
+}
+
+func modelToDomainRelease(model Release) domain.Release {
+	return domain.Release{
+		ID:           model.ID,
+		SeriesID:     model.SeriesID,
+		ISBN13:       model.ISBN13,
+		Title:        model.Title,
+		Authors:      unmarshalAuthors(model.Authors),
+		Publisher:    model.Publisher,
+		VolumeLabel:  model.VolumeLabel,
+		VolumeNumber: valueOrZeroInt(model.VolumeNumber),
+		ReleaseDate:  model.ReleaseDate,
+		ThumbnailURL: model.ThumbnailURL,
+		Source:       model.Source,
+		DiscoveredAt: model.DiscoveredAt,
+	}
+}
+
+var _ repository.ReleaseRepository = (*ReleaseRepository)(nil)
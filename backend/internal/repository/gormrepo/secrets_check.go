@@ -0,0 +1,48 @@
+package gormrepo
+
+import (
+	"fmt"
+
+	"book_manager/backend/internal/secrets"
+	"gorm.io/gorm"
+)
+
+// CheckSecrets は暗号化済みの API キーがすべて cipher で復号できるかを確認します。
+// SECRETS_MASTER_KEYS の設定漏れや誤りのまま起動すると、キーが読めないまま運用されるため起動前に呼び出します。
+func CheckSecrets(db *gorm.DB, cipher *secrets.Cipher) error {
+	var keys []OpenAIKey
+	if err := db.Select("ID", "APIKey").Find(&keys).Error; err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err := cipher.Decrypt(key.APIKey); err != nil {
+			return fmt.Errorf("openai key %s cannot be decrypted: %w", key.ID, err)
+		}
+	}
+	var settings []ProfileSettings
+	if err := db.Select("UserID", "OpenAIAPIKey").Find(&settings).Error; err != nil {
+		return err
+	}
+	for _, item := range settings {
+		if _, err := cipher.Decrypt(item.OpenAIAPIKey); err != nil {
+			return fmt.Errorf("profile settings of user %s cannot be decrypted: %w", item.UserID, err)
+		}
+	}
+	return nil
+}
+
+// sealSecret は保存する API キーを暗号化します。
+// 保存値を復号した結果と同じ場合は再暗号化せず保存値を使い、
+// 復号できない保存値は読み出し時に空になるため、空での上書きでは保存値を残して消さないようにします。
+func sealSecret(cipher *secrets.Cipher, stored, plain string) (string, error) {
+	if stored != "" {
+		current, err := cipher.Decrypt(stored)
+		if err != nil && plain == "" {
+			return stored, nil
+		}
+		if err == nil && current == plain {
+			return stored, nil
+		}
+	}
+	return cipher.Encrypt(plain)
+}
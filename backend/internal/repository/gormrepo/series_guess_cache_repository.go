@@ -0,0 +1,48 @@
+package gormrepo
+
+import (
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type SeriesGuessCacheRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesGuessCacheRepository(db *gorm.DB) *SeriesGuessCacheRepository {
+	return &SeriesGuessCacheRepository{db: db}
+}
+
+func (r *SeriesGuessCacheRepository) Get(isbn string) (domain.SeriesGuessCache, bool) {
+	var model SeriesGuessCache
+	if err := r.db.First(&model, "isbn13 = ?", isbn).Error; err != nil {
+		return domain.SeriesGuessCache{}, false
+	}
+	return domain.SeriesGuessCache{
+		ISBN13:       model.ISBN13,
+		IsSeries:     model.IsSeries,
+		SeriesName:   model.SeriesName,
+		VolumeNumber: model.VolumeNumber,
+		Confidence:   model.Confidence,
+		Model:        model.Model,
+		PromptHash:   model.PromptHash,
+		CreatedAt:    model.CreatedAt,
+	}, true
+}
+
+func (r *SeriesGuessCacheRepository) Upsert(entry domain.SeriesGuessCache) error {
+	model := SeriesGuessCache{
+		ISBN13:       entry.ISBN13,
+		IsSeries:     entry.IsSeries,
+		SeriesName:   entry.SeriesName,
+		VolumeNumber: entry.VolumeNumber,
+		Confidence:   entry.Confidence,
+		Model:        entry.Model,
+		PromptHash:   entry.PromptHash,
+		CreatedAt:    entry.CreatedAt,
+	}
+	return r.db.Save(&model).Error
+}
+
+var _ repository.SeriesGuessCacheRepository = (*SeriesGuessCacheRepository)(nil)
@@ -0,0 +1,98 @@
+package gormrepo
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(session domain.Session) error {
+	model := toModelSession(session)
+	return r.db.Create(&model).Error
+}
+
+func (r *SessionRepository) FindByID(id string) (domain.Session, bool) {
+	var model Session
+	if err := r.db.First(&model, "id = ?", id).Error; err != nil {
+		return domain.Session{}, false
+	}
+	return toDomainSession(model), true
+}
+
+func (r *SessionRepository) FindByTokenHash(tokenHash string) (domain.Session, bool) {
+	var model Session
+	if err := r.db.First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		return domain.Session{}, false
+	}
+	return toDomainSession(model), true
+}
+
+func (r *SessionRepository) ListByUser(userID string) []domain.Session {
+	var models []Session
+	if err := r.db.Where("user_id = ?", userID).Order("last_used_at desc").Find(&models).Error; err != nil {
+		return nil
+	}
+	items := make([]domain.Session, 0, len(models))
+	for _, model := range models {
+		items = append(items, toDomainSession(model))
+	}
+	return items
+}
+
+func (r *SessionRepository) Update(session domain.Session) bool {
+	model := toModelSession(session)
+	if err := r.db.Save(&model).Error; err != nil {
+		return false
+	}
+	return true
+}
+
+func (r *SessionRepository) RevokeAllForUser(userID string, at time.Time) int {
+	result := r.db.Model(&Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return 0
+	}
+	return int(result.RowsAffected)
+}
+
+func toModelSession(session domain.Session) Session {
+	return Session{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		TokenHash:  session.TokenHash,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		RevokedAt:  session.RevokedAt,
+	}
+}
+
+func toDomainSession(model Session) domain.Session {
+	return domain.Session{
+		ID:         model.ID,
+		UserID:     model.UserID,
+		DeviceName: model.DeviceName,
+		UserAgent:  model.UserAgent,
+		IP:         model.IP,
+		TokenHash:  model.TokenHash,
+		CreatedAt:  model.CreatedAt,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+	}
+}
+
+var _ repository.SessionRepository = (*SessionRepository)(nil)
@@ -16,14 +16,18 @@ func NewUserBookRepository(db *gorm.DB) *UserBookRepository {
 
 func (r *UserBookRepository) Create(userBook domain.UserBook) error {
 	model := UserBook{
-		ID:           userBook.ID,
-		UserID:       userBook.UserID,
-		BookID:       userBook.BookID,
-		Note:         userBook.Note,
-		AcquiredAt:   userBook.AcquiredAt,
-		SeriesID:     userBook.SeriesID,
-		VolumeNumber: valueOrNilInt(userBook.VolumeNumber),
-		SeriesSource: userBook.SeriesSource,
+		ID:            userBook.ID,
+		UserID:        userBook.UserID,
+		BookID:        userBook.BookID,
+		Note:          userBook.Note,
+		AcquiredAt:    userBook.AcquiredAt,
+		SeriesID:      userBook.SeriesID,
+		VolumeNumber:  valueOrNilInt(userBook.VolumeNumber),
+		VolumeLabel:   userBook.VolumeLabel,
+		SeriesSource:  userBook.SeriesSource,
+		PurchasePrice: valueOrNilInt(userBook.PurchasePrice),
+		Currency:      userBook.Currency,
+		Store:         userBook.Store,
 	}
 	if err := r.db.Create(&model).Error; err != nil {
 		if isUniqueViolation(err) {
@@ -80,14 +84,18 @@ func (r *UserBookRepository) FindByID(id string) (domain.UserBook, bool) {
 
 func (r *UserBookRepository) Update(userBook domain.UserBook) bool {
 	model := UserBook{
-		ID:           userBook.ID,
-		UserID:       userBook.UserID,
-		BookID:       userBook.BookID,
-		Note:         userBook.Note,
-		AcquiredAt:   userBook.AcquiredAt,
-		SeriesID:     userBook.SeriesID,
-		VolumeNumber: valueOrNilInt(userBook.VolumeNumber),
-		SeriesSource: userBook.SeriesSource,
+		ID:            userBook.ID,
+		UserID:        userBook.UserID,
+		BookID:        userBook.BookID,
+		Note:          userBook.Note,
+		AcquiredAt:    userBook.AcquiredAt,
+		SeriesID:      userBook.SeriesID,
+		VolumeNumber:  valueOrNilInt(userBook.VolumeNumber),
+		VolumeLabel:   userBook.VolumeLabel,
+		SeriesSource:  userBook.SeriesSource,
+		PurchasePrice: valueOrNilInt(userBook.PurchasePrice),
+		Currency:      userBook.Currency,
+		Store:         userBook.Store,
 	}
 	if err := r.db.Save(&model).Error; err != nil {
 		return false
@@ -102,16 +110,35 @@ func (r *UserBookRepository) Delete(id string) bool {
 	return true
 }
 
+func (r *UserBookRepository) CountByUser() map[string]int {
+	var rows []struct {
+		UserID string
+		Count  int
+	}
+	counts := make(map[string]int)
+	if err := r.db.Model(&UserBook{}).Select("user_id, count(*) as count").Group("user_id").Scan(&rows).Error; err != nil {
+		return counts
+	}
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+	return counts
+}
+
 func modelToDomainUserBook(model UserBook) domain.UserBook {
 	return domain.UserBook{
-		ID:           model.ID,
-		UserID:       model.UserID,
-		BookID:       model.BookID,
-		Note:         model.Note,
-		AcquiredAt:   model.AcquiredAt,
-		SeriesID:     model.SeriesID,
-		VolumeNumber: valueOrZeroInt(model.VolumeNumber),
-		SeriesSource: model.SeriesSource,
+		ID:            model.ID,
+		UserID:        model.UserID,
+		BookID:        model.BookID,
+		Note:          model.Note,
+		AcquiredAt:    model.AcquiredAt,
+		SeriesID:      model.SeriesID,
+		VolumeNumber:  valueOrZeroInt(model.VolumeNumber),
+		VolumeLabel:   model.VolumeLabel,
+		SeriesSource:  model.SeriesSource,
+		PurchasePrice: valueOrZeroInt(model.PurchasePrice),
+		Currency:      model.Currency,
+		Store:         model.Store,
 	}
 }
 
@@ -21,6 +21,7 @@ func (r *UserRepository) Create(user domain.User) error {
 		UserID:     user.UserID,
 		DisplayName:  user.DisplayName,
 		PasswordHash: user.PasswordHash,
+		EmailVerified: user.EmailVerified,
 	}
 	if _, ok := r.FindByID(user.ID); ok {
 		return repository.ErrUserExists
@@ -45,6 +46,7 @@ func (r *UserRepository) FindByEmail(email string) (domain.User, bool) {
 		UserID:     model.UserID,
 		DisplayName:  model.DisplayName,
 		PasswordHash: model.PasswordHash,
+		EmailVerified: model.EmailVerified,
 	}, true
 }
 
@@ -59,6 +61,7 @@ func (r *UserRepository) FindByUserID(userID string) (domain.User, bool) {
 		UserID:     model.UserID,
 		DisplayName:  model.DisplayName,
 		PasswordHash: model.PasswordHash,
+		EmailVerified: model.EmailVerified,
 	}, true
 }
 
@@ -73,6 +76,7 @@ func (r *UserRepository) FindByID(id string) (domain.User, bool) {
 		UserID:     model.UserID,
 		DisplayName:  model.DisplayName,
 		PasswordHash: model.PasswordHash,
+		EmailVerified: model.EmailVerified,
 	}, true
 }
 
@@ -89,6 +93,7 @@ func (r *UserRepository) List() []domain.User {
 			UserID:     model.UserID,
 			DisplayName:  model.DisplayName,
 			PasswordHash: model.PasswordHash,
+			EmailVerified: model.EmailVerified,
 		})
 	}
 	return items
@@ -101,6 +106,7 @@ func (r *UserRepository) Update(user domain.User) bool {
 		UserID:     user.UserID,
 		DisplayName:  user.DisplayName,
 		PasswordHash: user.PasswordHash,
+		EmailVerified: user.EmailVerified,
 	}
 	if err := r.db.Save(&model).Error; err != nil {
 		return false
@@ -0,0 +1,125 @@
+package repository
+
+// MemoryAccountDataRepository はメモリ実装の各リポジトリからユーザーのデータを削除します。
+// トランザクションはないため、beforeCommit を先に呼び出し、失敗した場合は何も削除しません。
+type MemoryAccountDataRepository struct {
+	tables []memoryAccountTable
+}
+
+type memoryAccountTable struct {
+	name   string
+	delete func(userID string) int
+}
+
+func NewMemoryAccountDataRepository(
+	users UserRepository,
+	profiles ProfileSettingsRepository,
+	userBooks UserBookRepository,
+	favorites FavoriteRepository,
+	nextToBuy NextToBuyRepository,
+	recommendations RecommendationRepository,
+	calendarTokens CalendarTokenRepository,
+	verifications EmailVerificationRepository,
+	auditLogs *MemoryAuditLogRepository,
+	refreshTokens *MemoryRefreshTokenRepository,
+	sessions *MemorySessionRepository,
+	apiTokens *MemoryAPITokenRepository,
+	emailChanges EmailChangeRepository,
+	suspensions AccountSuspensionRepository,
+	bookReports BookReportRepository,
+	adminUsers AdminUserRepository,
+) *MemoryAccountDataRepository {
+	return &MemoryAccountDataRepository{
+		tables: []memoryAccountTable{
+			{name: "user_books", delete: func(userID string) int {
+				count := 0
+				for _, item := range userBooks.ListByUser(userID) {
+					if userBooks.Delete(item.ID) {
+						count++
+					}
+				}
+				return count
+			}},
+			{name: "favorites", delete: func(userID string) int {
+				count := 0
+				for _, item := range favorites.ListByUser(userID) {
+					if favorites.Delete(item.ID) {
+						count++
+					}
+				}
+				return count
+			}},
+			{name: "next_to_buy_manuals", delete: func(userID string) int {
+				count := 0
+				for _, item := range nextToBuy.ListByUser(userID) {
+					if nextToBuy.Delete(item.ID) {
+						count++
+					}
+				}
+				return count
+			}},
+			{name: "recommendations", delete: func(userID string) int {
+				count := 0
+				for _, item := range recommendations.ListByUser(userID) {
+					if recommendations.Delete(item.ID) {
+						count++
+					}
+				}
+				return count
+			}},
+			{name: "profile_settings", delete: func(userID string) int {
+				if _, ok := profiles.Get(userID); !ok {
+					return 0
+				}
+				profiles.Delete(userID)
+				return 1
+			}},
+			{name: "calendar_tokens", delete: func(userID string) int {
+				if calendarTokens.Delete(userID) {
+					return 1
+				}
+				return 0
+			}},
+			{name: "email_verifications", delete: verifications.DeleteForUser},
+			{name: "audit_logs", delete: auditLogs.DeleteByUser},
+			{name: "refresh_tokens", delete: refreshTokens.DeleteByUser},
+			{name: "sessions", delete: sessions.DeleteByUser},
+			{name: "api_tokens", delete: apiTokens.DeleteByUser},
+			{name: "email_changes", delete: emailChanges.DeleteForUser},
+			{name: "account_suspensions", delete: func(userID string) int {
+				if suspensions.Delete(userID) {
+					return 1
+				}
+				return 0
+			}},
+			{name: "book_reports", delete: bookReports.DeleteByUser},
+			// ロールは公開ユーザー ID で保存しているため、users より先に削除する
+			{name: "admin_users", delete: func(userID string) int {
+				user, ok := users.FindByID(userID)
+				if !ok || !adminUsers.Delete(user.UserID) {
+					return 0
+				}
+				return 1
+			}},
+			{name: "users", delete: func(userID string) int {
+				if users.Delete(userID) {
+					return 1
+				}
+				return 0
+			}},
+		},
+	}
+}
+
+func (r *MemoryAccountDataRepository) DeleteAllForUser(userID string, beforeCommit func() error) (map[string]int, error) {
+	if beforeCommit != nil {
+		if err := beforeCommit(); err != nil {
+			return nil, err
+		}
+	}
+	counts := make(map[string]int, len(r.tables))
+	for _, table := range r.tables {
+		counts[table.name] = table.delete(userID)
+	}
+	return counts, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryAccountSuspensionRepository struct {
+	mu     sync.RWMutex
+	byUser map[string]domain.AccountSuspension
+}
+
+func NewMemoryAccountSuspensionRepository() *MemoryAccountSuspensionRepository {
+	return &MemoryAccountSuspensionRepository{
+		byUser: make(map[string]domain.AccountSuspension),
+	}
+}
+
+func (r *MemoryAccountSuspensionRepository) Get(userID string) (domain.AccountSuspension, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	suspension, ok := r.byUser[userID]
+	return suspension, ok
+}
+
+func (r *MemoryAccountSuspensionRepository) Save(suspension domain.AccountSuspension) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byUser[suspension.UserID] = suspension
+	return nil
+}
+
+func (r *MemoryAccountSuspensionRepository) Delete(userID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byUser[userID]; !ok {
+		return false
+	}
+	delete(r.byUser, userID)
+	return true
+}
+
+func (r *MemoryAccountSuspensionRepository) List() []domain.AccountSuspension {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.AccountSuspension, 0, len(r.byUser))
+	for _, suspension := range r.byUser {
+		items = append(items, suspension)
+	}
+	return items
+}
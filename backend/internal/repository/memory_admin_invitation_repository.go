@@ -2,6 +2,7 @@ package repository
 
 import (
 	"sync"
+	"time"
 
 	"book_manager/backend/internal/domain"
 )
@@ -82,10 +83,15 @@ func (r *MemoryAdminInvitationRepository) Update(invitation domain.AdminInvitati
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.byID[invitation.ID]; !ok {
+	existing, ok := r.byID[invitation.ID]
+	if !ok {
 		return false
 	}
+	// 再送でトークンが変わった場合は古いトークンで引けないようにする
+	delete(r.byToken, existing.Token)
 	r.byID[invitation.ID] = invitation
+	r.byToken[invitation.Token] = invitation.ID
+	r.byUserID[invitation.UserID] = invitation.ID
 	return true
 }
 
@@ -93,6 +99,23 @@ func (r *MemoryAdminInvitationRepository) Delete(id string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	return r.deleteLocked(id)
+}
+
+func (r *MemoryAdminInvitationRepository) DeleteExpiredBefore(cutoff time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, invitation := range r.byID {
+		if invitation.UsedAt == nil && invitation.ExpiresAt.Before(cutoff) && r.deleteLocked(id) {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *MemoryAdminInvitationRepository) deleteLocked(id string) bool {
 	invitation, ok := r.byID[id]
 	if !ok {
 		return false
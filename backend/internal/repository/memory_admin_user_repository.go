@@ -48,6 +48,19 @@ func (r *MemoryAdminUserRepository) List() []domain.AdminUser {
 	return items
 }
 
+func (r *MemoryAdminUserRepository) UpdateRole(userID, role string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	adminUser, ok := r.byUserID[userID]
+	if !ok {
+		return false
+	}
+	adminUser.Role = role
+	r.byUserID[userID] = adminUser
+	return true
+}
+
 func (r *MemoryAdminUserRepository) Delete(userID string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
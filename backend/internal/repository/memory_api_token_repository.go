@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryAPITokenRepository struct {
+	mu     sync.RWMutex
+	byID   map[string]domain.APIToken
+	byHash map[string]string
+}
+
+func NewMemoryAPITokenRepository() *MemoryAPITokenRepository {
+	return &MemoryAPITokenRepository{
+		byID:   make(map[string]domain.APIToken),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *MemoryAPITokenRepository) Create(token domain.APIToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[token.ID] = token
+	r.byHash[token.TokenHash] = token.ID
+	return nil
+}
+
+func (r *MemoryAPITokenRepository) FindByID(id string) (domain.APIToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, ok := r.byID[id]
+	return token, ok
+}
+
+func (r *MemoryAPITokenRepository) FindByHash(tokenHash string) (domain.APIToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byHash[tokenHash]
+	if !ok {
+		return domain.APIToken{}, false
+	}
+	token, ok := r.byID[id]
+	return token, ok
+}
+
+func (r *MemoryAPITokenRepository) ListByUser(userID string) []domain.APIToken {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.APIToken, 0)
+	for _, token := range r.byID {
+		if token.UserID == userID {
+			items = append(items, token)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return items
+}
+
+func (r *MemoryAPITokenRepository) Update(token domain.APIToken) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byID[token.ID]; !ok {
+		return false
+	}
+	r.byID[token.ID] = token
+	return true
+}
+
+// DeleteByUser はユーザーの個人用アクセストークンを削除し、削除した件数を返します。
+func (r *MemoryAPITokenRepository) DeleteByUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, item := range r.byID {
+		if item.UserID != userID {
+			continue
+		}
+		delete(r.byHash, item.TokenHash)
+		delete(r.byID, id)
+		count++
+	}
+	return count
+}
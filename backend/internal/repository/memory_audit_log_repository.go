@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -39,3 +40,107 @@ func (r *MemoryAuditLogRepository) DeleteBefore(t time.Time) error {
 	r.items = filtered
 	return nil
 }
+
+func (r *MemoryAuditLogRepository) ListByUser(userID string) []domain.AuditLog {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.AuditLog, 0)
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *MemoryAuditLogRepository) LastActivityByUser() map[string]time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	latest := make(map[string]time.Time)
+	for _, item := range r.items {
+		if item.UserID == "" {
+			continue
+		}
+		if current, ok := latest[item.UserID]; !ok || item.CreatedAt.After(current) {
+			latest[item.UserID] = item.CreatedAt
+		}
+	}
+	return latest
+}
+
+func (r *MemoryAuditLogRepository) Query(query AuditLogQuery) []domain.AuditLog {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.AuditLog, 0)
+	for _, item := range r.items {
+		if !matchesAuditLogQuery(item, query) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].ID > items[j].ID
+		}
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	if query.Limit > 0 && len(items) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items
+}
+
+func (r *MemoryAuditLogRepository) Count(query AuditLogQuery) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query.BeforeAt, query.BeforeID = time.Time{}, ""
+	count := 0
+	for _, item := range r.items {
+		if matchesAuditLogQuery(item, query) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchesAuditLogQuery(item domain.AuditLog, query AuditLogQuery) bool {
+	if query.UserID != "" && item.UserID != query.UserID {
+		return false
+	}
+	if query.Entity != "" && item.Entity != query.Entity {
+		return false
+	}
+	if query.EntityID != "" && item.EntityID != query.EntityID {
+		return false
+	}
+	if query.Action != "" && item.Action != query.Action {
+		return false
+	}
+	if !query.From.IsZero() && item.CreatedAt.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && !item.CreatedAt.Before(query.To) {
+		return false
+	}
+	return query.After(item)
+}
+
+// DeleteByUser はユーザーの監査ログを削除し、削除した件数を返します。
+func (r *MemoryAuditLogRepository) DeleteByUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filtered := r.items[:0]
+	for _, item := range r.items {
+		if item.UserID != userID {
+			filtered = append(filtered, item)
+		}
+	}
+	count := len(r.items) - len(filtered)
+	r.items = filtered
+	return count
+}
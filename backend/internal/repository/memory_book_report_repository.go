@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryBookReportRepository struct {
+	mu   sync.RWMutex
+	byID map[string]domain.BookReport
+}
+
+func NewMemoryBookReportRepository() *MemoryBookReportRepository {
+	return &MemoryBookReportRepository{
+		byID: make(map[string]domain.BookReport),
+	}
+}
+
+func (r *MemoryBookReportRepository) Create(report domain.BookReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[report.ID] = report
+	return nil
+}
+
+func (r *MemoryBookReportRepository) FindByID(id string) (domain.BookReport, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	report, ok := r.byID[id]
+	return report, ok
+}
+
+func (r *MemoryBookReportRepository) List(status string) []domain.BookReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.BookReport, 0, len(r.byID))
+	for _, report := range r.byID {
+		if status != "" && report.Status != status {
+			continue
+		}
+		items = append(items, report)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return items
+}
+
+func (r *MemoryBookReportRepository) Update(report domain.BookReport) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byID[report.ID]; !ok {
+		return false
+	}
+	r.byID[report.ID] = report
+	return true
+}
+
+func (r *MemoryBookReportRepository) DeleteByUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, report := range r.byID {
+		if report.UserID == userID {
+			delete(r.byID, id)
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryCalendarTokenRepository struct {
+	mu     sync.RWMutex
+	byUser map[string]domain.CalendarToken
+	byHash map[string]string
+}
+
+func NewMemoryCalendarTokenRepository() *MemoryCalendarTokenRepository {
+	return &MemoryCalendarTokenRepository{
+		byUser: make(map[string]domain.CalendarToken),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *MemoryCalendarTokenRepository) Upsert(token domain.CalendarToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.byUser[token.UserID]; ok {
+		delete(r.byHash, current.TokenHash)
+	}
+	r.byUser[token.UserID] = token
+	r.byHash[token.TokenHash] = token.UserID
+	return nil
+}
+
+func (r *MemoryCalendarTokenRepository) FindByUserID(userID string) (domain.CalendarToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, ok := r.byUser[userID]
+	return token, ok
+}
+
+func (r *MemoryCalendarTokenRepository) FindByHash(tokenHash string) (domain.CalendarToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userID, ok := r.byHash[tokenHash]
+	if !ok {
+		return domain.CalendarToken{}, false
+	}
+	item, ok := r.byUser[userID]
+	return item, ok
+}
+
+func (r *MemoryCalendarTokenRepository) Delete(userID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.byUser[userID]
+	if !ok {
+		return false
+	}
+	delete(r.byUser, userID)
+	delete(r.byHash, current.TokenHash)
+	return true
+}
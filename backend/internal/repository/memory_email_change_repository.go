@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryEmailChangeRepository struct {
+	mu     sync.RWMutex
+	byID   map[string]domain.EmailChange
+	byHash map[string]string
+}
+
+func NewMemoryEmailChangeRepository() *MemoryEmailChangeRepository {
+	return &MemoryEmailChangeRepository{
+		byID:   make(map[string]domain.EmailChange),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *MemoryEmailChangeRepository) Create(change domain.EmailChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[change.ID] = change
+	r.byHash[change.TokenHash] = change.ID
+	return nil
+}
+
+func (r *MemoryEmailChangeRepository) FindByHash(tokenHash string) (domain.EmailChange, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byHash[tokenHash]
+	if !ok {
+		return domain.EmailChange{}, false
+	}
+	change, ok := r.byID[id]
+	return change, ok
+}
+
+func (r *MemoryEmailChangeRepository) ListByUser(userID string) []domain.EmailChange {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.EmailChange, 0)
+	for _, change := range r.byID {
+		if change.UserID == userID {
+			items = append(items, change)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return items
+}
+
+func (r *MemoryEmailChangeRepository) Update(change domain.EmailChange) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byID[change.ID]; !ok {
+		return false
+	}
+	r.byID[change.ID] = change
+	return true
+}
+
+func (r *MemoryEmailChangeRepository) DeleteForUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, change := range r.byID {
+		if change.UserID == userID {
+			delete(r.byID, id)
+			delete(r.byHash, change.TokenHash)
+			count++
+		}
+	}
+	return count
+}
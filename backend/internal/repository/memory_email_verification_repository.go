@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryEmailVerificationRepository struct {
+	mu     sync.RWMutex
+	byID   map[string]domain.EmailVerification
+	byHash map[string]string
+}
+
+func NewMemoryEmailVerificationRepository() *MemoryEmailVerificationRepository {
+	return &MemoryEmailVerificationRepository{
+		byID:   make(map[string]domain.EmailVerification),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *MemoryEmailVerificationRepository) Create(verification domain.EmailVerification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[verification.ID] = verification
+	r.byHash[verification.TokenHash] = verification.ID
+	return nil
+}
+
+func (r *MemoryEmailVerificationRepository) FindByHash(tokenHash string) (domain.EmailVerification, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byHash[tokenHash]
+	if !ok {
+		return domain.EmailVerification{}, false
+	}
+	verification, ok := r.byID[id]
+	return verification, ok
+}
+
+func (r *MemoryEmailVerificationRepository) MarkUsed(id string, at time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	verification, ok := r.byID[id]
+	if !ok || verification.UsedAt != nil {
+		return false
+	}
+	verification.UsedAt = &at
+	r.byID[id] = verification
+	return true
+}
+
+func (r *MemoryEmailVerificationRepository) DeleteForUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, verification := range r.byID {
+		if verification.UserID == userID {
+			delete(r.byID, id)
+			delete(r.byHash, verification.TokenHash)
+			count++
+		}
+	}
+	return count
+}
@@ -65,6 +65,19 @@ func (r *MemoryFavoriteRepository) ListBySeriesID(seriesID string) []domain.Favo
 	return items
 }
 
+func (r *MemoryFavoriteRepository) ListByType(favoriteType string) []domain.Favorite {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.Favorite, 0)
+	for _, fav := range r.byID {
+		if fav.Type == favoriteType {
+			items = append(items, fav)
+		}
+	}
+	return items
+}
+
 func (r *MemoryFavoriteRepository) Delete(id string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
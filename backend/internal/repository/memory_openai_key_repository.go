@@ -40,6 +40,17 @@ func (r *MemoryOpenAIKeyRepository) List() []domain.OpenAIKey {
 	return items
 }
 
+func (r *MemoryOpenAIKeyRepository) Update(key domain.OpenAIKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byID[key.ID]; !ok {
+		return false
+	}
+	r.byID[key.ID] = key
+	return true
+}
+
 func (r *MemoryOpenAIKeyRepository) Delete(id string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryOpenAIUsageRepository struct {
+	mu    sync.RWMutex
+	items []domain.OpenAIUsage
+}
+
+func NewMemoryOpenAIUsageRepository() *MemoryOpenAIUsageRepository {
+	return &MemoryOpenAIUsageRepository{
+		items: make([]domain.OpenAIUsage, 0),
+	}
+}
+
+func (r *MemoryOpenAIUsageRepository) Create(usage domain.OpenAIUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, usage)
+	return nil
+}
+
+func (r *MemoryOpenAIUsageRepository) TotalsBetween(from, to time.Time, group OpenAIUsageGroup) map[string]OpenAIUsageTotals {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := make(map[string]OpenAIUsageTotals)
+	for _, item := range r.items {
+		if item.CreatedAt.Before(from) || !item.CreatedAt.Before(to) {
+			continue
+		}
+		var key string
+		switch group {
+		case OpenAIUsageByKey:
+			key = item.KeyID
+		case OpenAIUsageByUser:
+			key = item.UserID
+		case OpenAIUsageByModel:
+			key = item.Model
+		default:
+			return totals
+		}
+		totals[key] = addOpenAIUsage(totals[key], item)
+	}
+	return totals
+}
+
+func (r *MemoryOpenAIUsageRepository) TotalsByUserSince(userID string, since time.Time) OpenAIUsageTotals {
+	return r.sum(func(item domain.OpenAIUsage) bool {
+		return item.UserID == userID && !item.CreatedAt.Before(since)
+	})
+}
+
+func (r *MemoryOpenAIUsageRepository) TotalsByKeySince(keyID string, since time.Time) OpenAIUsageTotals {
+	return r.sum(func(item domain.OpenAIUsage) bool {
+		return item.KeyID == keyID && !item.CreatedAt.Before(since)
+	})
+}
+
+func (r *MemoryOpenAIUsageRepository) sum(match func(domain.OpenAIUsage) bool) OpenAIUsageTotals {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var totals OpenAIUsageTotals
+	for _, item := range r.items {
+		if match(item) {
+			totals = addOpenAIUsage(totals, item)
+		}
+	}
+	return totals
+}
+
+func addOpenAIUsage(totals OpenAIUsageTotals, item domain.OpenAIUsage) OpenAIUsageTotals {
+	totals.Requests++
+	totals.PromptTokens += item.PromptTokens
+	totals.CompletionTokens += item.CompletionTokens
+	totals.TotalTokens += item.TotalTokens
+	totals.CostMicroUSD += item.CostMicroUSD
+	return totals
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryPromptRepository struct {
+	mu   sync.RWMutex
+	byID map[string]domain.Prompt
+}
+
+func NewMemoryPromptRepository() *MemoryPromptRepository {
+	return &MemoryPromptRepository{
+		byID: make(map[string]domain.Prompt),
+	}
+}
+
+func (r *MemoryPromptRepository) Create(prompt domain.Prompt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range r.byID {
+		if item.Version == prompt.Version {
+			return ErrPromptVersionExists
+		}
+	}
+	r.byID[prompt.ID] = prompt
+	return nil
+}
+
+func (r *MemoryPromptRepository) List() []domain.Prompt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.Prompt, 0, len(r.byID))
+	for _, item := range r.byID {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Version > items[j].Version
+	})
+	return items
+}
+
+func (r *MemoryPromptRepository) FindByID(id string) (domain.Prompt, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.byID[id]
+	return item, ok
+}
+
+func (r *MemoryPromptRepository) FindActive() (domain.Prompt, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, item := range r.byID {
+		if item.Active {
+			return item, true
+		}
+	}
+	return domain.Prompt{}, false
+}
+
+func (r *MemoryPromptRepository) Activate(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byID[id]; !ok {
+		return false
+	}
+	for key, item := range r.byID {
+		item.Active = key == id
+		r.byID[key] = item
+	}
+	return true
+}
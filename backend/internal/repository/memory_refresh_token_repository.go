@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	byID   map[string]domain.RefreshToken
+	byHash map[string]string
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{
+		byID:   make(map[string]domain.RefreshToken),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *MemoryRefreshTokenRepository) Create(token domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[token.ID] = token
+	r.byHash[token.TokenHash] = token.ID
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) FindByHash(tokenHash string) (domain.RefreshToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byHash[tokenHash]
+	if !ok {
+		return domain.RefreshToken{}, false
+	}
+	token, ok := r.byID[id]
+	return token, ok
+}
+
+func (r *MemoryRefreshTokenRepository) Rotate(id, replacedBy string, at time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.byID[id]
+	if !ok || token.RevokedAt != nil {
+		return false
+	}
+	token.RevokedAt = &at
+	token.ReplacedBy = replacedBy
+	r.byID[id] = token
+	return true
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(familyID string, at time.Time) int {
+	return r.revokeWhere(func(token domain.RefreshToken) bool {
+		return token.FamilyID == familyID
+	}, at)
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeAllForUser(userID string, at time.Time) int {
+	return r.revokeWhere(func(token domain.RefreshToken) bool {
+		return token.UserID == userID
+	}, at)
+}
+
+func (r *MemoryRefreshTokenRepository) revokeWhere(match func(domain.RefreshToken) bool, at time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, token := range r.byID {
+		if token.RevokedAt != nil || !match(token) {
+			continue
+		}
+		token.RevokedAt = &at
+		r.byID[id] = token
+		count++
+	}
+	return count
+}
+
+func (r *MemoryRefreshTokenRepository) DeleteExpired(before time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, token := range r.byID {
+		if token.ExpiresAt.Before(before) {
+			delete(r.byID, id)
+			delete(r.byHash, token.TokenHash)
+			count++
+		}
+	}
+	return count
+}
+
+// DeleteByUser はユーザーのリフレッシュトークンを削除し、削除した件数を返します。
+func (r *MemoryRefreshTokenRepository) DeleteByUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, item := range r.byID {
+		if item.UserID != userID {
+			continue
+		}
+		delete(r.byHash, item.TokenHash)
+		delete(r.byID, id)
+		count++
+	}
+	return count
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemoryReleaseRepository struct {
+	mu    sync.RWMutex
+	byID  map[string]domain.Release
+	byKey map[string]string
+}
+
+func NewMemoryReleaseRepository() *MemoryReleaseRepository {
+	return &MemoryReleaseRepository{
+		byID:  make(map[string]domain.Release),
+		byKey: make(map[string]string),
+	}
+}
+
+func (r *MemoryReleaseRepository) Upsert(release domain.Release) (domain.Release, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := release.SeriesID + "::" + ReleaseKey(release)
+	if id, ok := r.byKey[key]; ok {
+		release.ID = id
+	}
+	r.byID[release.ID] = release
+	r.byKey[key] = release.ID
+	return release, nil
+}
+
+func (r *MemoryReleaseRepository) ListBySeriesIDs(seriesIDs []string, fromDate string) []domain.Release {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]struct{}, len(seriesIDs))
+	for _, id := range seriesIDs {
+		wanted[id] = struct{}{}
+	}
+	items := make([]domain.Release, 0)
+	for _, item := range r.byID {
+		if _, ok := wanted[item.SeriesID]; !ok {
+			continue
+		}
+		if item.ReleaseDate < fromDate {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ReleaseDate != items[j].ReleaseDate {
+			return items[i].ReleaseDate < items[j].ReleaseDate
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+func (r *MemoryReleaseRepository) DeleteBefore(date string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, id := range r.byKey {
+		if item, ok := r.byID[id]; ok && item.ReleaseDate < date {
+			delete(r.byID, id)
+			delete(r.byKey, key)
+		}
+	}
+	return nil
+}
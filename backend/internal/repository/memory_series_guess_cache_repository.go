@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"sync"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemorySeriesGuessCacheRepository struct {
+	mu    sync.RWMutex
+	cache map[string]domain.SeriesGuessCache
+}
+
+func NewMemorySeriesGuessCacheRepository() *MemorySeriesGuessCacheRepository {
+	return &MemorySeriesGuessCacheRepository{
+		cache: make(map[string]domain.SeriesGuessCache),
+	}
+}
+
+func (r *MemorySeriesGuessCacheRepository) Get(isbn string) (domain.SeriesGuessCache, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.cache[isbn]
+	return entry, ok
+}
+
+func (r *MemorySeriesGuessCacheRepository) Upsert(entry domain.SeriesGuessCache) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cache[entry.ISBN13] = entry
+	return nil
+}
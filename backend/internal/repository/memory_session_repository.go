@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type MemorySessionRepository struct {
+	mu     sync.RWMutex
+	byID   map[string]domain.Session
+	byHash map[string]string
+}
+
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{
+		byID:   make(map[string]domain.Session),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *MemorySessionRepository) Create(session domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[session.ID] = session
+	r.byHash[session.TokenHash] = session.ID
+	return nil
+}
+
+func (r *MemorySessionRepository) FindByID(id string) (domain.Session, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.byID[id]
+	return session, ok
+}
+
+func (r *MemorySessionRepository) FindByTokenHash(tokenHash string) (domain.Session, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byHash[tokenHash]
+	if !ok {
+		return domain.Session{}, false
+	}
+	session, ok := r.byID[id]
+	return session, ok
+}
+
+func (r *MemorySessionRepository) ListByUser(userID string) []domain.Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.Session, 0)
+	for _, session := range r.byID {
+		if session.UserID == userID {
+			items = append(items, session)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].LastUsedAt.After(items[j].LastUsedAt)
+	})
+	return items
+}
+
+func (r *MemorySessionRepository) Update(session domain.Session) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.byID[session.ID]
+	if !ok {
+		return false
+	}
+	if existing.TokenHash != session.TokenHash {
+		delete(r.byHash, existing.TokenHash)
+		r.byHash[session.TokenHash] = session.ID
+	}
+	r.byID[session.ID] = session
+	return true
+}
+
+func (r *MemorySessionRepository) RevokeAllForUser(userID string, at time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, session := range r.byID {
+		if session.UserID != userID || session.RevokedAt != nil {
+			continue
+		}
+		session.RevokedAt = &at
+		r.byID[id] = session
+		count++
+	}
+	return count
+}
+
+// DeleteByUser はユーザーのセッションを削除し、削除した件数を返します。
+func (r *MemorySessionRepository) DeleteByUser(userID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, item := range r.byID {
+		if item.UserID != userID {
+			continue
+		}
+		delete(r.byHash, item.TokenHash)
+		delete(r.byID, id)
+		count++
+	}
+	return count
+}
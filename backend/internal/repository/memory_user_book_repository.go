@@ -125,3 +125,16 @@ func removeID(ids []string, id string) []string {
 	}
 	return result
 }
+
+func (r *MemoryUserBookRepository) CountByUser() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int, len(r.byUser))
+	for userID, ids := range r.byUser {
+		if len(ids) > 0 {
+			counts[userID] = len(ids)
+		}
+	}
+	return counts
+}
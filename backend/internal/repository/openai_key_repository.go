@@ -5,5 +5,6 @@ import "book_manager/backend/internal/domain"
 type OpenAIKeyRepository interface {
 	Create(key domain.OpenAIKey) error
 	List() []domain.OpenAIKey
+	Update(key domain.OpenAIKey) bool
 	Delete(id string) bool
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+// OpenAIUsageTotals は使用量の記録を合計した値です。
+type OpenAIUsageTotals struct {
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostMicroUSD     int64
+}
+
+// OpenAIUsageGroup は使用量を集計する単位です。
+type OpenAIUsageGroup string
+
+const (
+	OpenAIUsageByKey   OpenAIUsageGroup = "key_id"
+	OpenAIUsageByUser  OpenAIUsageGroup = "user_id"
+	OpenAIUsageByModel OpenAIUsageGroup = "model"
+)
+
+type OpenAIUsageRepository interface {
+	Create(usage domain.OpenAIUsage) error
+	// TotalsBetween は from 以上 to 未満の記録を group の値ごとに合計します（記録のない値は含みません）。
+	TotalsBetween(from, to time.Time, group OpenAIUsageGroup) map[string]OpenAIUsageTotals
+	// TotalsByUserSince はユーザーの since 以降の記録を合計します。
+	TotalsByUserSince(userID string, since time.Time) OpenAIUsageTotals
+	// TotalsByKeySince はキーの since 以降の記録を合計します。
+	TotalsByKeySince(keyID string, since time.Time) OpenAIUsageTotals
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"errors"
+
+	"book_manager/backend/internal/domain"
+)
+
+var ErrPromptVersionExists = errors.New("prompt version already exists")
+
+type PromptRepository interface {
+	// Create は同じバージョンが既にある場合 ErrPromptVersionExists を返します。
+	Create(prompt domain.Prompt) error
+	List() []domain.Prompt
+	FindByID(id string) (domain.Prompt, bool)
+	FindActive() (domain.Prompt, bool)
+	// Activate は指定したバージョンを有効にし、それ以外を無効にします。
+	Activate(id string) bool
+}
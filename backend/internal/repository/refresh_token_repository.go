@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type RefreshTokenRepository interface {
+	Create(token domain.RefreshToken) error
+	FindByHash(tokenHash string) (domain.RefreshToken, bool)
+	Rotate(id, replacedBy string, at time.Time) bool
+	RevokeFamily(familyID string, at time.Time) int
+	RevokeAllForUser(userID string, at time.Time) int
+	DeleteExpired(before time.Time) int
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type ReleaseRepository interface {
+	Upsert(release domain.Release) (domain.Release, error)
+	ListBySeriesIDs(seriesIDs []string, fromDate string) []domain.Release
+	DeleteBefore(date string) error
+}
+
+// ReleaseKey はシリーズ内で発売予定を一意に識別するキーを返します。
+// ISBN がない場合はタイトルで代用します。
+func ReleaseKey(release domain.Release) string {
+	if release.ISBN13 != "" {
+		return "isbn:" + release.ISBN13
+	}
+	return "title:" + release.Title
+}
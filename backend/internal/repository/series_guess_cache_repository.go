@@ -0,0 +1,8 @@
+package repository
+
+import "book_manager/backend/internal/domain"
+
+type SeriesGuessCacheRepository interface {
+	Get(isbn string) (domain.SeriesGuessCache, bool)
+	Upsert(entry domain.SeriesGuessCache) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"book_manager/backend/internal/domain"
+)
+
+type SessionRepository interface {
+	Create(session domain.Session) error
+	FindByID(id string) (domain.Session, bool)
+	FindByTokenHash(tokenHash string) (domain.Session, bool)
+	ListByUser(userID string) []domain.Session
+	Update(session domain.Session) bool
+	RevokeAllForUser(userID string, at time.Time) int
+}
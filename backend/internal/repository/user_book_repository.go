@@ -10,4 +10,6 @@ type UserBookRepository interface {
 	FindByID(id string) (domain.UserBook, bool)
 	Update(userBook domain.UserBook) bool
 	Delete(id string) bool
+	// CountByUser はユーザーごとの所蔵数を返します（所蔵のないユーザーは含みません）。
+	CountByUser() map[string]int
 }
@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"book_manager/backend/internal/auditlogs"
 	"book_manager/backend/internal/authctx"
+	"book_manager/backend/internal/clientip"
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/repository"
 )
@@ -24,12 +26,14 @@ func AuditMiddleware(repo repository.AuditLogRepository, next http.Handler) http
 		r.Body = io.NopCloser(bytes.NewReader(body))
 
 		action := methodToAction(r.Method)
-		entity, entityID := pathToEntity(r.URL.Path)
+		path := redactPath(r.URL.Path)
+		entity, entityID := pathToEntity(path)
+		// パスワードやトークンを監査ログに残さないよう、記録する前にマスクする
 		payload := map[string]any{
 			"method": r.Method,
-			"path":   r.URL.Path,
-			"query":  r.URL.Query(),
-			"body":   string(body),
+			"path":   path,
+			"query":  auditlogs.RedactQuery(r.URL.Query()),
+			"body":   auditlogs.RedactBody(path, string(body)),
 		}
 
 		_ = repo.Create(domain.AuditLog{
@@ -39,7 +43,7 @@ func AuditMiddleware(repo repository.AuditLogRepository, next http.Handler) http
 			Entity:    entity,
 			EntityID:  entityID,
 			Payload:   payload,
-			IP:        requestIP(r),
+			IP:        clientip.FromRequest(r),
 			UserAgent: r.UserAgent(),
 			CreatedAt: time.Now(),
 		})
@@ -75,15 +79,16 @@ func pathToEntity(path string) (string, string) {
 	return entity, entityID
 }
 
-func userIDFromRequest(r *http.Request) string {
-	return strings.TrimSpace(authctx.UserIDFromContext(r.Context()))
+// redactPath は URL に含まれる購読用トークンを監査ログに残さないようにします。
+func redactPath(path string) string {
+	if strings.HasPrefix(path, "/calendar/") {
+		return "/calendar/redacted"
+	}
+	return path
 }
 
-func requestIP(r *http.Request) string {
-	if value := strings.TrimSpace(r.Header.Get("X-Forwarded-For")); value != "" {
-		return strings.Split(value, ",")[0]
-	}
-	return r.RemoteAddr
+func userIDFromRequest(r *http.Request) string {
+	return strings.TrimSpace(authctx.UserIDFromContext(r.Context()))
 }
 
 func newAuditID() string {
@@ -3,6 +3,8 @@ package router
 import (
 	"net/http"
 
+	"book_manager/backend/internal/adminusers"
+	"book_manager/backend/internal/clientip"
 	"book_manager/backend/internal/handler"
 	"book_manager/backend/internal/repository"
 )
@@ -12,6 +14,7 @@ func New(
 	auditRepo repository.AuditLogRepository,
 	allowedOrigins string,
 	authMiddleware func(http.Handler) http.Handler,
+	ipResolver *clientip.Resolver,
 ) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", h.Health)
@@ -22,7 +25,12 @@ func New(
 	mux.HandleFunc("/auth/logout", h.AuthLogout)
 	mux.HandleFunc("/auth/resend-verify", h.AuthResendVerify)
 	mux.HandleFunc("/auth/email", h.AuthUpdateEmail)
+	mux.HandleFunc("/auth/email-change/confirm", h.AuthConfirmEmailChange)
+	mux.HandleFunc("/auth/password", h.AuthChangePassword)
 	mux.HandleFunc("/auth/status", h.AuthStatus)
+	mux.HandleFunc("/auth/verify-email", h.AuthVerifyEmail)
+	mux.HandleFunc("/auth/sessions", h.AuthSessions)
+	mux.HandleFunc("/auth/sessions/", h.AuthSessionsByID)
 
 	mux.HandleFunc("/isbn/lookup", h.IsbnLookup)
 
@@ -43,6 +51,9 @@ func New(
 	mux.HandleFunc("/next-to-buy/manual", h.NextToBuyManual)
 	mux.HandleFunc("/next-to-buy/manual/", h.NextToBuyManualByID)
 
+	mux.HandleFunc("/releases/upcoming", h.ReleasesUpcoming)
+	mux.HandleFunc("/calendar/", h.CalendarFeed)
+
 	mux.HandleFunc("/recommendations", h.Recommendations)
 	mux.HandleFunc("/recommendations/", h.RecommendationsByID)
 
@@ -51,6 +62,15 @@ func New(
 	mux.HandleFunc("/users/profile", h.UsersProfile)
 	mux.HandleFunc("/users/me", h.UsersMe)
 	mux.HandleFunc("/users/me/settings", h.UsersMeSettings)
+	mux.HandleFunc("/users/me/calendar", h.UsersMeCalendar)
+	mux.HandleFunc("/users/me/spending", h.UsersMeSpending)
+	mux.HandleFunc("/users/me/series-reclassify", h.UsersMeSeriesReclassify)
+	mux.HandleFunc("/users/me/series-reclassify/apply", h.UsersMeSeriesReclassifyApply)
+	mux.HandleFunc("/users/me/reading-suggestions", h.UsersMeReadingSuggestions)
+	mux.HandleFunc("/users/me/takeout", h.UsersMeTakeout)
+	mux.HandleFunc("/users/me/email-change", h.UsersMeEmailChange)
+	mux.HandleFunc("/users/me/api-tokens", h.UsersMeAPITokens)
+	mux.HandleFunc("/users/me/api-tokens/", h.UsersMeAPITokensByID)
 	mux.HandleFunc("/user/dashboard", h.UserDashboard)
 
 	mux.HandleFunc("/follows/", h.Follows)
@@ -61,13 +81,25 @@ func New(
 	mux.HandleFunc("/series/detail", h.SeriesDetail)
 	mux.HandleFunc("/series/", h.SeriesByID)
 
-	mux.HandleFunc("/admin/openai-keys", h.AdminOpenAIKeys)
-	mux.HandleFunc("/admin/openai-keys/", h.AdminOpenAIKeysByID)
-	mux.HandleFunc("/admin/openai-models", h.AdminOpenAIModels)
-	mux.HandleFunc("/admin/users", h.AdminUsers)
-	mux.HandleFunc("/admin/users/", h.AdminUsersByID)
-	mux.HandleFunc("/admin/invitations", h.AdminInvitations)
-	mux.HandleFunc("/admin/invitations/", h.AdminInvitationsByID)
+	mux.HandleFunc("/admin/openai-keys", h.RequirePermission(adminusers.PermManageOpenAI, h.AdminOpenAIKeys))
+	mux.HandleFunc("/admin/openai-keys/", h.RequirePermission(adminusers.PermManageOpenAI, h.AdminOpenAIKeysByID))
+	mux.HandleFunc("/admin/openai-models", h.RequirePermission(adminusers.PermManageOpenAI, h.AdminOpenAIModels))
+	mux.HandleFunc("/admin/openai-usage", h.RequirePermission(adminusers.PermViewAIUsage, h.AdminOpenAIUsage))
+	mux.HandleFunc("/admin/ai-metrics", h.RequirePermission(adminusers.PermViewAIUsage, h.AdminAIMetrics))
+	mux.HandleFunc("/admin/prompts", h.RequirePermission(adminusers.PermManagePrompts, h.AdminPrompts))
+	mux.HandleFunc("/admin/prompts/", h.RequirePermission(adminusers.PermManagePrompts, h.AdminPromptsByID))
+	mux.HandleFunc("/admin/roles", h.RequirePermission(adminusers.PermManageRoles, h.AdminRoles))
+	mux.HandleFunc("/admin/users", h.RequirePermission(adminusers.PermManageRoles, h.AdminUsers))
+	mux.HandleFunc("/admin/users/", h.RequirePermission(adminusers.PermManageRoles, h.AdminUsersByID))
+	mux.HandleFunc("/admin/invitations", h.RequirePermission(adminusers.PermManageInvitations, h.AdminInvitations))
+	mux.HandleFunc("/admin/invitations/", h.RequirePermission(adminusers.PermManageInvitations, h.AdminInvitationsByID))
+	mux.HandleFunc("/admin/accounts", h.RequirePermission(adminusers.PermManageAccounts, h.AdminAccounts))
+	mux.HandleFunc("/admin/accounts/", h.RequirePermission(adminusers.PermManageAccounts, h.AdminAccountsByID))
+	mux.HandleFunc("/admin/audit-logs", h.RequirePermission(adminusers.PermViewAuditLogs, h.AdminAuditLogs))
+	mux.HandleFunc("/admin/series/merge", h.RequirePermission(adminusers.PermManageSeries, h.AdminSeriesMerge))
+	mux.HandleFunc("/admin/book-reports", h.RequirePermission(adminusers.PermHandleBookReports, h.AdminBookReports))
+	mux.HandleFunc("/admin/book-reports/", h.RequirePermission(adminusers.PermHandleBookReports, h.AdminBookReportsByID))
+	mux.HandleFunc("/admin/audit-logs/export", h.RequirePermission(adminusers.PermViewAuditLogs, h.AdminAuditLogsExport))
 
 	mux.HandleFunc("/auth/signup/admin", h.AuthSignupAdmin)
 
@@ -75,5 +107,6 @@ func New(
 	if authMiddleware != nil {
 		handlerWithAudit = authMiddleware(handlerWithAudit)
 	}
-	return CORSMiddleware(allowedOrigins, handlerWithAudit)
+	// 送信元 IP は認証・監査ログ・ログイン試行の制限で使うため、最初に求めておく
+	return CORSMiddleware(allowedOrigins, ipResolver.Middleware(handlerWithAudit))
 }
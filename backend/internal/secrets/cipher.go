@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prefix は暗号化済みの値の先頭に付く文字列です。付いていない値は平文として扱います。
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey = errors.New("unknown master key id")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// Cipher はマスターキーによるエンベロープ暗号化を行います。
+// 値ごとにデータキーを生成して AES-GCM で暗号化し、データキーはマスターキーで暗号化して値と一緒に保存します。
+// 保存形式は enc:v1:<マスターキーID>:<暗号化したデータキー>:<暗号文> で、キー ID により過去のマスターキーでも復号できます。
+type Cipher struct {
+	current string
+	keys    map[string][]byte
+}
+
+// ParseMasterKeys は "id:base64キー,id:base64キー" 形式の設定を読み込みます。先頭のキーで暗号化します。
+// 空文字の場合は nil を返し、nil の Cipher は暗号化せずに値をそのまま扱います。
+func ParseMasterKeys(spec string) (*Cipher, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	c := &Cipher{keys: make(map[string][]byte)}
+	for _, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid master key entry %q", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("master key %q must be 32 bytes in base64", id)
+		}
+		if _, exists := c.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate master key id %q", id)
+		}
+		c.keys[id] = key
+		if c.current == "" {
+			c.current = id
+		}
+	}
+	return c, nil
+}
+
+// GenerateKey は新しいマスターキーを base64 で返します。
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// CurrentKeyID は暗号化に使うマスターキーの ID を返します。
+func (c *Cipher) CurrentKeyID() string {
+	if c == nil {
+		return ""
+	}
+	return c.current
+}
+
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	if c == nil || plain == "" {
+		return plain, nil
+	}
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(c.keys[c.current], dataKey, []byte(c.current))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, []byte(plain), nil)
+	if err != nil {
+		return "", err
+	}
+	return prefix + c.current + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt は暗号化済みの値を復号します。暗号化されていない値はそのまま返します。
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	if c == nil {
+		return "", ErrUnknownKey
+	}
+	masterKey, ok := c.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	dataKey, err := open(masterKey, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(dataKey, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// NeedsRotation は値が平文か、現在とは異なるマスターキーで暗号化されているかを返します。
+func (c *Cipher) NeedsRotation(value string) bool {
+	if c == nil || value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return keyID != c.current
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func seal(key, plain, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plain, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -50,6 +50,16 @@ func (s *Service) List() []domain.Series {
 	return s.repo.List()
 }
 
+// Get は ID でシリーズを探します。
+func (s *Service) Get(id string) (domain.Series, bool) {
+	for _, item := range s.repo.List() {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return domain.Series{}, false
+}
+
 func (s *Service) Delete(id string) bool {
 	return s.repo.Delete(id)
 }
@@ -0,0 +1,155 @@
+package sessions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/repository"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session revoked")
+)
+
+const (
+	DeviceNameMaxLength = 100
+	userAgentMaxLength  = 512
+)
+
+// Client はセッションを開始・更新したリクエストの端末情報です。
+type Client struct {
+	DeviceName string
+	UserAgent  string
+	IP         string
+}
+
+// Service はリフレッシュトークンごとのログインセッションを記録します。
+// 認証バックエンド（Firebase / ローカル）に関係なく、/auth/* ハンドラーが発行したトークンを追跡します。
+type Service struct {
+	repo repository.SessionRepository
+	now  func() time.Time
+}
+
+func NewService(repo repository.SessionRepository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Start はサインアップ・ログインで発行したリフレッシュトークンのセッションを作成します。
+func (s *Service) Start(userID, refreshToken string, client Client) (domain.Session, error) {
+	now := s.now()
+	session := domain.Session{
+		ID:         idgen.NewSession(),
+		UserID:     userID,
+		DeviceName: truncate(strings.TrimSpace(client.DeviceName), DeviceNameMaxLength),
+		UserAgent:  truncate(client.UserAgent, userAgentMaxLength),
+		IP:         client.IP,
+		TokenHash:  hashToken(refreshToken),
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	if err := s.repo.Create(session); err != nil {
+		return domain.Session{}, err
+	}
+	return session, nil
+}
+
+// Check はリフレッシュトークンのセッションが失効していないかを確認します。
+// 記録のないトークン（セッション記録の導入前に発行されたもの）は許可します。
+func (s *Service) Check(refreshToken string) error {
+	session, ok := s.repo.FindByTokenHash(hashToken(refreshToken))
+	if ok && session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// Touch はリフレッシュでトークンが入れ替わったセッションの最終利用日時を更新します。
+// 記録のないトークンの場合は新しいセッションとして記録します。
+func (s *Service) Touch(userID, oldToken, newToken string, client Client) (domain.Session, error) {
+	session, ok := s.repo.FindByTokenHash(hashToken(oldToken))
+	if !ok {
+		return s.Start(userID, newToken, client)
+	}
+	if session.RevokedAt != nil {
+		return domain.Session{}, ErrSessionRevoked
+	}
+	session.TokenHash = hashToken(newToken)
+	session.LastUsedAt = s.now()
+	if client.UserAgent != "" {
+		session.UserAgent = truncate(client.UserAgent, userAgentMaxLength)
+	}
+	if client.IP != "" {
+		session.IP = client.IP
+	}
+	if !s.repo.Update(session) {
+		return domain.Session{}, errors.New("failed to update session")
+	}
+	return session, nil
+}
+
+// EndByToken はログアウトしたリフレッシュトークンのセッションを失効させます。
+func (s *Service) EndByToken(refreshToken string) {
+	session, ok := s.repo.FindByTokenHash(hashToken(refreshToken))
+	if !ok || session.RevokedAt != nil {
+		return
+	}
+	now := s.now()
+	session.RevokedAt = &now
+	s.repo.Update(session)
+}
+
+// List はユーザーの有効なセッションを最終利用日時の新しい順に返します。
+func (s *Service) List(userID string) []domain.Session {
+	items := s.repo.ListByUser(userID)
+	active := make([]domain.Session, 0, len(items))
+	for _, session := range items {
+		if session.RevokedAt == nil {
+			active = append(active, session)
+		}
+	}
+	return active
+}
+
+// Revoke はユーザー自身のセッションを失効させます。
+func (s *Service) Revoke(userID, id string) (domain.Session, error) {
+	session, ok := s.repo.FindByID(id)
+	if !ok || session.UserID != userID {
+		return domain.Session{}, ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return session, nil
+	}
+	now := s.now()
+	session.RevokedAt = &now
+	if !s.repo.Update(session) {
+		return domain.Session{}, errors.New("failed to revoke session")
+	}
+	return session, nil
+}
+
+// RevokeAll はユーザーのすべてのセッションを失効させ、失効させた件数を返します。
+func (s *Service) RevokeAll(userID string) int {
+	return s.repo.RevokeAllForUser(userID, s.now())
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,197 @@
+package spending
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"book_manager/backend/internal/domain"
+	"book_manager/backend/internal/repository"
+)
+
+// Total は集計キーと通貨ごとの支出合計です。
+type Total struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Currency string `json:"currency"`
+	Amount   int    `json:"amount"`
+	Count    int    `json:"count"`
+}
+
+// Report は購入日（AcquiredAt）を基準にした支出の集計結果です。
+type Report struct {
+	From          string  `json:"from"`
+	To            string  `json:"to"`
+	Totals        []Total `json:"totals"`
+	ByMonth       []Total `json:"byMonth"`
+	BySeries      []Total `json:"bySeries"`
+	ByPublisher   []Total `json:"byPublisher"`
+	UnpricedCount int     `json:"unpricedCount"`
+}
+
+// BudgetStatus は当月の予算に対する支出状況です。
+type BudgetStatus struct {
+	Month     string `json:"month"`
+	Budget    int    `json:"budget"`
+	Currency  string `json:"currency"`
+	Spent     int    `json:"spent"`
+	Planned   int    `json:"planned"`
+	Remaining int    `json:"remaining"`
+	Exceeded  bool   `json:"exceeded"`
+}
+
+type Service struct {
+	userBooks repository.UserBookRepository
+	books     repository.BookRepository
+	series    repository.SeriesRepository
+}
+
+func NewService(userBooks repository.UserBookRepository, books repository.BookRepository, series repository.SeriesRepository) *Service {
+	return &Service{
+		userBooks: userBooks,
+		books:     books,
+		series:    series,
+	}
+}
+
+// Report は from〜to（YYYY-MM、両端を含む。空なら制限なし）の支出を月・シリーズ・出版社ごとに集計します。
+// 購入日のない所蔵は対象外です。
+func (s *Service) Report(userID, from, to string) Report {
+	report := Report{From: from, To: to}
+	items := make([]domain.UserBook, 0)
+	bookIDs := make([]string, 0)
+	for _, item := range s.userBooks.ListByUser(userID) {
+		month := acquiredMonth(item.AcquiredAt)
+		if month == "" || (from != "" && month < from) || (to != "" && month > to) {
+			continue
+		}
+		if item.PurchasePrice <= 0 {
+			report.UnpricedCount++
+			continue
+		}
+		items = append(items, item)
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	booksByID := make(map[string]domain.Book)
+	for _, book := range s.books.ListByIDs(bookIDs) {
+		booksByID[book.ID] = book
+	}
+	seriesNames := make(map[string]string)
+	for _, series := range s.series.List() {
+		seriesNames[series.ID] = series.Name
+	}
+
+	totals := newAggregator()
+	byMonth := newAggregator()
+	bySeries := newAggregator()
+	byPublisher := newAggregator()
+	for _, item := range items {
+		currency := currencyOf(item.Currency)
+		totals.add(currency, currency, currency, item.PurchasePrice)
+		month := acquiredMonth(item.AcquiredAt)
+		byMonth.add(month, month, currency, item.PurchasePrice)
+		seriesName := seriesNames[item.SeriesID]
+		if item.SeriesID == "" || seriesName == "" {
+			bySeries.add("", "シリーズなし", currency, item.PurchasePrice)
+		} else {
+			bySeries.add(item.SeriesID, seriesName, currency, item.PurchasePrice)
+		}
+		publisher := strings.TrimSpace(booksByID[item.BookID].Publisher)
+		if publisher == "" {
+			byPublisher.add("", "出版社不明", currency, item.PurchasePrice)
+		} else {
+			byPublisher.add(publisher, publisher, currency, item.PurchasePrice)
+		}
+	}
+	report.Totals = totals.sorted(byKey)
+	report.ByMonth = byMonth.sorted(byKey)
+	report.BySeries = bySeries.sorted(byAmount)
+	report.ByPublisher = byPublisher.sorted(byAmount)
+	return report
+}
+
+// Budget は当月の支出と「次に買う本」の予定額を予算と比較します。
+// 予定額は通貨が一致し、購入予定日が当月または未設定の項目を対象にします。
+func (s *Service) Budget(userID string, settings domain.ProfileSettings, planned []domain.NextToBuyManual, now time.Time) BudgetStatus {
+	month := now.Format("2006-01")
+	currency := currencyOf(settings.BudgetCurrency)
+	status := BudgetStatus{
+		Month:    month,
+		Budget:   settings.MonthlyBudget,
+		Currency: currency,
+	}
+	for _, item := range s.userBooks.ListByUser(userID) {
+		if acquiredMonth(item.AcquiredAt) == month && currencyOf(item.Currency) == currency {
+			status.Spent += item.PurchasePrice
+		}
+	}
+	for _, item := range planned {
+		if item.ExpectedPrice <= 0 || currencyOf(item.Currency) != currency {
+			continue
+		}
+		if item.TargetDate != "" && acquiredMonth(item.TargetDate) != month {
+			continue
+		}
+		status.Planned += item.ExpectedPrice
+	}
+	status.Remaining = status.Budget - status.Spent
+	status.Exceeded = status.Budget > 0 && status.Spent+status.Planned > status.Budget
+	return status
+}
+
+func acquiredMonth(date string) string {
+	if len(date) < 7 {
+		return ""
+	}
+	return date[:7]
+}
+
+func currencyOf(value string) string {
+	if value == "" {
+		return domain.DefaultCurrency
+	}
+	return value
+}
+
+type aggregator struct {
+	totals map[string]*Total
+}
+
+func newAggregator() *aggregator {
+	return &aggregator{totals: make(map[string]*Total)}
+}
+
+func (a *aggregator) add(key, label, currency string, amount int) {
+	id := key + "\x00" + currency
+	total, ok := a.totals[id]
+	if !ok {
+		total = &Total{Key: key, Label: label, Currency: currency}
+		a.totals[id] = total
+	}
+	total.Amount += amount
+	total.Count++
+}
+
+type order int
+
+const (
+	byKey order = iota
+	byAmount
+)
+
+func (a *aggregator) sorted(o order) []Total {
+	items := make([]Total, 0, len(a.totals))
+	for _, total := range a.totals {
+		items = append(items, *total)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if o == byAmount && items[i].Amount != items[j].Amount {
+			return items[i].Amount > items[j].Amount
+		}
+		if items[i].Key != items[j].Key {
+			return items[i].Key < items[j].Key
+		}
+		return items[i].Currency < items[j].Currency
+	})
+	return items
+}
@@ -5,6 +5,7 @@ import (
 
 	"book_manager/backend/internal/domain"
 	"book_manager/backend/internal/idgen"
+	"book_manager/backend/internal/isbn"
 	"book_manager/backend/internal/repository"
 )
 
@@ -15,11 +16,15 @@ type Service struct {
 }
 
 type UpdateInput struct {
-	Note         *string
-	AcquiredAt   *string
-	SeriesID     *string
-	VolumeNumber *int
-	SeriesSource *string
+	Note          *string
+	AcquiredAt    *string
+	SeriesID      *string
+	VolumeNumber  *int
+	VolumeLabel   *string
+	SeriesSource  *string
+	PurchasePrice *int
+	Currency      *string
+	Store         *string
 }
 
 func NewService(repo repository.UserBookRepository) *Service {
@@ -73,10 +78,26 @@ func (s *Service) Update(id string, input UpdateInput) (domain.UserBook, bool) {
 	}
 	if input.VolumeNumber != nil {
 		userBook.VolumeNumber = *input.VolumeNumber
+		userBook.VolumeLabel = ""
+	}
+	if input.VolumeLabel != nil {
+		// ラベルが指定された場合は整数の巻数もラベルから導出する
+		volume, _ := isbn.ParseVolume(*input.VolumeLabel)
+		userBook.VolumeLabel = volume.Label
+		userBook.VolumeNumber = isbn.VolumeNumberOf(volume)
 	}
 	if input.SeriesSource != nil {
 		userBook.SeriesSource = *input.SeriesSource
 	}
+	if input.PurchasePrice != nil {
+		userBook.PurchasePrice = *input.PurchasePrice
+	}
+	if input.Currency != nil {
+		userBook.Currency = *input.Currency
+	}
+	if input.Store != nil {
+		userBook.Store = *input.Store
+	}
 	if !s.repo.Update(userBook) {
 		return domain.UserBook{}, false
 	}
@@ -12,6 +12,7 @@ var ErrInvalidVisibility = errors.New("invalid visibility")
 var ErrUserNotFound = errors.New("user not found")
 var ErrEmailExists = errors.New("email already exists")
 var ErrUpdateFailed = errors.New("user update failed")
+var ErrInvalidBudget = errors.New("invalid budget")
 
 type Service struct {
 	users    repository.UserRepository
@@ -102,6 +103,31 @@ func (s *Service) IsUserIDTaken(userID string) bool {
 	return ok
 }
 
+// MarkEmailVerified はメールアドレスを確認済みとして記録します。
+// Firebase モードでは確認状態をトークンから受け取るため、認証ミドルウェアが同期に使います。
+func (s *Service) MarkEmailVerified(id string) error {
+	user, ok := s.users.FindByID(id)
+	if !ok {
+		return ErrUserNotFound
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	user.EmailVerified = true
+	if !s.users.Update(user) {
+		return ErrUpdateFailed
+	}
+	return nil
+}
+
+// GetByUserID は公開用の UserID からユーザーを返します。
+func (s *Service) GetByUserID(userID string) (domain.User, bool) {
+	if strings.TrimSpace(userID) == "" {
+		return domain.User{}, false
+	}
+	return s.users.FindByUserID(userID)
+}
+
 func (s *Service) FindByEmail(email string) (domain.User, bool) {
 	if strings.TrimSpace(email) == "" {
 		return domain.User{}, false
@@ -146,3 +172,18 @@ func (s *Service) UpdateSettings(userID, visibility string, openAIEnabled *bool,
 	s.settings.Upsert(current)
 	return current, nil
 }
+
+// SetMonthlyBudget は月の購入予算を設定します。amount が 0 の場合は予算なしになります。
+func (s *Service) SetMonthlyBudget(userID string, amount int, currency string) (domain.ProfileSettings, error) {
+	if amount < 0 {
+		return domain.ProfileSettings{}, ErrInvalidBudget
+	}
+	current := s.GetSettings(userID)
+	current.MonthlyBudget = amount
+	current.BudgetCurrency = currency
+	if amount > 0 && current.BudgetCurrency == "" {
+		current.BudgetCurrency = domain.DefaultCurrency
+	}
+	s.settings.Upsert(current)
+	return current, nil
+}
@@ -6,6 +6,7 @@ export const commonErrorMessages: Record<string, string> = {
   email_password_required: "メールアドレスとパスワードを入力してください。",
   email_not_found: "このメールアドレスは登録されていません。",
   invalid_password: "パスワードが間違っています。",
+  invalid_credentials: "メールアドレスまたはパスワードが間違っています。",
 };
 
 export const signupErrorMessages: Record<string, string> = {
@@ -23,4 +24,5 @@ export const signupErrorMessages: Record<string, string> = {
 
 export const loginErrorMessages: Record<string, string> = {
   ...commonErrorMessages,
+  too_many_attempts: "ログインの試行回数が多すぎます。しばらく待ってから再試行してください。",
 };